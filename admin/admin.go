@@ -127,6 +127,18 @@ func (a *admin) handler() hb.TagInterface {
 		controller = pathHome
 	}
 
+	if controller == pathDeadLetterManager {
+		return deadLetterManager(a.logger, a.store, a.layout).ToTag(a.response, a.request)
+	}
+
+	if controller == pathQueuePause {
+		return queuePause(a.logger, a.store).ToTag(a.response, a.request)
+	}
+
+	if controller == pathTaskQueueCancel {
+		return taskQueueCancel(a.logger, a.store).ToTag(a.response, a.request)
+	}
+
 	if controller == pathTaskQueueCreate {
 		return taskQueueCreate(a.logger, a.store).ToTag(a.response, a.request)
 	}
@@ -171,6 +183,10 @@ func (a *admin) handler() hb.TagInterface {
 		return taskDefinitionUpdate(a.logger, a.store).ToTag(a.response, a.request)
 	}
 
+	if controller == pathWorkerManager {
+		return workerManager(a.logger, a.store, a.layout).ToTag(a.response, a.request)
+	}
+
 	if controller == pathTaskQueueCreate {
 		return hb.Div().Child(hb.H1().HTML(controller))
 	}
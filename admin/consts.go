@@ -9,15 +9,22 @@ const keyEndpoint = contextKey("endpoint")
 const defaultFavicon = `data:image/x-icon;base64,AAABAAEAEBAQAAEABAAoAQAAFgAAACgAAAAQAAAAIAAAAAEABAAAAAAAgAAAAAAAAAAAAAAAEAAAAAAAAAAAAAAAmzKzAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABEQEAAQERAAEAAQABAAEAAQABAQEBEQABAAEREQEAAAERARARAREAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAD//wAA//8AAP//AAD//wAA//8AAP//AAD//wAAi6MAALu7AAC6owAAuC8AAIkjAAD//wAA//8AAP//AAD//wAA`
 
 const fieldParameters = "parameters"
+const fieldPriority = "priority"
 
 const fieldQueueID = "queue_id"
+const fieldQueueName = "queue_name"
 const fieldTaskID = "task_id"
 const fieldStatus = "status"
 const fieldTitle = "title"
 const fieldAlias = "alias"
 const fieldDescription = "description"
 const fieldDetails = "details"
+const fieldWorkerID = "worker_id"
+const fieldClaimedAt = "claimed_at"
+const fieldErrorMessage = "error_message"
 
+const fieldFilterBatchID = "filter_batch_id"
+const fieldFilterDeadLetterQueueName = "filter_dead_letter_queue_name"
 const fieldFilterQueueID = "filter_queue_id"
 const fieldFilterStatus = "filter_status"
 const fieldFilterName = "filter_name"
@@ -27,6 +34,11 @@ const fieldFilterTaskID = "filter_task_id"
 
 const pathHome = "home"
 
+const pathDeadLetterManager = "dead-letter-manager"
+
+const pathQueuePause = "queue-pause"
+
+const pathTaskQueueCancel = "task-queue-cancel"
 const pathTaskQueueCreate = "task-queue-create"
 const pathTaskQueueDelete = "task-queue-delete"
 const pathTaskQueueDetails = "task-queue-details"
@@ -42,6 +54,8 @@ const pathTaskDefinitionManager = "task-definition-manager"
 const pathTaskDefinitionUpdate = "task-definition-update"
 const pathTaskDefinitionDelete = "task-definition-delete"
 
+const pathWorkerManager = "worker-manager"
+
 const actionModalQueuedTaskFilterShow = "modal-queued-task-filter-show"
 
 // const actionModalQueuedTaskRequeueShow = "modal-queued-task-requeue-show"
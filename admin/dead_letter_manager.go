@@ -0,0 +1,337 @@
+package admin
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/dracory/cdn"
+	"github.com/dracory/hb"
+	"github.com/dracory/req"
+	"github.com/dracory/sb"
+	"github.com/dracory/taskstore"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+const actionDeadLetterPurge = "dead-letter-purge"
+const actionDeadLetterReplay = "dead-letter-replay"
+
+func deadLetterManager(logger slog.Logger, store taskstore.StoreInterface, layout Layout) *deadLetterManagerController {
+	return &deadLetterManagerController{
+		logger: logger,
+		store:  store,
+		layout: layout,
+	}
+}
+
+type deadLetterManagerController struct {
+	logger slog.Logger
+	store  taskstore.StoreInterface
+	layout Layout
+}
+
+func (c *deadLetterManagerController) ToTag(w http.ResponseWriter, r *http.Request) hb.TagInterface {
+	if r.Method == http.MethodPost {
+		return c.actionSubmitted(r)
+	}
+
+	data, errorMessage := c.prepareData(r)
+
+	c.layout.SetTitle("Dead Letter Queue | Zeppelin")
+
+	if errorMessage != "" {
+		c.layout.SetBody(hb.Div().
+			Class("alert alert-danger").
+			Text(errorMessage).ToHTML())
+
+		return hb.Raw(c.layout.Render(w, r))
+	}
+
+	htmxScript := `setTimeout(() => {
+		if (!window.htmx) {
+			let script = document.createElement('script');
+			document.head.appendChild(script);
+			script.type = 'text/javascript';
+			script.src = '` + cdn.Htmx_2_0_0() + `';
+		}
+	}, 1000);`
+
+	swalScript := `setTimeout(() => {
+		if (!window.Swal) {
+			let script = document.createElement('script');
+			document.head.appendChild(script);
+			script.type = 'text/javascript';
+			script.src = '` + cdn.Sweetalert2_11() + `';
+		}
+	}, 1000);`
+
+	c.layout.SetBody(c.page(&data).ToHTML())
+	c.layout.SetScripts([]string{htmxScript, swalScript})
+
+	return hb.Raw(c.layout.Render(w, r))
+}
+
+// actionSubmitted replays or purges the dead letters with the posted IDs
+func (c *deadLetterManagerController) actionSubmitted(r *http.Request) hb.TagInterface {
+	action := req.GetStringTrimmed(r, "action")
+	ids := lo.Compact(lo.Map(strings.Split(req.GetStringTrimmed(r, "ids"), ","), func(id string, _ int) string {
+		return strings.TrimSpace(id)
+	}))
+
+	if len(ids) < 1 {
+		return hb.Swal(hb.SwalOptions{Icon: "error", Title: "Error", Text: "No dead letters selected", Position: "top-right"})
+	}
+
+	var count int64
+	var err error
+	var message string
+
+	switch action {
+	case actionDeadLetterReplay:
+		count, err = c.store.DeadLetterReplay(context.Background(), ids)
+		message = cast.ToString(count) + " dead letter(s) successfully replayed."
+	case actionDeadLetterPurge:
+		count, err = c.store.DeadLetterPurge(context.Background(), ids)
+		message = cast.ToString(count) + " dead letter(s) successfully purged."
+	default:
+		return hb.Swal(hb.SwalOptions{Icon: "error", Title: "Error", Text: "Action not supported", Position: "top-right"})
+	}
+
+	if err != nil {
+		c.logger.Error("At deadLetterManagerController > actionSubmitted", "error", err.Error())
+		return hb.Swal(hb.SwalOptions{Icon: "error", Title: "Error", Text: err.Error(), Position: "top-right"})
+	}
+
+	return hb.Wrap().
+		Child(hb.Swal(hb.SwalOptions{Icon: "success", Title: "Success", Text: message, Position: "top-right"})).
+		Child(hb.Script(`setTimeout(function(){window.location.href = window.location.href}, 2000);`))
+}
+
+func (c *deadLetterManagerController) page(data *deadLetterManagerControllerData) hb.TagInterface {
+	adminHeader := adminHeader(c.store, &c.logger, data.request)
+	breadcrumbs := breadcrumbs(data.request, []Breadcrumb{
+		{
+			Name: "Dead Letter Queue",
+			URL:  url(data.request, pathDeadLetterManager, map[string]string{}),
+		},
+	})
+
+	ids := strings.Join(lo.Map(data.recordList, func(deadLetter taskstore.TaskQueueInterface, _ int) string {
+		return deadLetter.GetID()
+	}), ",")
+
+	buttonPurgeAll := hb.Button().
+		Class("btn btn-danger float-end ms-2").
+		Child(hb.I().Class("bi bi-trash me-2")).
+		HTML("Purge All").
+		HxPost(url(data.request, pathDeadLetterManager, map[string]string{
+			"action": actionDeadLetterPurge,
+			"ids":    ids,
+		})).
+		HxConfirm("Permanently delete all the listed dead letters?").
+		HxTarget("body").
+		HxSwap("beforeend")
+
+	buttonReplayAll := hb.Button().
+		Class("btn btn-primary float-end").
+		Child(hb.I().Class("bi bi-arrow-repeat me-2")).
+		HTML("Replay All").
+		HxPost(url(data.request, pathDeadLetterManager, map[string]string{
+			"action": actionDeadLetterReplay,
+			"ids":    ids,
+		})).
+		HxConfirm("Replay all the listed dead letters?").
+		HxTarget("body").
+		HxSwap("beforeend")
+
+	title := hb.Heading1().
+		HTML("Zeppelin. Dead Letter Queue").
+		ChildIf(len(data.recordList) > 0, buttonPurgeAll).
+		ChildIf(len(data.recordList) > 0, buttonReplayAll)
+
+	return hb.Div().
+		Class("container").
+		Child(breadcrumbs).
+		Child(hb.HR()).
+		Child(adminHeader).
+		Child(hb.HR()).
+		Child(title).
+		Child(c.tableFilter(data)).
+		Child(c.tableRecords(data))
+}
+
+func (c *deadLetterManagerController) tableFilter(data *deadLetterManagerControllerData) hb.TagInterface {
+	links := lo.Map(append([]string{""}, data.deadLetterQueueNames...), func(name string, _ int) hb.TagInterface {
+		return hb.Hyperlink().
+			Class("btn btn-sm me-2").
+			ClassIf(name == data.formDeadLetterQueueName, "btn-info text-white").
+			ClassIf(name != data.formDeadLetterQueueName, "btn-outline-info").
+			Text(lo.Ternary(name == "", "All", name)).
+			Href(url(data.request, pathDeadLetterManager, map[string]string{
+				fieldFilterDeadLetterQueueName: name,
+			}))
+	})
+
+	return hb.Div().
+		Class("card bg-light mb-3").
+		Child(hb.Div().
+			Class("card-body").
+			Child(hb.Span().Class("me-2").Text("Dead letter queue:")).
+			Children(links).
+			Child(hb.Span().
+				Class("ms-2").
+				Text("Showing " + cast.ToString(data.recordCount) + " dead letter(s)")))
+}
+
+func (c *deadLetterManagerController) tableRecords(data *deadLetterManagerControllerData) hb.TagInterface {
+	return hb.Table().
+		Class("table table-striped table-hover table-bordered").
+		Children([]hb.TagInterface{
+			hb.Thead().Children([]hb.TagInterface{
+				hb.TR().Children([]hb.TagInterface{
+					hb.TH().HTML("Name, Alias, Reference"),
+					hb.TH().HTML("Dead Letter Queue").Style("width: 1px;"),
+					hb.TH().HTML("Attempts").Style("width: 1px;"),
+					hb.TH().HTML("Dead Lettered").Style("width: 1px;"),
+					hb.TH().HTML("Actions"),
+				}),
+			}),
+			hb.Tbody().Children(lo.Map(data.recordList, func(deadLetter taskstore.TaskQueueInterface, _ int) hb.TagInterface {
+				task, taskExists := lo.Find(data.taskList, func(t taskstore.TaskDefinitionInterface) bool {
+					return t.GetID() == deadLetter.GetTaskID()
+				})
+
+				taskName := lo.IfF(taskExists, func() string { return task.GetTitle() }).Else("Unknown")
+				taskAlias := lo.IfF(taskExists, func() string { return task.GetAlias() }).Else("Unknown")
+
+				buttonDetails := hb.Button().
+					Class("btn btn-sm btn-info").
+					Style("margin-bottom: 2px; margin-left:2px; margin-right:2px;").
+					Child(hb.I().Class("bi bi-info-circle-fill")).
+					Title("See the details of the job run").
+					HxGet(url(data.request, pathTaskQueueDetails, map[string]string{
+						"queue_id": deadLetter.GetID(),
+					})).
+					HxTarget("body").
+					HxSwap("beforeend")
+
+				buttonReplay := hb.Button().
+					Class("btn btn-sm btn-primary").
+					Style("margin-bottom: 2px; margin-left:2px; margin-right:2px;").
+					Child(hb.I().Class("bi bi-arrow-repeat")).
+					Title("Replay this dead letter").
+					HxPost(url(data.request, pathDeadLetterManager, map[string]string{
+						"action": actionDeadLetterReplay,
+						"ids":    deadLetter.GetID(),
+					})).
+					HxTarget("body").
+					HxSwap("beforeend")
+
+				buttonPurge := hb.Button().
+					Class("btn btn-sm btn-danger").
+					Style("margin-bottom: 2px; margin-left:2px; margin-right:2px;").
+					Child(hb.I().Class("bi bi-trash")).
+					Title("Permanently delete this dead letter").
+					HxPost(url(data.request, pathDeadLetterManager, map[string]string{
+						"action": actionDeadLetterPurge,
+						"ids":    deadLetter.GetID(),
+					})).
+					HxConfirm("Permanently delete this dead letter?").
+					HxTarget("body").
+					HxSwap("beforeend")
+
+				return hb.TR().
+					// Name, Alias, Ref
+					Child(hb.TD().
+						Child(hb.Div().Text(taskName)).
+						Child(hb.Div().
+							Style("font-size: 11px;").
+							Text("Alias: ").
+							Text(taskAlias)).
+						Child(hb.Div().
+							Style("font-size: 11px;").
+							Text("Ref: ").
+							Text(deadLetter.GetID()))).
+
+					// Dead Letter Queue
+					Child(hb.TD().
+						Text(deadLetter.GetDeadLetterQueueName()).
+						Style("white-space: nowrap;")).
+
+					// Attempts
+					Child(hb.TD().
+						Text(cast.ToString(deadLetter.GetAttempts()))).
+
+					// Dead Lettered At
+					Child(hb.TD().
+						Child(hb.Div().Text(deadLetter.GetDeadLetteredAtCarbon().Format("d M Y"))).
+						Child(hb.Div().Text(deadLetter.GetDeadLetteredAtCarbon().ToTimeString())).
+						Style("white-space: nowrap; font-size: 13px;")).
+
+					// Actions
+					Child(hb.TD().
+						Style("text-align: center;").
+						Child(buttonDetails).
+						Child(buttonReplay).
+						Child(buttonPurge))
+			})),
+		})
+}
+
+func (c *deadLetterManagerController) prepareData(r *http.Request) (data deadLetterManagerControllerData, errorMessage string) {
+	var err error
+	data.request = r
+	data.formDeadLetterQueueName = req.GetStringTrimmed(r, fieldFilterDeadLetterQueueName)
+
+	query := taskstore.TaskQueueQuery().
+		SetLimit(100)
+
+	if data.formDeadLetterQueueName != "" {
+		query = query.SetDeadLetterQueueName(data.formDeadLetterQueueName)
+	}
+
+	data.recordList, err = c.store.DeadLetterList(context.Background(), query)
+
+	if err != nil {
+		c.logger.Error("At deadLetterManagerController > prepareData", "error", err.Error())
+		return data, "error retrieving dead letters"
+	}
+
+	data.recordCount, err = c.store.DeadLetterCount(context.Background(), query)
+
+	if err != nil {
+		c.logger.Error("At deadLetterManagerController > prepareData", "error", err.Error())
+		return data, "error retrieving dead letters"
+	}
+
+	data.taskList, err = c.store.TaskDefinitionList(context.Background(), taskstore.TaskDefinitionQuery().
+		SetOrderBy(taskstore.COLUMN_ALIAS).
+		SetSortOrder(sb.ASC).
+		SetOffset(0).
+		SetLimit(100))
+
+	if err != nil {
+		c.logger.Error("At deadLetterManagerController > prepareData", "error", err.Error())
+		return data, "error retrieving tasks"
+	}
+
+	data.deadLetterQueueNames = lo.Uniq(lo.FilterMap(data.taskList, func(task taskstore.TaskDefinitionInterface, _ int) (string, bool) {
+		return task.GetDeadLetterQueueName(), task.GetDeadLetterQueueName() != ""
+	}))
+
+	return data, ""
+}
+
+type deadLetterManagerControllerData struct {
+	request *http.Request
+
+	formDeadLetterQueueName string
+
+	recordList  []taskstore.TaskQueueInterface
+	recordCount int64
+
+	taskList             []taskstore.TaskDefinitionInterface
+	deadLetterQueueNames []string
+}
@@ -0,0 +1,145 @@
+package admin
+
+import (
+	"context"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dracory/taskstore"
+)
+
+func Test_deadLetterManager(t *testing.T) {
+	// Test deadLetterManager controller constructor with real SQLite store
+	store := setupTestStore(t)
+	layout := setupTestLayout(t)
+	logger := slog.Default()
+
+	controller := deadLetterManager(*logger, store, layout)
+
+	if controller == nil {
+		t.Error("deadLetterManager() should return a non-nil controller")
+	}
+	if controller.store == nil {
+		t.Error("deadLetterManager() should set store")
+	}
+	if controller.layout == nil {
+		t.Error("deadLetterManager() should set layout")
+	}
+}
+
+func setupTestDeadLetter(t *testing.T, store taskstore.StoreInterface) taskstore.TaskQueueInterface {
+	t.Helper()
+
+	task := taskstore.NewTaskDefinition().
+		SetAlias("TestDeadLetterTask").
+		SetTitle("Test Dead Letter Task").
+		SetDeadLetterQueueName("poison")
+	if err := store.TaskDefinitionCreate(context.Background(), task); err != nil {
+		t.Fatal(err)
+	}
+
+	deadLetter := taskstore.NewTaskQueue().
+		SetTaskID(task.GetID()).
+		SetStatus(taskstore.TaskQueueStatusFailed).
+		SetDeadLetterQueueName("poison")
+	if err := store.TaskQueueCreate(context.Background(), deadLetter); err != nil {
+		t.Fatal(err)
+	}
+
+	return deadLetter
+}
+
+func Test_deadLetterManagerController_list(t *testing.T) {
+	// Test the dead letters are listed
+	store := setupTestStore(t)
+	layout := &mockLayout{}
+	logger := slog.Default()
+
+	deadLetter := setupTestDeadLetter(t, store)
+
+	req := httptest.NewRequest("GET", "/?"+fieldFilterDeadLetterQueueName+"=poison", nil)
+	w := httptest.NewRecorder()
+
+	controller := deadLetterManager(*logger, store, layout)
+	controller.ToTag(w, req)
+
+	if !strings.Contains(layout.body, deadLetter.GetID()) {
+		t.Errorf("expected the dead letter to be listed, got %s", layout.body)
+	}
+	if !strings.Contains(layout.body, "Showing 1 dead letter(s)") {
+		t.Errorf("expected the dead letter count to be shown, got %s", layout.body)
+	}
+}
+
+func Test_deadLetterManagerController_replay(t *testing.T) {
+	// Test a dead letter is replayed
+	store := setupTestStore(t)
+	layout := &mockLayout{}
+	logger := slog.Default()
+
+	deadLetter := setupTestDeadLetter(t, store)
+
+	req := httptest.NewRequest("POST", "/?action="+actionDeadLetterReplay+"&ids="+deadLetter.GetID(), nil)
+	w := httptest.NewRecorder()
+
+	controller := deadLetterManager(*logger, store, layout)
+	html := controller.ToTag(w, req).ToHTML()
+
+	if !strings.Contains(html, "1 dead letter(s) successfully replayed.") {
+		t.Errorf("expected a success message, got %s", html)
+	}
+
+	queuedTask, err := store.TaskQueueFindByID(context.Background(), deadLetter.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queuedTask.GetStatus() != taskstore.TaskQueueStatusQueued {
+		t.Errorf("expected the replayed task to be queued, got %s", queuedTask.GetStatus())
+	}
+}
+
+func Test_deadLetterManagerController_purge(t *testing.T) {
+	// Test a dead letter is purged
+	store := setupTestStore(t)
+	layout := &mockLayout{}
+	logger := slog.Default()
+
+	deadLetter := setupTestDeadLetter(t, store)
+
+	req := httptest.NewRequest("POST", "/?action="+actionDeadLetterPurge+"&ids="+deadLetter.GetID(), nil)
+	w := httptest.NewRecorder()
+
+	controller := deadLetterManager(*logger, store, layout)
+	html := controller.ToTag(w, req).ToHTML()
+
+	if !strings.Contains(html, "1 dead letter(s) successfully purged.") {
+		t.Errorf("expected a success message, got %s", html)
+	}
+
+	count, err := store.DeadLetterCount(context.Background(), taskstore.TaskQueueQuery())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("expected no dead letters left, got %d", count)
+	}
+}
+
+func Test_deadLetterManagerController_no_ids(t *testing.T) {
+	// Test an action without dead letters is rejected
+	store := setupTestStore(t)
+	layout := &mockLayout{}
+	logger := slog.Default()
+
+	req := httptest.NewRequest("POST", "/?action="+actionDeadLetterReplay, nil)
+	w := httptest.NewRecorder()
+
+	controller := deadLetterManager(*logger, store, layout)
+	html := controller.ToTag(w, req).ToHTML()
+
+	if !strings.Contains(html, "No dead letters selected") {
+		t.Errorf("expected an error message, got %s", html)
+	}
+}
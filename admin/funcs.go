@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	urlpkg "net/url"
 
@@ -27,6 +28,14 @@ func adminHeader(store taskstore.StoreInterface, logger *slog.Logger, r *http.Re
 		HTML("Task Definitions").
 		Href(url(r, pathTaskDefinitionManager, nil)).
 		Class("nav-link")
+	linkDeadLetters := hb.Hyperlink().
+		HTML("Dead Letters").
+		Href(url(r, pathDeadLetterManager, nil)).
+		Class("nav-link")
+	linkWorkers := hb.Hyperlink().
+		HTML("Workers").
+		Href(url(r, pathWorkerManager, nil)).
+		Class("nav-link")
 
 	queueCount, err := store.TaskQueueCount(context.Background(), taskstore.TaskQueueQuery())
 
@@ -42,6 +51,21 @@ func adminHeader(store taskstore.StoreInterface, logger *slog.Logger, r *http.Re
 		taskCount = -1
 	}
 
+	deadLetterCount, err := store.DeadLetterCount(context.Background(), taskstore.TaskQueueQuery())
+
+	if err != nil {
+		logger.Error(err.Error())
+		deadLetterCount = -1
+	}
+
+	liveWorkers, err := store.WorkerListLive(context.Background())
+	workerCount := len(liveWorkers)
+
+	if err != nil {
+		logger.Error(err.Error())
+		workerCount = -1
+	}
+
 	ulNav := hb.NewUL().Class("nav  nav-pills justify-content-center")
 	ulNav.AddChild(hb.NewLI().Class("nav-item").Child(linkHome))
 
@@ -59,6 +83,20 @@ func adminHeader(store taskstore.StoreInterface, logger *slog.Logger, r *http.Re
 				Class("badge bg-secondary ms-2").
 				HTML(cast.ToString(taskCount)))))
 
+	ulNav.Child(hb.LI().
+		Class("nav-item").
+		Child(linkDeadLetters.
+			Child(hb.Span().
+				Class("badge bg-secondary ms-2").
+				HTML(cast.ToString(deadLetterCount)))))
+
+	ulNav.Child(hb.LI().
+		Class("nav-item").
+		Child(linkWorkers.
+			Child(hb.Span().
+				Class("badge bg-secondary ms-2").
+				HTML(cast.ToString(workerCount)))))
+
 	divCard := hb.NewDiv().Class("card card-default mt-3 mb-3")
 	divCardBody := hb.NewDiv().Class("card-body").Style("padding: 2px;")
 	return divCard.AddChild(divCardBody.AddChild(ulNav))
@@ -167,6 +205,16 @@ func isJSON(str string) bool {
 	return false
 }
 
+// isNullTime reports whether a datetime of the store is not set, i.e. it
+// is a zero time.Time or taskstore.NULL_DATETIME
+func isNullTime(t time.Time) bool {
+	nullTime, err := time.Parse(time.DateTime, taskstore.NULL_DATETIME)
+	if err != nil {
+		return t.IsZero()
+	}
+	return t.IsZero() || !t.After(nullTime)
+}
+
 // sortingIndicator returns the sorting indicator (up/down arrow) for a column
 // This is a standalone utility function for better reusability across controllers
 func sortingIndicator(columnName, sortByColumnName, sortOrder string) hb.TagInterface {
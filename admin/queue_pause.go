@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/dracory/hb"
+	"github.com/dracory/req"
+	"github.com/dracory/taskstore"
+)
+
+const actionQueuePause = "queue-pause"
+const actionQueueResume = "queue-resume"
+
+func queuePause(logger slog.Logger, store taskstore.StoreInterface) *queuePauseController {
+	return &queuePauseController{
+		logger: logger,
+		store:  store,
+	}
+}
+
+// queuePauseController pauses or resumes a whole queue
+type queuePauseController struct {
+	logger slog.Logger
+	store  taskstore.StoreInterface
+}
+
+func (c *queuePauseController) ToTag(w http.ResponseWriter, r *http.Request) hb.TagInterface {
+	data, err := c.prepareData(r)
+
+	if err != nil {
+		return hb.Swal(hb.SwalOptions{Icon: "error", Title: "Error", Text: err.Error(), Position: "top-right"})
+	}
+
+	if r.Method != http.MethodPost {
+		return hb.Swal(hb.SwalOptions{Icon: "error", Title: "Error", Text: "Method not allowed", Position: "top-right"})
+	}
+
+	return c.formSubmitted(data)
+}
+
+func (c *queuePauseController) formSubmitted(data queuePauseControllerData) hb.TagInterface {
+	var err error
+	var message string
+
+	if data.action == actionQueuePause {
+		err = c.store.QueuePause(context.Background(), data.queueName)
+		message = "Queue " + data.queueName + " successfully paused."
+	} else {
+		err = c.store.QueueResume(context.Background(), data.queueName)
+		message = "Queue " + data.queueName + " successfully resumed."
+	}
+
+	if err != nil {
+		c.logger.Error("At queuePauseController > formSubmitted", "error", err.Error())
+		return hb.Swal(hb.SwalOptions{Icon: "error", Title: "Error", Text: err.Error(), Position: "top-right"})
+	}
+
+	return hb.Wrap().
+		Child(hb.Swal(hb.SwalOptions{Icon: "success", Title: "Success", Text: message, Position: "top-right"})).
+		Child(hb.Script(`setTimeout(function(){window.location.href = window.location.href}, 2000);`))
+}
+
+func (c *queuePauseController) prepareData(r *http.Request) (data queuePauseControllerData, err error) {
+	data.request = r
+	data.action = req.GetStringTrimmed(r, "action")
+	data.queueName = req.GetStringTrimmed(r, fieldQueueName)
+
+	if data.queueName == "" {
+		return data, errors.New("queue_name is required")
+	}
+
+	if data.action != actionQueuePause && data.action != actionQueueResume {
+		return data, errors.New("action is not supported")
+	}
+
+	return data, nil
+}
+
+type queuePauseControllerData struct {
+	request   *http.Request
+	action    string
+	queueName string
+}
@@ -0,0 +1,77 @@
+package admin
+
+import (
+	"context"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_queuePause(t *testing.T) {
+	// Test queuePause controller constructor with real SQLite store
+	store := setupTestStore(t)
+	logger := slog.Default()
+
+	controller := queuePause(*logger, store)
+
+	if controller == nil {
+		t.Error("queuePause() should return a non-nil controller")
+	}
+	if controller.store == nil {
+		t.Error("queuePause() should set store")
+	}
+}
+
+func Test_queuePauseController_pause_and_resume(t *testing.T) {
+	// Test a queue is paused, then resumed
+	store := setupTestStore(t)
+	logger := slog.Default()
+
+	controller := queuePause(*logger, store)
+
+	req := httptest.NewRequest("POST", "/?action="+actionQueuePause+"&"+fieldQueueName+"=emails", nil)
+	html := controller.ToTag(httptest.NewRecorder(), req).ToHTML()
+
+	if !strings.Contains(html, "Queue emails successfully paused.") {
+		t.Errorf("expected a success message, got %s", html)
+	}
+
+	paused, err := store.QueueIsPaused(context.Background(), "emails")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !paused {
+		t.Error("expected the queue to be paused")
+	}
+
+	req = httptest.NewRequest("POST", "/?action="+actionQueueResume+"&"+fieldQueueName+"=emails", nil)
+	html = controller.ToTag(httptest.NewRecorder(), req).ToHTML()
+
+	if !strings.Contains(html, "Queue emails successfully resumed.") {
+		t.Errorf("expected a success message, got %s", html)
+	}
+
+	paused, err = store.QueueIsPaused(context.Background(), "emails")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if paused {
+		t.Error("expected the queue to be resumed")
+	}
+}
+
+func Test_queuePauseController_missing_queue_name(t *testing.T) {
+	// Test the queue name is required
+	store := setupTestStore(t)
+	logger := slog.Default()
+
+	controller := queuePause(*logger, store)
+
+	req := httptest.NewRequest("POST", "/?action="+actionQueuePause, nil)
+	html := controller.ToTag(httptest.NewRecorder(), req).ToHTML()
+
+	if !strings.Contains(html, "queue_name is required") {
+		t.Errorf("expected an error message, got %s", html)
+	}
+}
@@ -0,0 +1,195 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/dracory/bs"
+	"github.com/dracory/form"
+	"github.com/dracory/hb"
+	"github.com/dracory/req"
+	"github.com/dracory/taskstore"
+)
+
+func taskQueueCancel(logger slog.Logger, store taskstore.StoreInterface) *taskQueueCancelController {
+	return &taskQueueCancelController{
+		logger: logger,
+		store:  store,
+	}
+}
+
+type taskQueueCancelController struct {
+	logger slog.Logger
+	store  taskstore.StoreInterface
+}
+
+func (c *taskQueueCancelController) ToTag(w http.ResponseWriter, r *http.Request) hb.TagInterface {
+	data, err := c.prepareData(r)
+
+	if err != nil {
+		return hb.Swal(hb.SwalOptions{
+			Icon:              "error",
+			Title:             "Error",
+			Text:              err.Error(),
+			Position:          "top-right",
+			ShowCancelButton:  false,
+			ShowConfirmButton: false,
+		})
+	}
+
+	if r.Method == http.MethodPost {
+		return c.formSubmitted(data)
+	}
+
+	return c.modal(data)
+}
+
+func (c *taskQueueCancelController) formSubmitted(data taskQueueCancelControllerData) hb.TagInterface {
+	if err := c.store.TaskQueueCancel(context.Background(), data.queue.GetID()); err != nil {
+		c.logger.Error("At taskQueueCancelController > formSubmitted", "error", err.Error())
+		return hb.Swal(hb.SwalOptions{
+			Icon:              "error",
+			Title:             "Error",
+			Text:              err.Error(),
+			Position:          "top-right",
+			ShowCancelButton:  false,
+			ShowConfirmButton: false,
+		})
+	}
+
+	message := "Queued task successfully canceled."
+	if data.queue.IsRunning() {
+		message = "Cancellation requested. The worker stops the task shortly."
+	}
+
+	return hb.Wrap().
+		Child(hb.Swal(hb.SwalOptions{
+			Icon:              "success",
+			Title:             "Success",
+			Text:              message,
+			Position:          "top-right",
+			ShowCancelButton:  false,
+			ShowConfirmButton: false,
+		})).
+		Child(hb.Script(`setTimeout(function(){window.location.href = window.location.href}, 2000);`))
+}
+
+func (c *taskQueueCancelController) modal(data taskQueueCancelControllerData) *hb.Tag {
+	fieldWarning := form.NewField(form.FieldOptions{
+		Type: form.FORM_FIELD_TYPE_RAW,
+		Value: hb.Wrap().
+			Child(hb.Paragraph().
+				Child(hb.Text(`You are about to cancel this queued task:`))).
+			Child(hb.Paragraph().
+				Style("font-weight: bold;").
+				Child(hb.Text(`Ref. "`+data.queue.GetID()+`"`))).
+			ChildIf(data.queue.IsRunning(), hb.Paragraph().
+				Child(hb.Text(`The task is running. The worker processing it will be asked to stop.`))).
+			Child(hb.Paragraph().
+				Child(hb.Text(`Are you sure you want to proceed?`))).
+			ToHTML(),
+		Required: true,
+	})
+
+	fieldQueueID := form.NewField(form.FieldOptions{
+		Label:    "Queue ID",
+		Name:     fieldQueueID,
+		Type:     form.FORM_FIELD_TYPE_HIDDEN,
+		Value:    data.queueID,
+		Required: true,
+	})
+
+	formCancel := form.NewForm(form.FormOptions{
+		ID: "FormQueueCancel",
+		Fields: []form.FieldInterface{
+			fieldWarning,
+			fieldQueueID,
+		},
+	})
+
+	modalCloseScript := `document.getElementById('ModalQueueCancel').remove();document.getElementById('ModalBackdrop').remove();`
+	butonModalClose := hb.Button().Type("button").
+		Class("btn-close").
+		Data("bs-dismiss", "modal").
+		OnClick(modalCloseScript)
+
+	buttonClose := hb.Button().
+		Child(hb.I().Class("bi bi-chevron-left me-2")).
+		HTML("Close").
+		Class("btn btn-secondary float-start").
+		OnClick(modalCloseScript)
+
+	buttonCancelTask := hb.Button().
+		Child(hb.I().Class("bi bi-stop-circle me-2")).
+		HTML("Cancel Task").
+		Class("btn btn-warning float-end").
+		HxInclude(`#ModalQueueCancel`).
+		HxPost(url(data.request, pathTaskQueueCancel, nil)).
+		HxTarget("body").
+		HxSwap("beforeend")
+
+	modal := bs.Modal().
+		ID("ModalQueueCancel").
+		Class("fade show").
+		Style(`display:block;position:fixed;top:50%;left:50%;transform:translate(-50%,-50%);z-index:1051;`).
+		Children([]hb.TagInterface{
+			bs.ModalDialog().Children([]hb.TagInterface{
+				bs.ModalContent().Children([]hb.TagInterface{
+					bs.ModalHeader().Children([]hb.TagInterface{
+						hb.Heading5().
+							Text("Cancel Queued Task").
+							Style(`padding: 0px; margin: 0px;`),
+						butonModalClose,
+					}),
+
+					bs.ModalBody().
+						Child(formCancel.Build()),
+
+					bs.ModalFooter().
+						Style(`display:flex;justify-content:space-between;`).
+						Child(buttonClose).
+						Child(buttonCancelTask),
+				}),
+			}),
+		})
+
+	backdrop := hb.Div().
+		ID("ModalBackdrop").
+		Class("modal-backdrop fade show").
+		Style("display:block;")
+
+	return hb.Wrap().Children([]hb.TagInterface{
+		modal,
+		backdrop,
+	})
+}
+
+func (c *taskQueueCancelController) prepareData(r *http.Request) (data taskQueueCancelControllerData, err error) {
+	data.request = r
+
+	data.queueID = req.GetStringTrimmed(r, fieldQueueID)
+
+	if data.queueID == "" {
+		return data, errors.New("queue_id is required")
+	}
+
+	data.queue, err = c.store.TaskQueueFindByID(context.Background(), data.queueID)
+
+	if err != nil {
+		return data, err
+	}
+
+	if data.queue == nil {
+		return data, errors.New("queue not found")
+	}
+
+	return data, nil
+}
+
+type taskQueueCancelControllerData struct {
+	request *http.Request
+	queueID string
+	queue   taskstore.TaskQueueInterface
+}
@@ -0,0 +1,90 @@
+package admin
+
+import (
+	"context"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dracory/taskstore"
+)
+
+func Test_taskQueueCancel(t *testing.T) {
+	// Test taskQueueCancel controller constructor with real SQLite store
+	store := setupTestStore(t)
+	logger := slog.Default()
+
+	controller := taskQueueCancel(*logger, store)
+
+	if controller == nil {
+		t.Error("taskQueueCancel() should return a non-nil controller")
+	}
+	if controller.store == nil {
+		t.Error("taskQueueCancel() should set store")
+	}
+}
+
+func Test_taskQueueCancelController_modal(t *testing.T) {
+	// Test the confirmation modal is shown for a running task
+	store := setupTestStore(t)
+	logger := slog.Default()
+
+	queuedTask := taskstore.NewTaskQueue().
+		SetTaskID("TASK_RUNNING").
+		SetStatus(taskstore.TaskQueueStatusRunning)
+	if err := store.TaskQueueCreate(context.Background(), queuedTask); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("GET", "/?"+fieldQueueID+"="+queuedTask.GetID(), nil)
+	html := taskQueueCancel(*logger, store).ToTag(httptest.NewRecorder(), req).ToHTML()
+
+	if !strings.Contains(html, "ModalQueueCancel") {
+		t.Errorf("expected the cancel modal, got %s", html)
+	}
+	if !strings.Contains(html, "The task is running") {
+		t.Errorf("expected a notice for the running task, got %s", html)
+	}
+}
+
+func Test_taskQueueCancelController_submit(t *testing.T) {
+	// Test a queued task is canceled on submit
+	store := setupTestStore(t)
+	logger := slog.Default()
+
+	queuedTask := taskstore.NewTaskQueue().
+		SetTaskID("TASK_QUEUED").
+		SetStatus(taskstore.TaskQueueStatusQueued)
+	if err := store.TaskQueueCreate(context.Background(), queuedTask); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("POST", "/?"+fieldQueueID+"="+queuedTask.GetID(), nil)
+	html := taskQueueCancel(*logger, store).ToTag(httptest.NewRecorder(), req).ToHTML()
+
+	if !strings.Contains(html, "Queued task successfully canceled.") {
+		t.Errorf("expected a success message, got %s", html)
+	}
+
+	dbTask, err := store.TaskQueueFindByID(context.Background(), queuedTask.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dbTask.IsCanceled() {
+		t.Errorf("expected the task to be canceled, got %s", dbTask.GetStatus())
+	}
+}
+
+func Test_taskQueueCancelController_missing_queue_id(t *testing.T) {
+	// Test the queue id is required
+	store := setupTestStore(t)
+	logger := slog.Default()
+
+	req := httptest.NewRequest("GET", "/", nil)
+	html := taskQueueCancel(*logger, store).ToTag(httptest.NewRecorder(), req).ToHTML()
+
+	if !strings.Contains(html, "queue_id is required") {
+		t.Errorf("expected an error message, got %s", html)
+	}
+}
@@ -75,7 +75,17 @@ func (c *taskQueueCreateController) formSubmitted(data *taskQueueCreateControlle
 
 	taskParametersMap := cast.ToStringMap(taskParametersAny)
 
-	_, err = c.store.TaskDefinitionEnqueueByAlias(context.Background(), taskstore.DefaultQueueName, task.GetAlias(), taskParametersMap)
+	options := taskstore.EnqueueOptions{}
+
+	if data.formPriority != "" {
+		priority, err := cast.ToIntE(data.formPriority)
+		if err != nil {
+			return hb.Swal(hb.SwalOptions{Icon: "error", Title: "Error", Text: "Priority must be a whole number", Position: "top-right"})
+		}
+		options.Priority = &priority
+	}
+
+	_, err = c.store.TaskDefinitionEnqueueByAliasWithOptions(context.Background(), taskstore.DefaultQueueName, task.GetAlias(), taskParametersMap, options)
 
 	if err != nil {
 		c.logger.Error("At queueCreateController > formSubmitted", "error", err.Error())
@@ -127,10 +137,19 @@ func (c *taskQueueCreateController) modalQueueCreate(data *taskQueueCreateContro
 		}(),
 	})
 
+	fieldPriority := form.NewField(form.FieldOptions{
+		Label: "Priority",
+		Name:  fieldPriority,
+		Type:  form.FORM_FIELD_TYPE_NUMBER,
+		Value: data.formPriority,
+		Help:  "Tasks with a higher priority are processed first. Leave empty to use the priority of the task.",
+	})
+
 	formCreate := form.NewForm(form.FormOptions{
 		ID: formID,
 		Fields: []form.FieldInterface{
 			fieldTaskID,
+			fieldPriority,
 			fieldParamSize,
 			fieldParam,
 		},
@@ -197,6 +216,7 @@ func (c *taskQueueCreateController) modalQueueCreate(data *taskQueueCreateContro
 func (c *taskQueueCreateController) prepareData(r *http.Request) (data taskQueueCreateControllerData, err error) {
 	data.request = r
 	data.formParameters = req.GetStringTrimmed(r, fieldParameters)
+	data.formPriority = req.GetStringTrimmed(r, fieldPriority)
 	data.formStatus = req.GetStringTrimmed(r, "status")
 	data.formTaskID = req.GetStringTrimmed(r, fieldTaskID)
 
@@ -217,5 +237,6 @@ type taskQueueCreateControllerData struct {
 
 	formTaskID     string
 	formParameters string
+	formPriority   string
 	formStatus     string
 }
@@ -1,9 +1,14 @@
 package admin
 
 import (
+	"context"
 	"log/slog"
 	"net/http/httptest"
+	neturl "net/url"
+	"strings"
 	"testing"
+
+	"github.com/dracory/taskstore"
 )
 
 func Test_taskQueueCreate(t *testing.T) {
@@ -78,3 +83,39 @@ func Test_taskQueueCreateController_with_real_store(t *testing.T) {
 		t.Error("taskQueueCreateController store should not be nil")
 	}
 }
+
+func Test_taskQueueCreateController_formSubmitted_with_priority(t *testing.T) {
+	// Test the queued task is created with the priority from the form
+	store := setupTestStore(t)
+	logger := slog.Default()
+
+	task := taskstore.NewTaskDefinition().
+		SetAlias("TestPriorityTask").
+		SetTitle("Test Priority Task")
+	if err := store.TaskDefinitionCreate(context.Background(), task); err != nil {
+		t.Fatal(err)
+	}
+
+	form := neturl.Values{}
+	form.Set(fieldTaskID, task.GetID())
+	form.Set(fieldParameters, "{}")
+	form.Set(fieldPriority, "7")
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+
+	controller := taskQueueCreate(*logger, store)
+	controller.ToTag(w, req)
+
+	queuedTasks, err := store.TaskQueueList(context.Background(), taskstore.TaskQueueQuery().SetTaskID(task.GetID()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queuedTasks) != 1 {
+		t.Fatalf("expected 1 queued task, got %d", len(queuedTasks))
+	}
+	if queuedTasks[0].GetPriority() != 7 {
+		t.Errorf("expected priority 7, got %d", queuedTasks[0].GetPriority())
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/dracory/hb"
 	"github.com/dracory/req"
 	"github.com/dracory/taskstore"
+	"github.com/samber/lo"
 )
 
 func taskQueueDetails(logger slog.Logger, store taskstore.StoreInterface) *taskQueueDetailsController {
@@ -54,6 +55,37 @@ func (c *taskQueueDetailsController) modal(data taskQueueDetailsControllerData)
 		Required: true,
 	})
 
+	claimedAt := lo.IfF(!isNullTime(data.queue.GetClaimedAt()), func() string {
+		return data.queue.GetClaimedAtCarbon().Format("d M Y H:i:s")
+	}).Else("-")
+	workerID := lo.Ternary(data.queue.GetWorkerID() != "", data.queue.GetWorkerID(), "-")
+
+	fieldWorker := form.NewField(form.FieldOptions{
+		Label:    "Worker ID",
+		Name:     fieldWorkerID,
+		Type:     form.FORM_FIELD_TYPE_STRING,
+		Value:    workerID,
+		Readonly: true,
+	})
+
+	fieldClaimed := form.NewField(form.FieldOptions{
+		Label:    "Claimed At",
+		Name:     fieldClaimedAt,
+		Type:     form.FORM_FIELD_TYPE_STRING,
+		Value:    claimedAt,
+		Readonly: true,
+	})
+
+	errorMessage := lo.Ternary(data.queue.GetErrorMessage() != "", data.queue.GetErrorMessage(), "-")
+
+	fieldError := form.NewField(form.FieldOptions{
+		Label:    "Error Message",
+		Name:     fieldErrorMessage,
+		Type:     form.FORM_FIELD_TYPE_STRING,
+		Value:    errorMessage,
+		Readonly: true,
+	})
+
 	fieldDetail := form.NewField(form.FieldOptions{
 		Label:    "Queued Task Details",
 		Name:     fieldDetails,
@@ -70,6 +102,9 @@ func (c *taskQueueDetailsController) modal(data taskQueueDetailsControllerData)
 	formUpdate := form.NewForm(form.FormOptions{
 		ID: formID,
 		Fields: []form.FieldInterface{
+			fieldWorker,
+			fieldClaimed,
+			fieldError,
 			fieldDetailSize,
 			fieldDetail,
 			fieldQueueID,
@@ -1,9 +1,14 @@
 package admin
 
 import (
+	"context"
 	"log/slog"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/dracory/taskstore"
 )
 
 func Test_taskQueueDetails(t *testing.T) {
@@ -21,6 +26,59 @@ func Test_taskQueueDetails(t *testing.T) {
 	}
 }
 
+func Test_taskQueueDetailsController_modal(t *testing.T) {
+	// Test the modal shows the worker which claimed the task
+	store := setupTestStore(t)
+	logger := slog.Default()
+
+	queuedTask := taskstore.NewTaskQueue().SetTaskID("TASK_CLAIMED")
+	if err := store.TaskQueueCreate(context.Background(), queuedTask); err != nil {
+		t.Fatal(err)
+	}
+	ctx := taskstore.ContextWithWorkerID(context.Background(), "worker-42")
+	if _, err := store.TaskQueueClaimNext(ctx, taskstore.DefaultQueueName); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("GET", "/?"+fieldQueueID+"="+queuedTask.GetID(), nil)
+	html := taskQueueDetails(*logger, store).ToTag(httptest.NewRecorder(), req).ToHTML()
+
+	if !strings.Contains(html, "ModalQueueDetails") {
+		t.Errorf("expected the details modal, got %s", html)
+	}
+	if !strings.Contains(html, "worker-42") {
+		t.Errorf("expected the worker ID, got %s", html)
+	}
+	if !strings.Contains(html, "Claimed At") {
+		t.Errorf("expected the claimed at field, got %s", html)
+	}
+}
+
+func Test_taskQueueDetailsController_modal_unclaimed(t *testing.T) {
+	// Test the modal does not show a claimed at date for an unclaimed task,
+	// whose claimed at is NULL_DATETIME (the column default)
+	store := setupTestStore(t)
+	logger := slog.Default()
+
+	nullTime, err := time.Parse(time.DateTime, taskstore.NULL_DATETIME)
+	if err != nil {
+		t.Fatal(err)
+	}
+	queuedTask := taskstore.NewTaskQueue().
+		SetTaskID("TASK_UNCLAIMED").
+		SetClaimedAt(nullTime)
+	if err := store.TaskQueueCreate(context.Background(), queuedTask); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("GET", "/?"+fieldQueueID+"="+queuedTask.GetID(), nil)
+	html := taskQueueDetails(*logger, store).ToTag(httptest.NewRecorder(), req).ToHTML()
+
+	if strings.Contains(html, "0002") {
+		t.Errorf("expected no claimed at date, got %s", html)
+	}
+}
+
 func Test_taskQueueDetailsController_struct_fields(t *testing.T) {
 	// Test taskQueueDetailsController struct fields
 	store := setupTestStore(t)
@@ -107,6 +107,14 @@ func (*taskQueueManagerController) onModalRecordFilterShow(data *taskQueueManage
 		Help:  `Find queue by reference number (ID).`,
 	})
 
+	fieldBatchID := form.NewField(form.FieldOptions{
+		Label: "Batch ID",
+		Name:  fieldFilterBatchID,
+		Type:  form.FORM_FIELD_TYPE_STRING,
+		Value: data.formBatchID,
+		Help:  `Find the queued tasks of a batch, and its progress.`,
+	})
+
 	filterForm := form.NewForm(form.FormOptions{
 		ID:        "FormFilters",
 		Method:    http.MethodGet,
@@ -175,6 +183,7 @@ func (*taskQueueManagerController) onModalRecordFilterShow(data *taskQueueManage
 				Help:  `Filter by creation date.`,
 			}),
 			fieldQueueID,
+			fieldBatchID,
 			form.NewField(form.FieldOptions{
 				Label: "Path",
 				Name:  "path",
@@ -248,9 +257,99 @@ func (controller *taskQueueManagerController) page(data *taskQueueManagerControl
 		Child(adminHeader).
 		Child(hb.HR()).
 		Child(title).
+		Child(controller.queues(data)).
+		ChildIf(data.batch != nil, controller.batchProgress(data)).
 		Child(controller.tableRecords(data))
 }
 
+// queues shows the known queues, with a toggle to pause or resume each
+func (controller *taskQueueManagerController) queues(data *taskQueueManagerControllerData) hb.TagInterface {
+	queueNames := []string{taskstore.DefaultQueueName}
+	queueNames = append(queueNames, data.pausedQueueNames...)
+	queueNames = append(queueNames, lo.Map(data.recordList, func(queuedTask taskstore.TaskQueueInterface, _ int) string {
+		return queuedTask.GetQueueName()
+	})...)
+	queueNames = lo.Uniq(lo.Compact(queueNames))
+
+	items := lo.Map(queueNames, func(queueName string, _ int) hb.TagInterface {
+		isPaused := lo.Contains(data.pausedQueueNames, queueName)
+
+		status := hb.Span().
+			Class("badge ms-1").
+			ClassIf(isPaused, "bg-warning text-dark").
+			ClassIf(!isPaused, "bg-success").
+			Text(lo.Ternary(isPaused, taskstore.QueueStatusPaused, taskstore.QueueStatusActive))
+
+		buttonToggle := hb.Button().
+			Class("btn btn-sm ms-1").
+			ClassIf(isPaused, "btn-success").
+			ClassIf(!isPaused, "btn-warning").
+			Child(hb.I().Class(lo.Ternary(isPaused, "bi bi-play-fill", "bi bi-pause-fill"))).
+			Title(lo.Ternary(isPaused, "Resume queue", "Pause queue")).
+			HxPost(url(data.request, pathQueuePause, map[string]string{
+				"action":       lo.Ternary(isPaused, actionQueueResume, actionQueuePause),
+				fieldQueueName: queueName,
+			})).
+			HxTarget("body").
+			HxSwap("beforeend")
+
+		return hb.Span().
+			Class("me-3 text-nowrap").
+			Text(queueName).
+			Child(status).
+			Child(buttonToggle)
+	})
+
+	return hb.Div().
+		Class("card mb-3").
+		Child(hb.Div().
+			Class("card-body").
+			Child(hb.Span().Class("me-2").Text("Queues:")).
+			Children(items))
+}
+
+func (controller *taskQueueManagerController) batchProgress(data *taskQueueManagerControllerData) hb.TagInterface {
+	if data.batch == nil {
+		return nil
+	}
+
+	batch := data.batch
+
+	percent := func(count int) string {
+		if batch.GetTotalCount() < 1 {
+			return "0%"
+		}
+		return cast.ToString(count*100/batch.GetTotalCount()) + "%"
+	}
+
+	finished := batch.GetSucceededCount() + batch.GetFailedCount()
+
+	progress := hb.Div().
+		Class("progress mb-2").
+		Child(hb.Div().
+			Class("progress-bar bg-success").
+			Style("width: " + percent(batch.GetSucceededCount()) + ";").
+			Title(cast.ToString(batch.GetSucceededCount()) + " succeeded")).
+		Child(hb.Div().
+			Class("progress-bar bg-danger").
+			Style("width: " + percent(batch.GetFailedCount()) + ";").
+			Title(cast.ToString(batch.GetFailedCount()) + " failed"))
+
+	summary := hb.Div().
+		Text("Batch " + batch.GetID() + ": " + batch.GetStatus() + ". ").
+		Text(cast.ToString(finished) + " of " + cast.ToString(batch.GetTotalCount()) + " tasks finished").
+		Text(" (" + cast.ToString(batch.GetSucceededCount()) + " succeeded, ").
+		Text(cast.ToString(batch.GetFailedCount()) + " failed, ").
+		Text(cast.ToString(batch.GetPendingCount()) + " pending)")
+
+	return hb.Div().
+		Class("card mb-3").
+		Child(hb.Div().
+			Class("card-body").
+			Child(progress).
+			Child(summary))
+}
+
 func (controller *taskQueueManagerController) tableRecords(data *taskQueueManagerControllerData) hb.TagInterface {
 	table := hb.Table().
 		Class("table table-striped table-hover table-bordered").
@@ -302,6 +401,19 @@ func (controller *taskQueueManagerController) tableRecords(data *taskQueueManage
 					HxTarget("body").
 					HxSwap("beforeend")
 
+				buttonCancel := hb.Button().
+					Class("btn btn-sm btn-warning").
+					Style("margin-bottom: 2px; margin-left:2px; margin-right:2px;").
+					Child(hb.I().Class("bi bi-stop-circle")).
+					Title("Cancel this task").
+					HxGet(url(data.request, pathTaskQueueCancel, map[string]string{
+						"queue_id": queuedTask.GetID(),
+					})).
+					HxTarget("body").
+					HxSwap("beforeend")
+
+				isCancelable := queuedTask.IsQueued() || queuedTask.IsRunning() || queuedTask.IsPaused()
+
 				buttonParameters := hb.Button().
 					Class("btn btn-sm btn-info").
 					Style("margin-bottom: 2px; margin-left:2px; margin-right:2px;").
@@ -373,6 +485,7 @@ func (controller *taskQueueManagerController) tableRecords(data *taskQueueManage
 					StyleIf(queuedTask.IsRunning(), `color:silver;`).
 					StyleIf(queuedTask.IsQueued(), `color:blue;`).
 					StyleIf(queuedTask.IsFailed(), `color:red;`).
+					StyleIf(queuedTask.IsCanceled(), `color:orange;`).
 					HTML(queuedTask.GetStatus())
 
 				return hb.TR().
@@ -386,7 +499,15 @@ func (controller *taskQueueManagerController) tableRecords(data *taskQueueManage
 						Child(hb.Div().
 							Style("font-size: 11px;").
 							Text("Ref: ").
-							Text(queuedTask.GetID()))).
+							Text(queuedTask.GetID())).
+						ChildIf(queuedTask.GetBatchID() != "", hb.Div().
+							Style("font-size: 11px;").
+							Text("Batch: ").
+							Child(hb.Hyperlink().
+								Text(queuedTask.GetBatchID()).
+								Href(url(data.request, pathTaskQueueManager, map[string]string{
+									fieldFilterBatchID: queuedTask.GetBatchID(),
+								}))))).
 
 					// Status
 					Child(hb.TD().
@@ -422,6 +543,7 @@ func (controller *taskQueueManagerController) tableRecords(data *taskQueueManage
 						Child(buttonDetails).
 						Child(buttonAddToQueue).
 						Child(buttonRestart).
+						ChildIf(isCancelable, buttonCancel).
 						Child(buttonDelete))
 			})),
 		})
@@ -443,15 +565,16 @@ func (controller *taskQueueManagerController) sortableColumnLabel(data *taskQueu
 	}
 
 	link := url(data.request, pathTaskQueueManager, map[string]string{
-		"controller":     pathTaskQueueManager,
-		"page":           "0",
-		"by":             columnName,
-		"sort":           direction,
-		"date_from":      data.formCreatedFrom,
-		"date_to":        data.formCreatedTo,
-		"status":         data.formStatus,
-		"filter_task_id": data.formTaskID,
-		"queue_id":       data.formQueueID,
+		"controller":       pathTaskQueueManager,
+		"page":             "0",
+		"by":               columnName,
+		"sort":             direction,
+		"date_from":        data.formCreatedFrom,
+		"date_to":          data.formCreatedTo,
+		"status":           data.formStatus,
+		"filter_task_id":   data.formTaskID,
+		"queue_id":         data.formQueueID,
+		fieldFilterBatchID: data.formBatchID,
 	})
 	return hb.Hyperlink().
 		HTML(tableLabel).
@@ -482,12 +605,13 @@ func (controller *taskQueueManagerController) tableFilter(data *taskQueueManager
 		Child(hb.I().Class("bi bi-filter me-2")).
 		Text("Filters").
 		HxPost(url(data.request, pathTaskQueueManager, map[string]string{
-			"action":       actionModalQueueFilterShow,
-			"name":         data.formName,
-			"status":       data.formStatus,
-			"queue_id":     data.formQueueID,
-			"created_from": data.formCreatedFrom,
-			"created_to":   data.formCreatedTo,
+			"action":           actionModalQueueFilterShow,
+			"name":             data.formName,
+			"status":           data.formStatus,
+			"queue_id":         data.formQueueID,
+			"created_from":     data.formCreatedFrom,
+			"created_to":       data.formCreatedTo,
+			fieldFilterBatchID: data.formBatchID,
 		})).
 		HxTarget("body").
 		HxSwap("beforeend")
@@ -510,6 +634,10 @@ func (controller *taskQueueManagerController) tableFilter(data *taskQueueManager
 		description = append(description, hb.Span().Text("and queue id: "+data.formQueueID).ToHTML())
 	}
 
+	if data.formBatchID != "" {
+		description = append(description, hb.Span().Text("and batch id: "+data.formBatchID).ToHTML())
+	}
+
 	// 	if data.formTaskID != "" {
 
 	if data.formCreatedFrom != "" && data.formCreatedTo != "" {
@@ -538,12 +666,13 @@ func (controller *taskQueueManagerController) tablePagination(
 	perPage int,
 ) hb.TagInterface {
 	url := url(data.request, pathTaskQueueManager, map[string]string{
-		"status":       data.formStatus,
-		"name":         data.formName,
-		"created_from": data.formCreatedFrom,
-		"created_to":   data.formCreatedTo,
-		"by":           data.sortBy,
-		"order":        data.sortOrder,
+		"status":           data.formStatus,
+		"name":             data.formName,
+		"created_from":     data.formCreatedFrom,
+		"created_to":       data.formCreatedTo,
+		"by":               data.sortBy,
+		"order":            data.sortOrder,
+		fieldFilterBatchID: data.formBatchID,
 	})
 
 	url = lo.Ternary(strings.Contains(url, "?"), url+"&page=", url+"?page=") // page must be last
@@ -579,6 +708,7 @@ func (controller *taskQueueManagerController) prepareData(r *http.Request) (data
 	data.formName = req.GetStringTrimmed(r, fieldFilterName)
 	data.formQueueID = req.GetStringTrimmed(r, fieldFilterQueueID)
 	data.formStatus = req.GetStringTrimmed(r, fieldFilterStatus)
+	data.formBatchID = req.GetStringTrimmed(r, fieldFilterBatchID)
 
 	data.recordList, data.recordCount, err = controller.fetchRecordList(&data)
 
@@ -587,6 +717,22 @@ func (controller *taskQueueManagerController) prepareData(r *http.Request) (data
 		return data, "error retrieving web queues"
 	}
 
+	if data.formBatchID != "" {
+		data.batch, err = controller.store.BatchFindByID(context.Background(), data.formBatchID)
+
+		if err != nil {
+			controller.logger.Error("At queueManagerController > prepareData", "error", err.Error())
+			return data, "error retrieving batch"
+		}
+	}
+
+	data.pausedQueueNames, err = controller.store.QueuePausedList(context.Background())
+
+	if err != nil {
+		controller.logger.Error("At queueManagerController > prepareData", "error", err.Error())
+		return data, "error retrieving paused queues"
+	}
+
 	data.taskList, err = controller.store.TaskDefinitionList(context.Background(), taskstore.TaskDefinitionQuery().
 		SetOrderBy(taskstore.COLUMN_ALIAS).
 		SetSortOrder(sb.ASC).
@@ -622,6 +768,10 @@ func (controller *taskQueueManagerController) fetchRecordList(data *taskQueueMan
 		query = query.SetStatus(data.formStatus)
 	}
 
+	if data.formBatchID != "" {
+		query = query.SetBatchID(data.formBatchID)
+	}
+
 	recordList, err := controller.store.TaskQueueList(context.Background(), query)
 
 	if err != nil {
@@ -653,10 +803,14 @@ type taskQueueManagerControllerData struct {
 	formCreatedTo   string
 	formQueueID     string
 	formTaskID      string
+	formBatchID     string
 
 	recordList  []taskstore.TaskQueueInterface
 	recordCount int64
 
 	queueID  string
 	taskList []taskstore.TaskDefinitionInterface
+	batch    taskstore.BatchInterface
+
+	pausedQueueNames []string
 }
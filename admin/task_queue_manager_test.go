@@ -1,9 +1,13 @@
 package admin
 
 import (
+	"context"
 	"log/slog"
 	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/dracory/taskstore"
 )
 
 func Test_taskQueueManager(t *testing.T) {
@@ -74,3 +78,63 @@ func Test_taskQueueManagerControllerData_nil_request(t *testing.T) {
 		t.Error("taskQueueManagerControllerData request should be nil")
 	}
 }
+
+func Test_taskQueueManagerController_batch_progress(t *testing.T) {
+	// Test the progress of the batch is shown when filtering by batch
+	store := setupTestStore(t)
+	layout := &mockLayout{}
+	logger := slog.Default()
+
+	task := taskstore.NewTaskDefinition().
+		SetAlias("TestBatchTask").
+		SetTitle("Test Batch Task")
+	if err := store.TaskDefinitionCreate(context.Background(), task); err != nil {
+		t.Fatal(err)
+	}
+
+	batch := taskstore.NewBatch()
+	queuedTasks, err := store.BatchEnqueue(context.Background(), batch, []taskstore.BatchTask{
+		{Alias: task.GetAlias()},
+		{Alias: task.GetAlias()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.(*taskstore.Store).TaskQueueSuccess(context.Background(), queuedTasks[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("GET", "/?"+fieldFilterBatchID+"="+batch.GetID(), nil)
+	w := httptest.NewRecorder()
+
+	controller := taskQueueManager(*logger, store, layout)
+	controller.ToTag(w, req)
+
+	if !strings.Contains(layout.body, "1 of 2 tasks finished") {
+		t.Errorf("expected the batch progress to be shown, got %s", layout.body)
+	}
+}
+
+func Test_taskQueueManagerController_paused_queues(t *testing.T) {
+	// Test the paused queues are shown with a resume toggle
+	store := setupTestStore(t)
+	layout := &mockLayout{}
+	logger := slog.Default()
+
+	if err := store.QueuePause(context.Background(), "emails"); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+
+	controller := taskQueueManager(*logger, store, layout)
+	controller.ToTag(w, req)
+
+	if !strings.Contains(layout.body, "emails") || !strings.Contains(layout.body, "Resume queue") {
+		t.Errorf("expected the paused queue to be shown with a resume toggle, got %s", layout.body)
+	}
+	if !strings.Contains(layout.body, "Pause queue") {
+		t.Errorf("expected the default queue to be shown with a pause toggle, got %s", layout.body)
+	}
+}
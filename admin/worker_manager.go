@@ -0,0 +1,252 @@
+package admin
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/dracory/cdn"
+	"github.com/dracory/hb"
+	"github.com/dracory/req"
+	"github.com/dracory/taskstore"
+	"github.com/samber/lo"
+	"github.com/spf13/cast"
+)
+
+const actionWorkerDeregister = "worker-deregister"
+
+func workerManager(logger slog.Logger, store taskstore.StoreInterface, layout Layout) *workerManagerController {
+	return &workerManagerController{
+		logger: logger,
+		store:  store,
+		layout: layout,
+	}
+}
+
+type workerManagerController struct {
+	logger slog.Logger
+	store  taskstore.StoreInterface
+	layout Layout
+}
+
+func (c *workerManagerController) ToTag(w http.ResponseWriter, r *http.Request) hb.TagInterface {
+	if r.Method == http.MethodPost {
+		return c.actionSubmitted(r)
+	}
+
+	data, errorMessage := c.prepareData(r)
+
+	c.layout.SetTitle("Workers | Zeppelin")
+
+	if errorMessage != "" {
+		c.layout.SetBody(hb.Div().
+			Class("alert alert-danger").
+			Text(errorMessage).ToHTML())
+
+		return hb.Raw(c.layout.Render(w, r))
+	}
+
+	htmxScript := `setTimeout(() => {
+		if (!window.htmx) {
+			let script = document.createElement('script');
+			document.head.appendChild(script);
+			script.type = 'text/javascript';
+			script.src = '` + cdn.Htmx_2_0_0() + `';
+		}
+	}, 1000);`
+
+	swalScript := `setTimeout(() => {
+		if (!window.Swal) {
+			let script = document.createElement('script');
+			document.head.appendChild(script);
+			script.type = 'text/javascript';
+			script.src = '` + cdn.Sweetalert2_11() + `';
+		}
+	}, 1000);`
+
+	c.layout.SetBody(c.page(&data).ToHTML())
+	c.layout.SetScripts([]string{htmxScript, swalScript})
+
+	return hb.Raw(c.layout.Render(w, r))
+}
+
+// actionSubmitted deregisters the dead worker with the posted ID
+func (c *workerManagerController) actionSubmitted(r *http.Request) hb.TagInterface {
+	action := req.GetStringTrimmed(r, "action")
+	workerID := req.GetStringTrimmed(r, fieldWorkerID)
+
+	if action != actionWorkerDeregister {
+		return hb.Swal(hb.SwalOptions{Icon: "error", Title: "Error", Text: "Action not supported", Position: "top-right"})
+	}
+
+	if workerID == "" {
+		return hb.Swal(hb.SwalOptions{Icon: "error", Title: "Error", Text: "No worker selected", Position: "top-right"})
+	}
+
+	if err := c.store.WorkerDeregister(context.Background(), workerID); err != nil {
+		c.logger.Error("At workerManagerController > actionSubmitted", "error", err.Error())
+		return hb.Swal(hb.SwalOptions{Icon: "error", Title: "Error", Text: err.Error(), Position: "top-right"})
+	}
+
+	return hb.Wrap().
+		Child(hb.Swal(hb.SwalOptions{Icon: "success", Title: "Success", Text: "Worker successfully deregistered.", Position: "top-right"})).
+		Child(hb.Script(`setTimeout(function(){window.location.href = window.location.href}, 2000);`))
+}
+
+func (c *workerManagerController) page(data *workerManagerControllerData) hb.TagInterface {
+	adminHeader := adminHeader(c.store, &c.logger, data.request)
+	breadcrumbs := breadcrumbs(data.request, []Breadcrumb{
+		{
+			Name: "Workers",
+			URL:  url(data.request, pathWorkerManager, map[string]string{}),
+		},
+	})
+
+	title := hb.Heading1().
+		HTML("Zeppelin. Workers")
+
+	summary := hb.Div().
+		Class("card bg-light mb-3").
+		Child(hb.Div().
+			Class("card-body").
+			Text("Showing " + cast.ToString(len(data.liveWorkers)) + " live and " + cast.ToString(len(data.deadWorkers)) + " dead worker(s)"))
+
+	return hb.Div().
+		Class("container").
+		Child(breadcrumbs).
+		Child(hb.HR()).
+		Child(adminHeader).
+		Child(hb.HR()).
+		Child(title).
+		Child(summary).
+		Child(c.tableRecords(data))
+}
+
+func (c *workerManagerController) tableRecords(data *workerManagerControllerData) hb.TagInterface {
+	workers := append(append([]taskstore.WorkerInterface{}, data.liveWorkers...), data.deadWorkers...)
+
+	return hb.Table().
+		Class("table table-striped table-hover table-bordered").
+		Children([]hb.TagInterface{
+			hb.Thead().Children([]hb.TagInterface{
+				hb.TR().Children([]hb.TagInterface{
+					hb.TH().HTML("Worker"),
+					hb.TH().HTML("Queue").Style("width: 1px;"),
+					hb.TH().HTML("In Flight").Style("width: 1px;"),
+					hb.TH().HTML("Started").Style("width: 1px;"),
+					hb.TH().HTML("Last Heartbeat").Style("width: 1px;"),
+					hb.TH().HTML("Status").Style("width: 1px;"),
+					hb.TH().HTML("Actions"),
+				}),
+			}),
+			hb.Tbody().Children(lo.Map(workers, func(worker taskstore.WorkerInterface, _ int) hb.TagInterface {
+				isAlive := c.store.WorkerIsAlive(worker)
+
+				// The running tasks of a dead worker are left over, until reaped
+				orphanedTasks := lo.Filter(data.deadWorkerTasks, func(task taskstore.TaskQueueInterface, _ int) bool {
+					return task.GetWorkerID() == worker.GetID()
+				})
+
+				status := hb.Span().
+					ClassIf(isAlive, "badge bg-success").
+					ClassIf(!isAlive, "badge bg-danger").
+					Text(lo.Ternary(isAlive, "Alive", "Dead"))
+
+				buttonDeregister := hb.Button().
+					Class("btn btn-sm btn-danger").
+					Style("margin-bottom: 2px; margin-left:2px; margin-right:2px;").
+					Child(hb.I().Class("bi bi-trash")).
+					Title("Remove this dead worker from the list").
+					HxPost(url(data.request, pathWorkerManager, map[string]string{
+						"action":      actionWorkerDeregister,
+						fieldWorkerID: worker.GetID(),
+					})).
+					HxConfirm("Remove this dead worker from the list?").
+					HxTarget("body").
+					HxSwap("beforeend")
+
+				return hb.TR().
+					// Worker
+					Child(hb.TD().
+						Child(hb.Div().Text(worker.GetID())).
+						ChildIf(worker.GetVersion() != "", hb.Div().
+							Style("font-size: 11px;").
+							Text("Version: ").
+							Text(worker.GetVersion())).
+						Children(lo.Map(orphanedTasks, func(task taskstore.TaskQueueInterface, _ int) hb.TagInterface {
+							return hb.Div().
+								Class("text-danger").
+								Style("font-size: 11px;").
+								Text("Running task: ").
+								Text(task.GetID())
+						}))).
+
+					// Queue
+					Child(hb.TD().
+						Text(worker.GetQueueName()).
+						Style("white-space: nowrap;")).
+
+					// In Flight
+					Child(hb.TD().
+						Text(cast.ToString(worker.GetInFlight()) + " / " + cast.ToString(worker.GetMaxConcurrency())).
+						Style("white-space: nowrap;")).
+
+					// Started
+					Child(hb.TD().
+						Child(hb.Div().Text(worker.GetStartedAtCarbon().Format("d M Y"))).
+						Child(hb.Div().Text(worker.GetStartedAtCarbon().ToTimeString())).
+						Style("white-space: nowrap; font-size: 13px;")).
+
+					// Last Heartbeat
+					Child(hb.TD().
+						Child(hb.Div().Text(worker.GetHeartbeatAtCarbon().Format("d M Y"))).
+						Child(hb.Div().Text(worker.GetHeartbeatAtCarbon().ToTimeString())).
+						Style("white-space: nowrap; font-size: 13px;")).
+
+					// Status
+					Child(hb.TD().
+						Child(status)).
+
+					// Actions
+					Child(hb.TD().
+						Style("text-align: center;").
+						ChildIf(!isAlive, buttonDeregister))
+			})),
+		})
+}
+
+func (c *workerManagerController) prepareData(r *http.Request) (data workerManagerControllerData, errorMessage string) {
+	var err error
+	data.request = r
+
+	data.liveWorkers, err = c.store.WorkerListLive(context.Background())
+
+	if err != nil {
+		c.logger.Error("At workerManagerController > prepareData", "error", err.Error())
+		return data, "error retrieving workers"
+	}
+
+	data.deadWorkers, err = c.store.WorkerListDead(context.Background())
+
+	if err != nil {
+		c.logger.Error("At workerManagerController > prepareData", "error", err.Error())
+		return data, "error retrieving workers"
+	}
+
+	data.deadWorkerTasks, err = c.store.WorkerDeadTaskList(context.Background())
+
+	if err != nil {
+		c.logger.Error("At workerManagerController > prepareData", "error", err.Error())
+		return data, "error retrieving the tasks of dead workers"
+	}
+
+	return data, ""
+}
+
+type workerManagerControllerData struct {
+	request *http.Request
+
+	liveWorkers     []taskstore.WorkerInterface
+	deadWorkers     []taskstore.WorkerInterface
+	deadWorkerTasks []taskstore.TaskQueueInterface
+}
@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"context"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dracory/taskstore"
+)
+
+func Test_workerManager(t *testing.T) {
+	// Test workerManager controller constructor with real SQLite store
+	store := setupTestStore(t)
+	layout := setupTestLayout(t)
+	logger := slog.Default()
+
+	controller := workerManager(*logger, store, layout)
+
+	if controller == nil {
+		t.Error("workerManager() should return a non-nil controller")
+	}
+	if controller.store == nil {
+		t.Error("workerManager() should set store")
+	}
+	if controller.layout == nil {
+		t.Error("workerManager() should set layout")
+	}
+}
+
+func Test_workerManagerController_list(t *testing.T) {
+	// Test the registered workers are listed
+	store := setupTestStore(t)
+	layout := &mockLayout{}
+	logger := slog.Default()
+
+	worker := taskstore.NewWorker("worker-42").
+		SetQueueName("emails").
+		SetVersion("v1.0.0")
+	if err := store.WorkerRegister(context.Background(), worker); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+
+	controller := workerManager(*logger, store, layout)
+	controller.ToTag(w, req)
+
+	if !strings.Contains(layout.body, "worker-42") {
+		t.Errorf("expected the worker to be listed, got %s", layout.body)
+	}
+	if !strings.Contains(layout.body, "Alive") {
+		t.Errorf("expected the worker to be alive, got %s", layout.body)
+	}
+	if !strings.Contains(layout.body, "Showing 1 live and 0 dead worker(s)") {
+		t.Errorf("expected the worker count to be shown, got %s", layout.body)
+	}
+}
+
+func Test_workerManagerController_deregister(t *testing.T) {
+	// Test a worker is deregistered
+	store := setupTestStore(t)
+	layout := &mockLayout{}
+	logger := slog.Default()
+
+	if err := store.WorkerRegister(context.Background(), taskstore.NewWorker("worker-42")); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("POST", "/?action="+actionWorkerDeregister+"&"+fieldWorkerID+"=worker-42", nil)
+	html := workerManager(*logger, store, layout).ToTag(httptest.NewRecorder(), req).ToHTML()
+
+	if !strings.Contains(html, "Worker successfully deregistered.") {
+		t.Errorf("expected a success message, got %s", html)
+	}
+
+	worker, err := store.WorkerFindByID(context.Background(), "worker-42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if worker != nil {
+		t.Error("expected the worker to be deregistered")
+	}
+}
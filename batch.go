@@ -0,0 +1,305 @@
+package taskstore
+
+import (
+	"time"
+
+	"github.com/dracory/neat/database/orm"
+	"github.com/dracory/neat/database/soft_delete"
+	neatuid "github.com/dracory/neat/support/uid"
+	"github.com/dromara/carbon/v2"
+)
+
+// == INTERFACE =================================================================
+
+// BatchInterface is a group of queued tasks enqueued together. Once all
+// its tasks finished, the batch completes and enqueues its callback tasks.
+type BatchInterface interface {
+	IsCompleted() bool
+	IsFailed() bool
+	IsPending() bool
+	IsRunning() bool
+	IsSuccess() bool
+	IsSoftDeleted() bool
+
+	GetCompletedAt() time.Time
+	GetCompletedAtCarbon() *carbon.Carbon
+	SetCompletedAt(completedAt time.Time) BatchInterface
+
+	GetCreatedAt() time.Time
+	GetCreatedAtCarbon() *carbon.Carbon
+	SetCreatedAt(createdAt time.Time) BatchInterface
+
+	GetFailedCount() int
+	SetFailedCount(failedCount int) BatchInterface
+
+	GetID() string
+	SetID(id string) BatchInterface
+
+	GetOnCompleteAlias() string
+	SetOnCompleteAlias(alias string) BatchInterface
+
+	GetOnFailureAlias() string
+	SetOnFailureAlias(alias string) BatchInterface
+
+	GetOnSuccessAlias() string
+	SetOnSuccessAlias(alias string) BatchInterface
+
+	GetPendingCount() int
+	SetPendingCount(pendingCount int) BatchInterface
+
+	GetQueueName() string
+	SetQueueName(queueName string) BatchInterface
+
+	GetSoftDeletedAt() time.Time
+	GetSoftDeletedAtCarbon() *carbon.Carbon
+	SetSoftDeletedAt(deletedAt time.Time) BatchInterface
+
+	GetStatus() string
+	SetStatus(status string) BatchInterface
+
+	GetSucceededCount() int
+	SetSucceededCount(succeededCount int) BatchInterface
+
+	GetTotalCount() int
+	SetTotalCount(totalCount int) BatchInterface
+
+	GetUpdatedAt() time.Time
+	GetUpdatedAtCarbon() *carbon.Carbon
+	SetUpdatedAt(updatedAt time.Time) BatchInterface
+}
+
+// == TYPE =====================================================================
+
+type batch struct {
+	orm.ShortID
+
+	StatusField          string    `db:"status"`
+	QueueNameField       string    `db:"queue_name"`
+	TotalCountField      int       `db:"total_count"`
+	PendingCountField    int       `db:"pending_count"`
+	SucceededCountField  int       `db:"succeeded_count"`
+	FailedCountField     int       `db:"failed_count"`
+	OnCompleteAliasField string    `db:"on_complete_alias"`
+	OnSuccessAliasField  string    `db:"on_success_alias"`
+	OnFailureAliasField  string    `db:"on_failure_alias"`
+	CompletedAtField     time.Time `db:"completed_at"`
+
+	CreatedAtField orm.CreatedAt
+	UpdatedAtField orm.UpdatedAt
+	soft_delete.SoftDeletesMaxDate
+}
+
+var _ BatchInterface = (*batch)(nil)
+
+// == CONSTRUCTORS =============================================================
+
+// NewBatch creates a new batch
+// If a queue name is provided, it is used for the callback tasks;
+// otherwise DefaultQueueName is used.
+func NewBatch(queueName ...string) BatchInterface {
+	name := DefaultQueueName
+	if len(queueName) > 0 && queueName[0] != "" {
+		name = queueName[0]
+	}
+
+	o := &batch{}
+
+	o.SetID(neatuid.GenerateShortID()).
+		SetStatus(BatchStatusPending).
+		SetQueueName(name).
+		SetTotalCount(0).
+		SetPendingCount(0).
+		SetSucceededCount(0).
+		SetFailedCount(0).
+		SetCompletedAt(time.Time{}).
+		SetCreatedAt(carbon.Now(carbon.UTC).StdTime()).
+		SetUpdatedAt(carbon.Now(carbon.UTC).StdTime()).
+		SetSoftDeletedAt(carbon.Parse(MAX_DATETIME, carbon.UTC).StdTime())
+
+	return o
+}
+
+// == METHODS ==================================================================
+
+// IsCompleted returns true once all the tasks of the batch finished
+func (o *batch) IsCompleted() bool {
+	return o.IsSuccess() || o.IsFailed()
+}
+
+func (o *batch) IsFailed() bool {
+	return o.GetStatus() == BatchStatusFailed
+}
+
+func (o *batch) IsPending() bool {
+	return o.GetStatus() == BatchStatusPending
+}
+
+func (o *batch) IsRunning() bool {
+	return o.GetStatus() == BatchStatusRunning
+}
+
+func (o *batch) IsSuccess() bool {
+	return o.GetStatus() == BatchStatusSuccess
+}
+
+func (o *batch) IsSoftDeleted() bool {
+	return o.SoftDeletesMaxDate.IsSoftDeleted()
+}
+
+// == SETTERS AND GETTERS ======================================================
+
+func (o *batch) GetCompletedAt() time.Time {
+	return o.CompletedAtField
+}
+
+func (o *batch) GetCompletedAtCarbon() *carbon.Carbon {
+	return carbon.CreateFromStdTime(o.CompletedAtField)
+}
+
+func (o *batch) SetCompletedAt(completedAt time.Time) BatchInterface {
+	o.CompletedAtField = completedAt
+	return o
+}
+
+func (o *batch) GetCreatedAt() time.Time {
+	return o.CreatedAtField.CreatedAt
+}
+
+func (o *batch) GetCreatedAtCarbon() *carbon.Carbon {
+	return carbon.CreateFromStdTime(o.CreatedAtField.CreatedAt)
+}
+
+func (o *batch) SetCreatedAt(createdAt time.Time) BatchInterface {
+	o.CreatedAtField.CreatedAt = createdAt
+	return o
+}
+
+// GetFailedCount returns the number of tasks of the batch which failed
+// or were canceled
+func (o *batch) GetFailedCount() int {
+	return o.FailedCountField
+}
+
+func (o *batch) SetFailedCount(failedCount int) BatchInterface {
+	o.FailedCountField = failedCount
+	return o
+}
+
+func (o *batch) GetID() string {
+	return o.ShortID.ID
+}
+
+func (o *batch) SetID(id string) BatchInterface {
+	o.ShortID.ID = id
+	return o
+}
+
+// GetOnCompleteAlias returns the alias of the task definition enqueued
+// when the batch completes, whatever the outcome
+func (o *batch) GetOnCompleteAlias() string {
+	return o.OnCompleteAliasField
+}
+
+func (o *batch) SetOnCompleteAlias(alias string) BatchInterface {
+	o.OnCompleteAliasField = alias
+	return o
+}
+
+// GetOnFailureAlias returns the alias of the task definition enqueued
+// when the batch completes with at least one failed task
+func (o *batch) GetOnFailureAlias() string {
+	return o.OnFailureAliasField
+}
+
+func (o *batch) SetOnFailureAlias(alias string) BatchInterface {
+	o.OnFailureAliasField = alias
+	return o
+}
+
+// GetOnSuccessAlias returns the alias of the task definition enqueued
+// when all the tasks of the batch succeeded
+func (o *batch) GetOnSuccessAlias() string {
+	return o.OnSuccessAliasField
+}
+
+func (o *batch) SetOnSuccessAlias(alias string) BatchInterface {
+	o.OnSuccessAliasField = alias
+	return o
+}
+
+// GetPendingCount returns the number of tasks of the batch which
+// have not finished yet
+func (o *batch) GetPendingCount() int {
+	return o.PendingCountField
+}
+
+func (o *batch) SetPendingCount(pendingCount int) BatchInterface {
+	o.PendingCountField = pendingCount
+	return o
+}
+
+// GetQueueName returns the name of the queue the callback tasks
+// are enqueued to
+func (o *batch) GetQueueName() string {
+	return o.QueueNameField
+}
+
+func (o *batch) SetQueueName(queueName string) BatchInterface {
+	o.QueueNameField = queueName
+	return o
+}
+
+func (o *batch) GetSoftDeletedAt() time.Time {
+	return o.SoftDeletesMaxDate.SoftDeletedAt
+}
+
+func (o *batch) GetSoftDeletedAtCarbon() *carbon.Carbon {
+	return carbon.CreateFromStdTime(o.SoftDeletesMaxDate.SoftDeletedAt)
+}
+
+func (o *batch) SetSoftDeletedAt(deletedAt time.Time) BatchInterface {
+	o.SoftDeletesMaxDate.SoftDeletedAt = deletedAt
+	return o
+}
+
+func (o *batch) GetStatus() string {
+	return o.StatusField
+}
+
+func (o *batch) SetStatus(status string) BatchInterface {
+	o.StatusField = status
+	return o
+}
+
+// GetSucceededCount returns the number of tasks of the batch which succeeded
+func (o *batch) GetSucceededCount() int {
+	return o.SucceededCountField
+}
+
+func (o *batch) SetSucceededCount(succeededCount int) BatchInterface {
+	o.SucceededCountField = succeededCount
+	return o
+}
+
+// GetTotalCount returns the number of tasks in the batch
+func (o *batch) GetTotalCount() int {
+	return o.TotalCountField
+}
+
+func (o *batch) SetTotalCount(totalCount int) BatchInterface {
+	o.TotalCountField = totalCount
+	return o
+}
+
+func (o *batch) GetUpdatedAt() time.Time {
+	return o.UpdatedAtField.UpdatedAt
+}
+
+func (o *batch) GetUpdatedAtCarbon() *carbon.Carbon {
+	return carbon.CreateFromStdTime(o.UpdatedAtField.UpdatedAt)
+}
+
+func (o *batch) SetUpdatedAt(updatedAt time.Time) BatchInterface {
+	o.UpdatedAtField.UpdatedAt = updatedAt
+	return o
+}
@@ -0,0 +1,102 @@
+package taskstore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewBatch(t *testing.T) {
+	batch := NewBatch()
+
+	if batch.GetID() == "" {
+		t.Error("Expected ID to be generated")
+	}
+	if batch.GetStatus() != BatchStatusPending {
+		t.Errorf("Expected status %s, got %s", BatchStatusPending, batch.GetStatus())
+	}
+	if batch.GetQueueName() != DefaultQueueName {
+		t.Errorf("Expected queue name %s, got %s", DefaultQueueName, batch.GetQueueName())
+	}
+	if !batch.GetCompletedAt().IsZero() {
+		t.Errorf("Expected no completed at, got %v", batch.GetCompletedAt())
+	}
+	if batch.IsSoftDeleted() {
+		t.Error("Expected batch not to be soft deleted")
+	}
+
+	if NewBatch("emails").GetQueueName() != "emails" {
+		t.Error("Expected the queue name to be used")
+	}
+}
+
+func TestBatch_StatusCheckers(t *testing.T) {
+	tests := []struct {
+		status    string
+		completed bool
+	}{
+		{BatchStatusPending, false},
+		{BatchStatusRunning, false},
+		{BatchStatusSuccess, true},
+		{BatchStatusFailed, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			batch := NewBatch().SetStatus(tt.status)
+
+			if batch.IsCompleted() != tt.completed {
+				t.Errorf("IsCompleted: Expected %v, got %v", tt.completed, batch.IsCompleted())
+			}
+			if batch.IsPending() != (tt.status == BatchStatusPending) {
+				t.Errorf("IsPending: Expected %v", tt.status == BatchStatusPending)
+			}
+			if batch.IsRunning() != (tt.status == BatchStatusRunning) {
+				t.Errorf("IsRunning: Expected %v", tt.status == BatchStatusRunning)
+			}
+			if batch.IsSuccess() != (tt.status == BatchStatusSuccess) {
+				t.Errorf("IsSuccess: Expected %v", tt.status == BatchStatusSuccess)
+			}
+			if batch.IsFailed() != (tt.status == BatchStatusFailed) {
+				t.Errorf("IsFailed: Expected %v", tt.status == BatchStatusFailed)
+			}
+		})
+	}
+}
+
+func TestBatch_SettersAndGetters(t *testing.T) {
+	completedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	batch := NewBatch().
+		SetID("BATCH_1").
+		SetQueueName("exports").
+		SetTotalCount(10).
+		SetPendingCount(4).
+		SetSucceededCount(5).
+		SetFailedCount(1).
+		SetOnCompleteAlias("ExportCompleted").
+		SetOnSuccessAlias("ExportSucceeded").
+		SetOnFailureAlias("ExportFailed").
+		SetCompletedAt(completedAt)
+
+	if batch.GetID() != "BATCH_1" {
+		t.Errorf("ID: Expected BATCH_1, got %s", batch.GetID())
+	}
+	if batch.GetQueueName() != "exports" {
+		t.Errorf("QueueName: Expected exports, got %s", batch.GetQueueName())
+	}
+	if batch.GetTotalCount() != 10 || batch.GetPendingCount() != 4 || batch.GetSucceededCount() != 5 || batch.GetFailedCount() != 1 {
+		t.Errorf("Counts: Expected 10/4/5/1, got %d/%d/%d/%d", batch.GetTotalCount(), batch.GetPendingCount(), batch.GetSucceededCount(), batch.GetFailedCount())
+	}
+	if batch.GetOnCompleteAlias() != "ExportCompleted" {
+		t.Errorf("OnCompleteAlias: Expected ExportCompleted, got %s", batch.GetOnCompleteAlias())
+	}
+	if batch.GetOnSuccessAlias() != "ExportSucceeded" {
+		t.Errorf("OnSuccessAlias: Expected ExportSucceeded, got %s", batch.GetOnSuccessAlias())
+	}
+	if batch.GetOnFailureAlias() != "ExportFailed" {
+		t.Errorf("OnFailureAlias: Expected ExportFailed, got %s", batch.GetOnFailureAlias())
+	}
+	if !batch.GetCompletedAt().Equal(completedAt) {
+		t.Errorf("CompletedAt: Expected %v, got %v", completedAt, batch.GetCompletedAt())
+	}
+}
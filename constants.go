@@ -1,5 +1,7 @@
 package taskstore
 
+import "time"
+
 const TaskQueueStatusCanceled = "canceled"
 const TaskQueueStatusDeleted = "deleted"
 const TaskQueueStatusFailed = "failed"
@@ -8,43 +10,115 @@ const TaskQueueStatusQueued = "queued"
 const TaskQueueStatusRunning = "running"
 const TaskQueueStatusSuccess = "success"
 
+// DependencyFailureCancel cancels a queued task when one of the tasks it
+// depends on fails or is canceled. This is the default.
+const DependencyFailureCancel = "cancel"
+
+// DependencyFailureBlock leaves a queued task blocked (paused) when one of
+// the tasks it depends on fails or is canceled, so it still runs if the
+// failed task is requeued and succeeds.
+const DependencyFailureBlock = "block"
+
+const BatchStatusFailed = "failed"
+const BatchStatusPending = "pending"
+const BatchStatusRunning = "running"
+const BatchStatusSuccess = "success"
+
+// QueueStatusActive is the status of a queue its tasks are claimed from.
+// Queues are active unless paused.
+const QueueStatusActive = "active"
+
+// QueueStatusPaused is the status of a queue no tasks are claimed from,
+// until it is resumed
+const QueueStatusPaused = "paused"
+
 const TaskDefinitionStatusActive = "active"
 const TaskDefinitionStatusCanceled = "canceled"
 
 const COLUMN_ALIAS = "alias"
 const COLUMN_ATTEMPTS = "attempts"
+const COLUMN_AVAILABLE_AT = "available_at"
+const COLUMN_BATCH_ID = "batch_id"
+const COLUMN_CANCEL_REQUESTED_AT = "cancel_requested_at"
+const COLUMN_CLAIMED_AT = "claimed_at"
 const COLUMN_COMPLETED_AT = "completed_at"
 const COLUMN_CREATED_AT = "created_at"
 const COLUMN_DETAILS = "details"
 const COLUMN_END_AT = "end_at"
+const COLUMN_ERROR_MESSAGE = "error_message"
 const COLUMN_EXECUTION_COUNT = "execution_count"
+const COLUMN_FAILED_COUNT = "failed_count"
+const COLUMN_HEARTBEAT_AT = "heartbeat_at"
 const COLUMN_ID = "id"
+const COLUMN_IN_FLIGHT = "in_flight"
+const COLUMN_DEAD_LETTER_QUEUE_NAME = "dead_letter_queue_name"
+const COLUMN_DEAD_LETTERED_AT = "dead_lettered_at"
+const COLUMN_DEPENDENCY_FAILURE_POLICY = "dependency_failure_policy"
+const COLUMN_DEPENDS_ON = "depends_on"
 const COLUMN_DESCRIPTION = "description"
 const COLUMN_IS_RECURRING = "is_recurring"
 const COLUMN_LAST_RUN_AT = "last_run_at"
+const COLUMN_LEASE_EXPIRES_AT = "lease_expires_at"
+const COLUMN_LEASE_OWNER = "lease_owner"
+const COLUMN_MAX_CONCURRENCY = "max_concurrency"
 const COLUMN_MAX_EXECUTION_COUNT = "max_execution_count"
 const COLUMN_METAS = "metas"
 const COLUMN_MEMO = "memo"
 const COLUMN_NAME = "name"
 const COLUMN_NEXT_RUN_AT = "next_run_at"
+const COLUMN_ON_COMPLETE_ALIAS = "on_complete_alias"
+const COLUMN_ON_FAILURE_ALIAS = "on_failure_alias"
+const COLUMN_ON_SUCCESS_ALIAS = "on_success_alias"
 const COLUMN_OUTPUT = "output"
 const COLUMN_PARAMETERS = "parameters"
+const COLUMN_PARAMETER_SCHEMA = "parameter_schema"
+const COLUMN_PAUSED_AT = "paused_at"
+const COLUMN_PENDING_COUNT = "pending_count"
+const COLUMN_PRIORITY = "priority"
 const COLUMN_QUEUE_NAME = "queue_name"
+const COLUMN_RATE_LIMIT = "rate_limit"
 const COLUMN_RECURRENCE_RULE = "recurrence_rule"
+const COLUMN_RETRY_POLICY = "retry_policy"
 const COLUMN_SOFT_DELETED_AT = "soft_deleted_at"
 const COLUMN_START_AT = "start_at"
 const COLUMN_STARTED_AT = "started_at"
 const COLUMN_STATUS = "status"
+const COLUMN_SUCCEEDED_COUNT = "succeeded_count"
 const COLUMN_TASK_DEFINITION_ID = "task_definition_id"
 const COLUMN_TASK_ID = "task_id"
+const COLUMN_TASK_QUEUE_ID = "task_queue_id"
+const COLUMN_TIMEOUT_SECONDS = "timeout_seconds"
 const COLUMN_TITLE = "title"
+const COLUMN_TOTAL_COUNT = "total_count"
+const COLUMN_UNIQUE_KEY = "unique_key"
+const COLUMN_UNIQUE_UNTIL = "unique_until"
 const COLUMN_UPDATED_AT = "updated_at"
+const COLUMN_VERSION = "version"
+const COLUMN_WORKER_ID = "worker_id"
 
 const ASC = "asc"
 const DESC = "desc"
 
 const DefaultQueueName = "default"
 
+// DefaultPriority is the priority of queued tasks, unless set otherwise.
+// Tasks with a higher priority are claimed first.
+const DefaultPriority = 0
+
+// DefaultLeaseDuration is how long a claimed task is leased, unless set
+// otherwise with NewStoreOptions.LeaseDuration. Leases are extended with
+// heartbeats while the task runs, and a task whose lease expired is
+// reaped (see TaskQueueReapExpiredLeases).
+const DefaultLeaseDuration = 5 * time.Minute
+
+// DefaultWorkerTimeout is how long a worker is considered alive after its
+// last heartbeat, unless set otherwise with NewStoreOptions.WorkerTimeout
+const DefaultWorkerTimeout = 2 * time.Minute
+
+// DefaultWorkerRetention is how long a dead worker stays registered before
+// it is pruned, unless set otherwise with NewStoreOptions.WorkerRetention
+const DefaultWorkerRetention = 24 * time.Hour
+
 // Null time (earliest valid date in Gregorian calendar is 1AD, no year 0)
 const NULL_DATE = "0002-01-01"
 const NULL_DATETIME = "0002-01-01 00:00:00"
@@ -0,0 +1,72 @@
+package taskstore
+
+import (
+	"time"
+
+	"github.com/dromara/carbon/v2"
+)
+
+// EnqueueOptions define the options for enqueueing a task.
+// The zero value enqueues the task with the defaults of its task definition.
+type EnqueueOptions struct {
+	// RetryPolicy overrides the retry policy of the task definition
+	// for this queued task only. Optional.
+	RetryPolicy *RetryPolicy
+
+	// Delay postpones the task, so it is not claimed before the delay
+	// has passed. Ignored when RunAt is set. Optional.
+	Delay time.Duration
+
+	// RunAt is the time before which the task is not claimed. Optional.
+	RunAt time.Time
+
+	// Priority overrides the priority of the task definition. Tasks with
+	// a higher priority are claimed first. Optional.
+	Priority *int
+
+	// UniqueKey prevents duplicate tasks from being enqueued. While a task
+	// holds the key, enqueueing another task with the same key returns the
+	// existing task instead of creating a new one. Optional.
+	UniqueKey string
+
+	// UniqueFor is how long the unique key is held after enqueueing, even
+	// once the task finished. The key is always held while the task is
+	// queued, running or paused.
+	UniqueFor time.Duration
+
+	// DependsOn are the IDs of the queued tasks which must succeed before
+	// the task can be claimed. Until then the task is blocked (paused).
+	// Optional.
+	DependsOn []string
+
+	// DependencyFailurePolicy is what happens to the task when one of the
+	// tasks it depends on fails or is canceled. One of DependencyFailureCancel
+	// (default) or DependencyFailureBlock.
+	DependencyFailurePolicy string
+
+	// Timeout overrides the execution timeout of the task definition.
+	// It is rounded up to whole seconds. Optional.
+	Timeout time.Duration
+
+	// batchID is the batch the task is enqueued in, set by BatchEnqueue
+	batchID string
+}
+
+// availableAt returns the time from which the task can be claimed.
+// A zero time means the task can be claimed immediately.
+func (options EnqueueOptions) availableAt() time.Time {
+	if !options.RunAt.IsZero() {
+		return options.RunAt.UTC()
+	}
+
+	if options.Delay > 0 {
+		return carbon.Now(carbon.UTC).StdTime().Add(options.Delay)
+	}
+
+	return time.Time{}
+}
+
+// timeoutSeconds returns the timeout rounded up to whole seconds
+func (options EnqueueOptions) timeoutSeconds() int {
+	return int((options.Timeout + time.Second - 1) / time.Second)
+}
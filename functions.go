@@ -1,8 +1,11 @@
 package taskstore
 
 import (
+	"fmt"
+	"os"
 	"time"
 
+	neatuid "github.com/dracory/neat/support/uid"
 	"github.com/dromara/carbon/v2"
 )
 
@@ -14,3 +17,26 @@ func parseTime(s string) time.Time {
 	}
 	return carbon.Parse(s, carbon.UTC).StdTime()
 }
+
+// nullableString converts an empty string to a NULL value for storage
+func nullableString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// isNullTime reports whether the time is a zero time.Time or NULL_DATETIME
+func isNullTime(t time.Time) bool {
+	return t.IsZero() || !t.After(carbon.Parse(NULL_DATETIME, carbon.UTC).StdTime())
+}
+
+// newWorkerID returns an ID identifying the current process as a worker,
+// made of the host name, the process ID and a random suffix
+func newWorkerID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), neatuid.GenerateShortID())
+}
@@ -0,0 +1,79 @@
+package taskstore
+
+import "sync"
+
+// NotifierInterface wakes up the runners of a queue when tasks are
+// enqueued to it, so they do not wait for their next poll. Runners still
+// poll at their interval, so a missed notification only delays a task.
+//
+// The store notifies with an in-process notifier by default, which wakes
+// the runners using the same store. A notifier backed by a message broker
+// or the database (i.e. Postgres LISTEN/NOTIFY) can be set with
+// NewStoreOptions.Notifier to wake runners in other processes too.
+type NotifierInterface interface {
+	// Notify signals the subscribers of the queue that tasks may be
+	// available. It must not block.
+	Notify(queueName string)
+
+	// Subscribe returns a channel receiving a signal when the queue is
+	// notified, and a function which unsubscribes. Signals are coalesced,
+	// so a subscriber busy meanwhile receives a single one.
+	Subscribe(queueName string) (<-chan struct{}, func())
+}
+
+// NewInProcessNotifier creates a notifier waking the subscribers within
+// the current process
+func NewInProcessNotifier() NotifierInterface {
+	return &inProcessNotifier{
+		subscribers: map[string]map[chan struct{}]struct{}{},
+	}
+}
+
+type inProcessNotifier struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan struct{}]struct{}
+}
+
+var _ NotifierInterface = (*inProcessNotifier)(nil)
+
+func (n *inProcessNotifier) Notify(queueName string) {
+	queueName = normalizeQueueName(queueName)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for subscriber := range n.subscribers[queueName] {
+		select {
+		case subscriber <- struct{}{}:
+		default:
+			// A signal is pending already
+		}
+	}
+}
+
+func (n *inProcessNotifier) Subscribe(queueName string) (<-chan struct{}, func()) {
+	queueName = normalizeQueueName(queueName)
+	subscriber := make(chan struct{}, 1)
+
+	n.mu.Lock()
+	if n.subscribers[queueName] == nil {
+		n.subscribers[queueName] = map[chan struct{}]struct{}{}
+	}
+	n.subscribers[queueName][subscriber] = struct{}{}
+	n.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+
+			delete(n.subscribers[queueName], subscriber)
+			if len(n.subscribers[queueName]) == 0 {
+				delete(n.subscribers, queueName)
+			}
+		})
+	}
+
+	return subscriber, unsubscribe
+}
@@ -0,0 +1,195 @@
+package taskstore
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInProcessNotifier_Notify(t *testing.T) {
+	notifier := NewInProcessNotifier()
+
+	emails, unsubscribeEmails := notifier.Subscribe("emails")
+	defer unsubscribeEmails()
+	reports, unsubscribeReports := notifier.Subscribe("reports")
+	defer unsubscribeReports()
+
+	// Signals are coalesced
+	notifier.Notify("emails")
+	notifier.Notify("emails")
+
+	select {
+	case <-emails:
+	default:
+		t.Fatal("expected the emails subscriber to be notified")
+	}
+
+	select {
+	case <-emails:
+		t.Fatal("expected a single pending signal")
+	default:
+	}
+
+	select {
+	case <-reports:
+		t.Fatal("expected the reports subscriber not to be notified")
+	default:
+	}
+}
+
+func TestInProcessNotifier_DefaultQueueName(t *testing.T) {
+	notifier := NewInProcessNotifier()
+
+	wakeups, unsubscribe := notifier.Subscribe("")
+	defer unsubscribe()
+
+	notifier.Notify(DefaultQueueName)
+
+	select {
+	case <-wakeups:
+	default:
+		t.Fatal("expected the empty queue name to subscribe to the default queue")
+	}
+}
+
+func TestInProcessNotifier_Unsubscribe(t *testing.T) {
+	notifier := NewInProcessNotifier()
+
+	wakeups, unsubscribe := notifier.Subscribe("emails")
+	unsubscribe()
+	unsubscribe() // Unsubscribing twice is harmless
+
+	notifier.Notify("emails")
+
+	select {
+	case <-wakeups:
+		t.Fatal("expected no signal after unsubscribing")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestStore_NotifiesWhenTasksBecomeQueued(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("initStore: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	wakeups, unsubscribe := store.GetNotifier().Subscribe(DefaultQueueName)
+	defer unsubscribe()
+
+	drain := func() {
+		for {
+			select {
+			case <-wakeups:
+			default:
+				return
+			}
+		}
+	}
+
+	expectWakeup := func(transition string) {
+		t.Helper()
+		select {
+		case <-wakeups:
+		case <-time.After(time.Second):
+			t.Fatalf("expected a wakeup when %s", transition)
+		}
+	}
+
+	handler := newTestTaskHandler()
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	parent, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+	}
+	if _, err := store.TaskDefinitionEnqueueByAliasWithOptions(ctx, DefaultQueueName, handler.Alias(), map[string]any{}, EnqueueOptions{
+		DependsOn: []string{parent.GetID()},
+	}); err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAliasWithOptions: Error[%v]", err)
+	}
+
+	drain()
+	if err := store.TaskQueueSuccess(ctx, parent); err != nil {
+		t.Fatalf("TaskQueueSuccess: Error[%v]", err)
+	}
+	expectWakeup("the dependencies of a task succeeded")
+
+	deadLetter := NewTaskQueue().
+		SetTaskID("DEAD_LETTER").
+		SetStatus(TaskQueueStatusFailed).
+		SetDeadLetterQueueName("poison")
+	if err := store.TaskQueueCreate(ctx, deadLetter); err != nil {
+		t.Fatalf("TaskQueueCreate: Error[%v]", err)
+	}
+
+	drain()
+	if _, err := store.DeadLetterReplay(ctx, []string{deadLetter.GetID()}); err != nil {
+		t.Fatalf("DeadLetterReplay: Error[%v]", err)
+	}
+	expectWakeup("a dead letter is replayed")
+}
+
+func TestStore_LegacyRunLoopsWakeUpOnEnqueue(t *testing.T) {
+	runs := map[string]func(store *Store, ctx context.Context, queueName string){
+		"serial": func(store *Store, ctx context.Context, queueName string) {
+			store.TaskQueueRunSerial(ctx, queueName, 60, 0)
+		},
+		"concurrent": func(store *Store, ctx context.Context, queueName string) {
+			store.TaskQueueRunConcurrent(ctx, queueName, 60, 0)
+		},
+	}
+
+	for name, run := range runs {
+		t.Run(name, func(t *testing.T) {
+			store, err := initStore()
+			if err != nil {
+				t.Fatalf("initStore: Error[%v]", err)
+			}
+			defer store.GetDB().Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			handler := newTestTaskHandler()
+			if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+				t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+			}
+
+			run(store, ctx, "emails")
+			defer store.TaskQueueStopByName("emails")
+
+			// Let the first run find the queue empty
+			time.Sleep(100 * time.Millisecond)
+
+			queuedTask, err := store.TaskDefinitionEnqueueByAlias(ctx, "emails", handler.Alias(), map[string]any{
+				"completeWithSuccess": "yes",
+			})
+			if err != nil {
+				t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+			}
+
+			// The enqueued task is claimed right away, without waiting
+			// for a fixed delay before the claim
+			deadline := time.Now().Add(500 * time.Millisecond)
+			for {
+				task, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+				if err != nil {
+					t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+				}
+				if task.GetStatus() == TaskQueueStatusSuccess {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("expected the enqueued task to wake up the loop, status is %s", task.GetStatus())
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+		})
+	}
+}
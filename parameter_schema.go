@@ -0,0 +1,216 @@
+package taskstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Parameter types of a TaskParameter, named as in JSON Schema
+const (
+	ParameterTypeString  = "string"
+	ParameterTypeInteger = "integer"
+	ParameterTypeNumber  = "number"
+	ParameterTypeBoolean = "boolean"
+	ParameterTypeArray   = "array"
+	ParameterTypeObject  = "object"
+)
+
+// TaskParameter declares a parameter a task definition expects.
+//
+// The parameters of a task definition are its parameter schema (see
+// TaskDefinitionInterface.SetParameterSchema, and TaskHandlerWithParameters
+// to declare them on a handler). The schema is stored as JSON with the task
+// definition, so callers in other languages can discover it. Enqueuing
+// checks the parameters against it, and fills the defaults.
+type TaskParameter struct {
+	// Name is the name of the parameter
+	Name string `json:"name"`
+
+	// Type is one of the ParameterType constants.
+	// Empty accepts values of any type.
+	Type string `json:"type,omitempty"`
+
+	// Required parameters must be enqueued, unless they have a default
+	Required bool `json:"required,omitempty"`
+
+	// Default is the value of the parameter when it is not enqueued.
+	// Nil means no default.
+	Default any `json:"default,omitempty"`
+
+	// Description describes the parameter to the callers
+	Description string `json:"description,omitempty"`
+}
+
+// ParameterError is a parameter which failed validation
+type ParameterError struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+// ParameterValidationError is returned when enqueuing a task with
+// parameters which do not match the parameter schema of its task
+// definition. Use errors.As to get the parameters which failed.
+type ParameterValidationError struct {
+	Alias  string           `json:"alias"`
+	Errors []ParameterError `json:"errors"`
+}
+
+func (e *ParameterValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, parameterError := range e.Errors {
+		messages = append(messages, parameterError.Name+": "+parameterError.Message)
+	}
+
+	return "invalid parameters for task '" + e.Alias + "': " + strings.Join(messages, "; ")
+}
+
+// validateParameterSchema checks the parameters of a schema have a unique
+// name and a known type
+func validateParameterSchema(schema []TaskParameter) error {
+	types := []string{"", ParameterTypeString, ParameterTypeInteger, ParameterTypeNumber, ParameterTypeBoolean, ParameterTypeArray, ParameterTypeObject}
+
+	names := map[string]bool{}
+	for _, parameter := range schema {
+		if parameter.Name == "" {
+			return errors.New("parameter name is empty")
+		}
+		if names[parameter.Name] {
+			return errors.New("parameter '" + parameter.Name + "' is declared twice")
+		}
+		names[parameter.Name] = true
+
+		if !slices.Contains(types, parameter.Type) {
+			return errors.New("parameter '" + parameter.Name + "' has unknown type '" + parameter.Type + "'")
+		}
+		if parameter.Default != nil && !parameterHasType(parameter.Default, parameter.Type) {
+			return errors.New("default of parameter '" + parameter.Name + "' is not of type " + parameter.Type)
+		}
+	}
+
+	return nil
+}
+
+// validateParameters checks the parameters against the schema of the task
+// definition with the alias. Returns a copy of the parameters, with the
+// defaults filled, or a *ParameterValidationError. Parameters not in the
+// schema are kept as they are.
+func validateParameters(alias string, schema []TaskParameter, parameters map[string]any) (map[string]any, error) {
+	if len(schema) == 0 {
+		return parameters, nil
+	}
+
+	validated := make(map[string]any, len(parameters)+len(schema))
+	for name, value := range parameters {
+		validated[name] = value
+	}
+
+	validationErr := &ParameterValidationError{Alias: alias}
+	for _, parameter := range schema {
+		value, exists := validated[parameter.Name]
+		if !exists || value == nil {
+			if parameter.Default != nil {
+				validated[parameter.Name] = parameter.Default
+			} else if parameter.Required {
+				validationErr.Errors = append(validationErr.Errors, ParameterError{
+					Name:    parameter.Name,
+					Message: "is required",
+				})
+			}
+			continue
+		}
+
+		if !parameterHasType(value, parameter.Type) {
+			validationErr.Errors = append(validationErr.Errors, ParameterError{
+				Name:    parameter.Name,
+				Message: "must be of type " + parameter.Type,
+			})
+		}
+	}
+
+	if len(validationErr.Errors) > 0 {
+		return nil, validationErr
+	}
+
+	return validated, nil
+}
+
+// parameterHasType reports whether the value is of the parameter type, once
+// encoded to JSON as the parameters are when enqueued
+func parameterHasType(value any, parameterType string) bool {
+	if parameterType == "" {
+		return true
+	}
+
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(valueBytes))
+	decoder.UseNumber()
+
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return false
+	}
+
+	switch parameterType {
+	case ParameterTypeString:
+		_, ok := decoded.(string)
+		return ok
+	case ParameterTypeInteger:
+		number, ok := decoded.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := number.Int64()
+		return err == nil
+	case ParameterTypeNumber:
+		_, ok := decoded.(json.Number)
+		return ok
+	case ParameterTypeBoolean:
+		_, ok := decoded.(bool)
+		return ok
+	case ParameterTypeArray:
+		_, ok := decoded.([]any)
+		return ok
+	case ParameterTypeObject:
+		_, ok := decoded.(map[string]any)
+		return ok
+	}
+
+	return false
+}
+
+// parameterSchemaToJSON serializes a parameter schema for storage.
+// An empty schema is stored as an empty string.
+func parameterSchemaToJSON(schema []TaskParameter) string {
+	if len(schema) == 0 {
+		return ""
+	}
+
+	schemaBytes, err := json.Marshal(schema)
+	if err != nil {
+		return ""
+	}
+
+	return string(schemaBytes)
+}
+
+// parameterSchemaFromJSON deserializes a stored parameter schema.
+// Empty or invalid values result in a nil schema.
+func parameterSchemaFromJSON(schemaJSON string) []TaskParameter {
+	if schemaJSON == "" {
+		return nil
+	}
+
+	var schema []TaskParameter
+	if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
+		return nil
+	}
+
+	return schema
+}
@@ -0,0 +1,114 @@
+package taskstore
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_validateParameters(t *testing.T) {
+	schema := []TaskParameter{
+		{Name: "to", Type: ParameterTypeString, Required: true},
+		{Name: "retries", Type: ParameterTypeInteger, Default: 3},
+		{Name: "ratio", Type: ParameterTypeNumber},
+		{Name: "urgent", Type: ParameterTypeBoolean},
+		{Name: "tags", Type: ParameterTypeArray},
+		{Name: "headers", Type: ParameterTypeObject},
+		{Name: "anything"},
+	}
+
+	validated, err := validateParameters("send-email", schema, map[string]any{
+		"to":       "jane@example.com",
+		"ratio":    json.Number("0.5"),
+		"urgent":   true,
+		"tags":     []string{"a", "b"},
+		"headers":  map[string]string{"X-Campaign": "spring"},
+		"anything": 42,
+		"extra":    "kept",
+	})
+	if err != nil {
+		t.Fatalf("validateParameters: Error[%v]", err)
+	}
+	if validated["retries"] != 3 {
+		t.Fatalf("Expected the default of retries to be filled, got %v", validated["retries"])
+	}
+	if validated["extra"] != "kept" {
+		t.Fatalf("Expected parameters not in the schema to be kept, got %v", validated["extra"])
+	}
+
+	_, err = validateParameters("send-email", schema, map[string]any{
+		"retries": 2.5,
+		"ratio":   "half",
+		"urgent":  "yes",
+		"tags":    "a;b",
+		"headers": []string{},
+	})
+	var validationErr *ParameterValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a *ParameterValidationError, got %v", err)
+	}
+	if validationErr.Alias != "send-email" {
+		t.Fatalf("Expected alias %s, got %s", "send-email", validationErr.Alias)
+	}
+
+	expected := []ParameterError{
+		{Name: "to", Message: "is required"},
+		{Name: "retries", Message: "must be of type integer"},
+		{Name: "ratio", Message: "must be of type number"},
+		{Name: "urgent", Message: "must be of type boolean"},
+		{Name: "tags", Message: "must be of type array"},
+		{Name: "headers", Message: "must be of type object"},
+	}
+	if !reflect.DeepEqual(validationErr.Errors, expected) {
+		t.Fatalf("Expected errors %v, got %v", expected, validationErr.Errors)
+	}
+	if validationErr.Error() != "invalid parameters for task 'send-email': to: is required; retries: must be of type integer; ratio: must be of type number; urgent: must be of type boolean; tags: must be of type array; headers: must be of type object" {
+		t.Fatalf("Unexpected message %s", validationErr.Error())
+	}
+
+	// Without a schema the parameters are not checked
+	parameters := map[string]any{"to": 1}
+	validated, err = validateParameters("send-email", nil, parameters)
+	if err != nil || !reflect.DeepEqual(validated, parameters) {
+		t.Fatalf("Expected the parameters unchanged, got %v %v", validated, err)
+	}
+}
+
+func Test_validateParameterSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  []TaskParameter
+		wantErr bool
+	}{
+		{name: "empty", schema: nil},
+		{name: "valid", schema: []TaskParameter{{Name: "to", Type: ParameterTypeString, Default: "x"}, {Name: "any"}}},
+		{name: "no name", schema: []TaskParameter{{Type: ParameterTypeString}}, wantErr: true},
+		{name: "duplicate", schema: []TaskParameter{{Name: "to"}, {Name: "to"}}, wantErr: true},
+		{name: "unknown type", schema: []TaskParameter{{Name: "to", Type: "email"}}, wantErr: true},
+		{name: "default of wrong type", schema: []TaskParameter{{Name: "retries", Type: ParameterTypeInteger, Default: "3"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateParameterSchema(tt.schema)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateParameterSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_parameterSchemaJSON(t *testing.T) {
+	if parameterSchemaToJSON(nil) != "" {
+		t.Fatal("Expected an empty schema to be stored as an empty string")
+	}
+	if parameterSchemaFromJSON("") != nil || parameterSchemaFromJSON("invalid") != nil {
+		t.Fatal("Expected empty or invalid values to result in a nil schema")
+	}
+
+	schema := []TaskParameter{{Name: "to", Type: ParameterTypeString, Required: true, Default: "jane@example.com", Description: "Recipient"}}
+	if got := parameterSchemaFromJSON(parameterSchemaToJSON(schema)); !reflect.DeepEqual(got, schema) {
+		t.Fatalf("Expected %v, got %v", schema, got)
+	}
+}
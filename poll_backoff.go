@@ -0,0 +1,44 @@
+package taskstore
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// pollBackoff is the interval between the polls of a runner. It doubles
+// while consecutive polls find nothing to do, from the minimum up to the
+// maximum interval, and is reset to the minimum as soon as a poll finds
+// work. With a maximum not above the minimum, the interval is fixed.
+type pollBackoff struct {
+	minInterval time.Duration
+	maxInterval time.Duration
+	current     atomic.Int64 // The interval waited after the last poll
+	idle        bool         // Whether the last poll found nothing to do
+}
+
+func newPollBackoff(minInterval, maxInterval time.Duration) *pollBackoff {
+	backoff := &pollBackoff{
+		minInterval: minInterval,
+		maxInterval: max(minInterval, maxInterval),
+	}
+	backoff.current.Store(int64(minInterval))
+	return backoff
+}
+
+// next returns the interval to wait after a poll, which found work or not
+func (b *pollBackoff) next(found bool) time.Duration {
+	interval := b.minInterval
+	if !found && b.idle {
+		interval = min(2*b.interval(), b.maxInterval)
+	}
+
+	b.idle = !found
+	b.current.Store(int64(interval))
+
+	return interval
+}
+
+// interval returns the interval waited after the last poll
+func (b *pollBackoff) interval() time.Duration {
+	return time.Duration(b.current.Load())
+}
@@ -0,0 +1,48 @@
+package taskstore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPollBackoff_Fixed(t *testing.T) {
+	backoff := newPollBackoff(10*time.Second, 0)
+
+	for _, found := range []bool{false, false, false, true, false} {
+		if interval := backoff.next(found); interval != 10*time.Second {
+			t.Fatalf("expected a fixed interval of 10s, got %v", interval)
+		}
+	}
+}
+
+func TestPollBackoff_Exponential(t *testing.T) {
+	backoff := newPollBackoff(time.Second, 8*time.Second)
+
+	if backoff.interval() != time.Second {
+		t.Fatalf("expected the minimum interval before any poll, got %v", backoff.interval())
+	}
+
+	steps := []struct {
+		found    bool
+		expected time.Duration
+	}{
+		{false, time.Second},
+		{false, 2 * time.Second},
+		{false, 4 * time.Second},
+		{false, 8 * time.Second},
+		{false, 8 * time.Second}, // capped at the maximum
+		{true, time.Second},      // reset once work is found
+		{false, time.Second},
+		{false, 2 * time.Second},
+	}
+
+	for i, step := range steps {
+		interval := backoff.next(step.found)
+		if interval != step.expected {
+			t.Fatalf("step %d: expected %v, got %v", i, step.expected, interval)
+		}
+		if backoff.interval() != interval {
+			t.Fatalf("step %d: expected the current interval to be %v, got %v", i, interval, backoff.interval())
+		}
+	}
+}
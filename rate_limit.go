@@ -0,0 +1,64 @@
+package taskstore
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// RateLimit caps how many tasks are claimed within a sliding window, i.e.
+// for task definitions calling APIs with strict quotas.
+//
+// A rate limit can be set on a queue (see Store.QueueSetRateLimit) and on a
+// task definition. Limits are enforced when claiming, by counting the claims
+// (every attempt of a task counts) within the last PeriodSeconds in the
+// database, so they hold across processes. Tasks over the limit stay queued until the window
+// allows them.
+type RateLimit struct {
+	// Limit is the number of tasks claimed at most within the period.
+	// Values of 0 or less disable the limit.
+	Limit int `json:"limit"`
+
+	// PeriodSeconds is the length of the sliding window.
+	// Values of 0 or less disable the limit.
+	PeriodSeconds int `json:"period_seconds"`
+}
+
+// IsEnabled reports whether the rate limit caps anything
+func (rateLimit RateLimit) IsEnabled() bool {
+	return rateLimit.Limit > 0 && rateLimit.PeriodSeconds > 0
+}
+
+// Period returns the length of the sliding window
+func (rateLimit RateLimit) Period() time.Duration {
+	return time.Duration(rateLimit.PeriodSeconds) * time.Second
+}
+
+// rateLimitToJSON serializes a rate limit for storage.
+// A nil or disabled rate limit is stored as an empty string.
+func rateLimitToJSON(rateLimit *RateLimit) string {
+	if rateLimit == nil || !rateLimit.IsEnabled() {
+		return ""
+	}
+
+	rateLimitBytes, err := json.Marshal(rateLimit)
+	if err != nil {
+		return ""
+	}
+
+	return string(rateLimitBytes)
+}
+
+// rateLimitFromJSON deserializes a stored rate limit.
+// Empty or invalid values result in a nil rate limit.
+func rateLimitFromJSON(rateLimitJSON string) *RateLimit {
+	if rateLimitJSON == "" {
+		return nil
+	}
+
+	rateLimit := &RateLimit{}
+	if err := json.Unmarshal([]byte(rateLimitJSON), rateLimit); err != nil {
+		return nil
+	}
+
+	return rateLimit
+}
@@ -0,0 +1,55 @@
+package taskstore
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimit_IsEnabled(t *testing.T) {
+	tests := []struct {
+		name      string
+		rateLimit RateLimit
+		want      bool
+	}{
+		{"zero value", RateLimit{}, false},
+		{"no period", RateLimit{Limit: 10}, false},
+		{"no limit", RateLimit{PeriodSeconds: 60}, false},
+		{"limit and period", RateLimit{Limit: 10, PeriodSeconds: 60}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rateLimit.IsEnabled(); got != tt.want {
+				t.Errorf("IsEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimit_Period(t *testing.T) {
+	if got := (RateLimit{Limit: 1, PeriodSeconds: 90}).Period(); got != 90*time.Second {
+		t.Errorf("Period() = %v, want %v", got, 90*time.Second)
+	}
+}
+
+func TestRateLimit_JSON(t *testing.T) {
+	if got := rateLimitToJSON(nil); got != "" {
+		t.Errorf("rateLimitToJSON(nil) = %q, want empty", got)
+	}
+	if got := rateLimitToJSON(&RateLimit{Limit: 10}); got != "" {
+		t.Errorf("rateLimitToJSON(disabled) = %q, want empty", got)
+	}
+
+	if got := rateLimitFromJSON(""); got != nil {
+		t.Errorf("rateLimitFromJSON(\"\") = %v, want nil", got)
+	}
+	if got := rateLimitFromJSON("not json"); got != nil {
+		t.Errorf("rateLimitFromJSON(invalid) = %v, want nil", got)
+	}
+
+	rateLimit := &RateLimit{Limit: 60, PeriodSeconds: 60}
+	got := rateLimitFromJSON(rateLimitToJSON(rateLimit))
+	if got == nil || *got != *rateLimit {
+		t.Errorf("rateLimitFromJSON(rateLimitToJSON(%v)) = %v", rateLimit, got)
+	}
+}
@@ -0,0 +1,124 @@
+package taskstore
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+const RetryBackoffFixed = "fixed"
+const RetryBackoffExponential = "exponential"
+const RetryBackoffJitter = "jitter"
+
+// RetryPolicy defines how a failed queued task is retried.
+//
+// A retry policy can be set on a task definition, and overridden for a
+// single queued task when it is enqueued. When no policy is set a failed
+// task is not retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values of 1 or less disable retries.
+	MaxAttempts int `json:"max_attempts"`
+
+	// Backoff is the strategy used to calculate the delay before the next
+	// attempt. One of RetryBackoffFixed (default), RetryBackoffExponential
+	// or RetryBackoffJitter.
+	Backoff string `json:"backoff,omitempty"`
+
+	// DelaySeconds is the base delay before the next attempt.
+	DelaySeconds int `json:"delay_seconds,omitempty"`
+
+	// MaxDelaySeconds caps the calculated delay. 0 means no cap.
+	MaxDelaySeconds int `json:"max_delay_seconds,omitempty"`
+}
+
+// CanRetry reports whether another attempt is allowed after the given
+// number of attempts has been made.
+func (policy RetryPolicy) CanRetry(attempts int) bool {
+	return attempts < policy.MaxAttempts
+}
+
+// NextDelay returns the delay before the next attempt, after the given
+// number of attempts has been made.
+//
+// Business logic:
+//   - fixed: the base delay
+//   - exponential: the base delay doubled after each attempt
+//   - jitter: a random delay between half and the full exponential delay,
+//     which spreads retries of tasks that failed together
+func (policy RetryPolicy) NextDelay(attempts int) time.Duration {
+	if policy.DelaySeconds <= 0 {
+		return 0
+	}
+
+	// The delay is capped by the max delay if set, and in any case by
+	// retryMaxDelay, so doubling it for many attempts cannot overflow
+	maxDelay := retryMaxDelay
+	if policy.MaxDelaySeconds > 0 && int64(policy.MaxDelaySeconds) < int64(retryMaxDelay/time.Second) {
+		maxDelay = time.Duration(policy.MaxDelaySeconds) * time.Second
+	}
+
+	delay := maxDelay
+	if int64(policy.DelaySeconds) < int64(maxDelay/time.Second) {
+		delay = time.Duration(policy.DelaySeconds) * time.Second
+	}
+
+	if policy.Backoff == RetryBackoffExponential || policy.Backoff == RetryBackoffJitter {
+		for i := 1; i < attempts && delay < maxDelay; i++ {
+			if delay > maxDelay/2 {
+				delay = maxDelay
+				break
+			}
+			delay *= 2
+		}
+	}
+
+	if policy.Backoff == RetryBackoffJitter {
+		half := delay / 2
+		delay = half + rand.N(delay-half+1)
+	}
+
+	return delay
+}
+
+// retryMaxDelay caps the delay before the next attempt of a retry policy
+// without a max delay, far beyond any useful delay
+const retryMaxDelay = time.Duration(math.MaxInt64 / 2)
+
+// permanentFailureMarker is implemented by queued tasks which can be marked
+// during processing as failed in a way that must not be retried
+type permanentFailureMarker interface {
+	setPermanentFailure(permanentFailure bool)
+	isPermanentFailure() bool
+}
+
+// retryPolicyToJSON serializes a retry policy for storage.
+// A nil policy is stored as an empty string.
+func retryPolicyToJSON(policy *RetryPolicy) string {
+	if policy == nil {
+		return ""
+	}
+
+	policyBytes, err := json.Marshal(policy)
+	if err != nil {
+		return ""
+	}
+
+	return string(policyBytes)
+}
+
+// retryPolicyFromJSON deserializes a stored retry policy.
+// Empty or invalid values result in a nil policy.
+func retryPolicyFromJSON(policyJSON string) *RetryPolicy {
+	if policyJSON == "" {
+		return nil
+	}
+
+	policy := &RetryPolicy{}
+	if err := json.Unmarshal([]byte(policyJSON), policy); err != nil {
+		return nil
+	}
+
+	return policy
+}
@@ -0,0 +1,150 @@
+package taskstore
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_CanRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		attempts int
+		want     bool
+	}{
+		{
+			name:     "zero max attempts never retries",
+			policy:   RetryPolicy{},
+			attempts: 1,
+			want:     false,
+		},
+		{
+			name:     "attempts below max attempts",
+			policy:   RetryPolicy{MaxAttempts: 3},
+			attempts: 2,
+			want:     true,
+		},
+		{
+			name:     "attempts reached max attempts",
+			policy:   RetryPolicy{MaxAttempts: 3},
+			attempts: 3,
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.CanRetry(tt.attempts); got != tt.want {
+				t.Errorf("CanRetry(%d) = %v, want %v", tt.attempts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_NextDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		attempts int
+		want     time.Duration
+	}{
+		{
+			name:     "no delay",
+			policy:   RetryPolicy{MaxAttempts: 3},
+			attempts: 1,
+			want:     0,
+		},
+		{
+			name:     "fixed is the default",
+			policy:   RetryPolicy{MaxAttempts: 3, DelaySeconds: 10},
+			attempts: 2,
+			want:     10 * time.Second,
+		},
+		{
+			name:     "exponential first attempt",
+			policy:   RetryPolicy{MaxAttempts: 5, Backoff: RetryBackoffExponential, DelaySeconds: 10},
+			attempts: 1,
+			want:     10 * time.Second,
+		},
+		{
+			name:     "exponential third attempt",
+			policy:   RetryPolicy{MaxAttempts: 5, Backoff: RetryBackoffExponential, DelaySeconds: 10},
+			attempts: 3,
+			want:     40 * time.Second,
+		},
+		{
+			name:     "exponential capped by max delay",
+			policy:   RetryPolicy{MaxAttempts: 50, Backoff: RetryBackoffExponential, DelaySeconds: 10, MaxDelaySeconds: 60},
+			attempts: 40,
+			want:     60 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.NextDelay(tt.attempts); got != tt.want {
+				t.Errorf("NextDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_NextDelay_Jitter(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, Backoff: RetryBackoffJitter, DelaySeconds: 10}
+
+	for i := 0; i < 20; i++ {
+		delay := policy.NextDelay(3)
+		if delay < 20*time.Second || delay > 40*time.Second {
+			t.Fatalf("NextDelay(3) = %v, want between 20s and 40s", delay)
+		}
+	}
+}
+
+func TestRetryPolicy_NextDelay_ManyAttempts(t *testing.T) {
+	for _, backoff := range []string{RetryBackoffExponential, RetryBackoffJitter} {
+		policy := RetryPolicy{MaxAttempts: 1000, Backoff: backoff, DelaySeconds: 1}
+
+		previous := time.Duration(0)
+		for attempts := 1; attempts <= 1000; attempts++ {
+			delay := policy.NextDelay(attempts)
+			if delay <= 0 {
+				t.Fatalf("%s: NextDelay(%d) = %v, want a positive delay", backoff, attempts, delay)
+			}
+			if backoff == RetryBackoffExponential && delay < previous {
+				t.Fatalf("%s: NextDelay(%d) = %v, want at least %v", backoff, attempts, delay, previous)
+			}
+			previous = delay
+		}
+
+		capped := RetryPolicy{MaxAttempts: 1000, Backoff: backoff, DelaySeconds: 1, MaxDelaySeconds: 3600}
+		if delay := capped.NextDelay(1000); delay <= 0 || delay > time.Hour {
+			t.Fatalf("%s: NextDelay(1000) = %v, want between 0 and 1h", backoff, delay)
+		}
+	}
+
+	huge := RetryPolicy{MaxAttempts: 3, Backoff: RetryBackoffExponential, DelaySeconds: math.MaxInt}
+	if delay := huge.NextDelay(2); delay <= 0 {
+		t.Fatalf("NextDelay(2) = %v, want a positive delay", delay)
+	}
+}
+
+func Test_retryPolicyJSON(t *testing.T) {
+	if got := retryPolicyToJSON(nil); got != "" {
+		t.Errorf("retryPolicyToJSON(nil) = %q, want empty string", got)
+	}
+
+	if got := retryPolicyFromJSON(""); got != nil {
+		t.Errorf("retryPolicyFromJSON(\"\") = %v, want nil", got)
+	}
+
+	if got := retryPolicyFromJSON("not json"); got != nil {
+		t.Errorf("retryPolicyFromJSON(invalid) = %v, want nil", got)
+	}
+
+	policy := &RetryPolicy{MaxAttempts: 3, Backoff: RetryBackoffExponential, DelaySeconds: 5}
+	got := retryPolicyFromJSON(retryPolicyToJSON(policy))
+	if got == nil || *got != *policy {
+		t.Errorf("retryPolicyFromJSON(retryPolicyToJSON(%v)) = %v", policy, got)
+	}
+}
@@ -8,8 +8,15 @@ import (
 )
 
 type ScheduleRunnerOptions struct {
+	// IntervalSeconds is how often a started runner checks the schedules
+	// (default: 60). With MaxIntervalSeconds set, it is the minimum interval.
 	IntervalSeconds int
-	Logger          *log.Logger
+	// MaxIntervalSeconds enables an adaptive interval: while no schedule is
+	// due, the interval doubles from IntervalSeconds up to
+	// MaxIntervalSeconds, and it is reset as soon as a schedule runs
+	// (default: 0, checking at a fixed interval)
+	MaxIntervalSeconds int
+	Logger             *log.Logger
 }
 
 type ScheduleRunnerInterface interface {
@@ -18,6 +25,9 @@ type ScheduleRunnerInterface interface {
 	IsRunning() bool
 	RunOnce(ctx context.Context) error
 	SetInitialRuns(ctx context.Context) error
+	// CurrentInterval returns the interval the runner waits between checks,
+	// which grows while no schedule is due (see MaxIntervalSeconds)
+	CurrentInterval() time.Duration
 }
 
 type scheduleRunner struct {
@@ -25,6 +35,7 @@ type scheduleRunner struct {
 	opts    ScheduleRunnerOptions
 	running atomic.Bool
 	stopCh  chan struct{}
+	backoff *pollBackoff // Interval between checks
 }
 
 func NewScheduleRunner(store StoreInterface, opts ScheduleRunnerOptions) ScheduleRunnerInterface {
@@ -33,9 +44,10 @@ func NewScheduleRunner(store StoreInterface, opts ScheduleRunnerOptions) Schedul
 	}
 
 	return &scheduleRunner{
-		store:  store,
-		opts:   opts,
-		stopCh: make(chan struct{}, 1),
+		store:   store,
+		opts:    opts,
+		stopCh:  make(chan struct{}, 1),
+		backoff: newPollBackoff(time.Duration(opts.IntervalSeconds)*time.Second, time.Duration(opts.MaxIntervalSeconds)*time.Second),
 	}
 }
 
@@ -45,8 +57,6 @@ func (r *scheduleRunner) Start(ctx context.Context) {
 	}
 
 	go func() {
-		ticker := time.NewTicker(time.Duration(r.opts.IntervalSeconds) * time.Second)
-		defer ticker.Stop()
 		defer r.running.Store(false)
 
 		for {
@@ -54,16 +64,21 @@ func (r *scheduleRunner) Start(ctx context.Context) {
 				return
 			}
 
-			if err := r.RunOnce(ctx); err != nil {
+			ran, err := r.runOnce(ctx)
+			if err != nil {
 				r.logf("ScheduleRunner: RunOnce error: %v", err)
 			}
 
+			timer := time.NewTimer(r.backoff.next(ran > 0))
+
 			select {
-			case <-ticker.C:
+			case <-timer.C:
 				continue
 			case <-ctx.Done():
+				timer.Stop()
 				return
 			case <-r.stopCh:
+				timer.Stop()
 				return
 			}
 		}
@@ -85,10 +100,20 @@ func (r *scheduleRunner) IsRunning() bool {
 	return r.running.Load()
 }
 
+func (r *scheduleRunner) CurrentInterval() time.Duration {
+	return r.backoff.interval()
+}
+
 func (r *scheduleRunner) RunOnce(ctx context.Context) error {
+	_, err := r.runOnce(ctx)
+	return err
+}
+
+// runOnce is RunOnce, returning the number of schedules due
+func (r *scheduleRunner) runOnce(ctx context.Context) (int, error) {
 	schedules, err := r.findActiveSchedulesToBeRun(ctx)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	for _, s := range schedules {
@@ -97,7 +122,7 @@ func (r *scheduleRunner) RunOnce(ctx context.Context) error {
 		}
 	}
 
-	return nil
+	return len(schedules), nil
 }
 
 func (r *scheduleRunner) SetInitialRuns(ctx context.Context) error {
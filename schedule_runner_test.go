@@ -275,3 +275,41 @@ func TestScheduleRunnerStartStop(t *testing.T) {
 		t.Error("expected runner to be stopped")
 	}
 }
+
+func TestScheduleRunner_AdaptiveInterval(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	store, err := NewStore(NewStoreOptions{
+		TaskDefinitionTableName: "task_definitions",
+		TaskQueueTableName:      "task_queue",
+		ScheduleTableName:       "schedules",
+		DB:                      db,
+		AutomigrateEnabled:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fixed := NewScheduleRunner(store, ScheduleRunnerOptions{})
+	if fixed.CurrentInterval() != 60*time.Second {
+		t.Fatalf("expected the default interval of 60s, got %v", fixed.CurrentInterval())
+	}
+
+	runner := NewScheduleRunner(store, ScheduleRunnerOptions{IntervalSeconds: 1, MaxIntervalSeconds: 4})
+
+	runner.Start(ctx)
+	defer runner.Stop()
+
+	// No schedules are due, so the second check backs off
+	time.Sleep(1300 * time.Millisecond)
+	if runner.CurrentInterval() != 2*time.Second {
+		t.Fatalf("expected the interval to back off to 2s, got %v", runner.CurrentInterval())
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"log/slog"
 	"os"
+	"runtime/debug"
 	"strings"
 	"sync"
 	"time"
@@ -31,6 +32,26 @@ type StoreInterface interface {
 	GetScheduleTableName() string
 	// SetScheduleTableName sets the schedule table name
 	SetScheduleTableName(tableName string)
+	// GetBatchTableName returns the batch table name
+	GetBatchTableName() string
+	// SetBatchTableName sets the batch table name
+	SetBatchTableName(tableName string)
+	// GetQueueTableName returns the queue table name
+	GetQueueTableName() string
+	// SetQueueTableName sets the queue table name
+	SetQueueTableName(tableName string)
+	// GetWorkerTableName returns the worker table name
+	GetWorkerTableName() string
+	// SetWorkerTableName sets the worker table name
+	SetWorkerTableName(tableName string)
+	// GetClaimTableName returns the claim table name
+	GetClaimTableName() string
+	// SetClaimTableName sets the claim table name
+	SetClaimTableName(tableName string)
+
+	// GetNotifier returns the notifier waking up the runners of a queue
+	// when tasks are enqueued
+	GetNotifier() NotifierInterface
 
 	// MigrateDown drops all tables
 	MigrateDown(ctx context.Context, tx ...*sql.Tx) error
@@ -51,11 +72,16 @@ type StoreInterface interface {
 	TaskQueueDelete(ctx context.Context, TaskQueue TaskQueueInterface) error
 	TaskQueueDeleteByID(ctx context.Context, id string) error
 	TaskQueueFindByID(ctx context.Context, TaskQueueID string) (TaskQueueInterface, error)
+	TaskQueueFindByUniqueKey(ctx context.Context, uniqueKey string) (TaskQueueInterface, error)
 	TaskQueueList(ctx context.Context, query TaskQueueQueryInterface) ([]TaskQueueInterface, error)
 	TaskQueueSoftDelete(ctx context.Context, TaskQueue TaskQueueInterface) error
 	TaskQueueSoftDeleteByID(ctx context.Context, id string) error
 	TaskQueueUpdate(ctx context.Context, TaskQueue TaskQueueInterface) error
+	TaskQueueClaimBatch(ctx context.Context, queueName string, n int) ([]TaskQueueInterface, error)
 	TaskQueueClaimNext(ctx context.Context, queueName string) (TaskQueueInterface, error)
+	TaskQueueCancel(ctx context.Context, id string) error
+	TaskQueueHeartbeat(ctx context.Context, queuedTaskID string) error
+	TaskQueueReapExpiredLeases(ctx context.Context, queueName string) (int64, error)
 
 	// Deprecated: Use NewTaskQueueRunner instead. These methods will be removed in a future version.
 	// See docs/runners.md for the recommended approach.
@@ -74,6 +100,40 @@ type StoreInterface interface {
 	TaskQueueStopByName(queueName string)
 	TaskQueueProcessTask(ctx context.Context, queuedTask TaskQueueInterface) (bool, error)
 
+	// == Batch Methods ==
+
+	BatchEnqueue(ctx context.Context, batch BatchInterface, tasks []BatchTask) ([]TaskQueueInterface, error)
+	BatchFindByID(ctx context.Context, id string) (BatchInterface, error)
+
+	// == Queue Methods ==
+
+	QueueGetRateLimit(ctx context.Context, queueName string) (*RateLimit, error)
+	QueueIsPaused(ctx context.Context, queueName string) (bool, error)
+	QueuePause(ctx context.Context, queueName string) error
+	QueuePausedList(ctx context.Context) ([]string, error)
+	QueueResume(ctx context.Context, queueName string) error
+	QueueSetRateLimit(ctx context.Context, queueName string, rateLimit *RateLimit) error
+
+	// == Worker Methods ==
+
+	WorkerDeadTaskList(ctx context.Context) ([]TaskQueueInterface, error)
+	WorkerDeregister(ctx context.Context, workerID string) error
+	WorkerFindByID(ctx context.Context, workerID string) (WorkerInterface, error)
+	WorkerHeartbeat(ctx context.Context, worker WorkerInterface) error
+	WorkerIsAlive(worker WorkerInterface) bool
+	WorkerListDead(ctx context.Context) ([]WorkerInterface, error)
+	WorkerListLive(ctx context.Context) ([]WorkerInterface, error)
+	WorkerPruneDead(ctx context.Context) (int64, error)
+	WorkerRegister(ctx context.Context, worker WorkerInterface) error
+
+	// == Dead Letter Methods ==
+
+	DeadLetterCount(ctx context.Context, query TaskQueueQueryInterface) (int64, error)
+	DeadLetterFindByID(ctx context.Context, id string) (TaskQueueInterface, error)
+	DeadLetterList(ctx context.Context, query TaskQueueQueryInterface) ([]TaskQueueInterface, error)
+	DeadLetterPurge(ctx context.Context, ids []string) (int64, error)
+	DeadLetterReplay(ctx context.Context, ids []string) (int64, error)
+
 	// == TaskDefinition Methods ==
 
 	TaskDefinitionCount(ctx context.Context, options TaskDefinitionQueryInterface) (int64, error)
@@ -89,12 +149,15 @@ type StoreInterface interface {
 
 	// TaskDefinition Operations
 	TaskDefinitionEnqueueByAlias(ctx context.Context, queueName string, alias string, parameters map[string]any) (TaskQueueInterface, error)
+	TaskDefinitionEnqueueByAliasWithOptions(ctx context.Context, queueName string, alias string, parameters map[string]any, options EnqueueOptions) (TaskQueueInterface, error)
 	TaskDefinitionExecuteCli(alias string, args []string) bool
 
 	// == TaskHandler Methods ==
 
 	TaskHandlerList() []TaskDefinitionHandlerInterface
 	TaskHandlerAdd(ctx context.Context, taskHandler TaskDefinitionHandlerInterface, createIfMissing bool) error
+	TaskHandlerAddFactory(ctx context.Context, alias string, factory func() TaskDefinitionHandlerInterface, createIfMissing bool) error
+	TaskHandlerFunc(ctx context.Context, alias string, title string, fn TaskFunc) error
 
 	// == Schedule Methods ==
 
@@ -115,7 +178,12 @@ type Store struct {
 	taskDefinitionTableName string
 	taskQueueTableName      string
 	scheduleTableName       string
+	batchTableName          string
+	queueTableName          string
+	workerTableName         string
+	claimTableName          string
 	taskHandlers            []TaskDefinitionHandlerInterface
+	taskHandlerFactories    []func() TaskDefinitionHandlerInterface // By index of taskHandlers, nil for handlers added as instances
 	db                      *neat.Database
 	automigrateEnabled      bool
 	debugEnabled            bool
@@ -125,6 +193,13 @@ type Store struct {
 	errorHandler            func(queueName, taskID string, err error)
 	logger                  *slog.Logger
 	isSQLite                bool
+	isSQLServer             bool
+	workerID                string        // Identifies the worker claiming tasks, unless set in the context (see ContextWithWorkerID)
+	leaseDuration           time.Duration // How long a claimed task is leased (default: 5m)
+	cancelCheckInterval     time.Duration // How often running tasks check whether they were asked to stop (default: 1s)
+	workerTimeout           time.Duration // How long a worker is alive after its last heartbeat (default: 2m)
+	workerRetention         time.Duration // How long a dead worker stays registered (default: 24h)
+	notifier                NotifierInterface
 }
 
 type queueRunner struct {
@@ -142,11 +217,19 @@ type NewStoreOptions struct {
 	TaskDefinitionTableName string
 	TaskQueueTableName      string
 	ScheduleTableName       string
+	BatchTableName          string // Optional (default: TaskQueueTableName + "_batch")
+	QueueTableName          string // Optional (default: TaskQueueTableName + "_queue")
+	WorkerTableName         string // Optional (default: TaskQueueTableName + "_worker")
+	ClaimTableName          string // Optional (default: TaskQueueTableName + "_claim")
 	DB                      *sql.DB
 	AutomigrateEnabled      bool
 	DebugEnabled            bool
 	MaxConcurrency          int                                       // Max concurrent tasks (default: 10, 0 = unlimited)
 	ErrorHandler            func(queueName, taskID string, err error) // Optional error callback
+	LeaseDuration           time.Duration                             // How long a claimed task is leased, unless extended by a heartbeat (default: 5 minutes)
+	WorkerTimeout           time.Duration                             // How long a worker is considered alive after its last heartbeat (default: 2 minutes)
+	WorkerRetention         time.Duration                             // How long a dead worker stays registered before it is pruned (default: 24 hours)
+	Notifier                NotifierInterface                         // Wakes up runners when tasks are enqueued (default: in-process notifier)
 }
 
 // NewStore creates a new task store
@@ -177,6 +260,10 @@ func NewStore(opts NewStoreOptions) (*Store, error) {
 		taskDefinitionTableName: opts.TaskDefinitionTableName,
 		taskQueueTableName:      opts.TaskQueueTableName,
 		scheduleTableName:       opts.ScheduleTableName,
+		batchTableName:          opts.BatchTableName,
+		queueTableName:          opts.QueueTableName,
+		workerTableName:         opts.WorkerTableName,
+		claimTableName:          opts.ClaimTableName,
 		automigrateEnabled:      opts.AutomigrateEnabled,
 		db:                      neatDB,
 		debugEnabled:            opts.DebugEnabled,
@@ -185,6 +272,45 @@ func NewStore(opts NewStoreOptions) (*Store, error) {
 		errorHandler:            opts.ErrorHandler,
 		logger:                  logger,
 		isSQLite:                strings.Contains(fmt.Sprintf("%T", opts.DB.Driver()), "sqlite"),
+		isSQLServer:             strings.Contains(fmt.Sprintf("%T", opts.DB.Driver()), "mssql"),
+		workerID:                newWorkerID(),
+		leaseDuration:           opts.LeaseDuration,
+		cancelCheckInterval:     time.Second,
+		workerTimeout:           opts.WorkerTimeout,
+		workerRetention:         opts.WorkerRetention,
+		notifier:                opts.Notifier,
+	}
+
+	if store.batchTableName == "" {
+		store.batchTableName = store.taskQueueTableName + "_batch"
+	}
+
+	if store.queueTableName == "" {
+		store.queueTableName = store.taskQueueTableName + "_queue"
+	}
+
+	if store.workerTableName == "" {
+		store.workerTableName = store.taskQueueTableName + "_worker"
+	}
+
+	if store.claimTableName == "" {
+		store.claimTableName = store.taskQueueTableName + "_claim"
+	}
+
+	if store.leaseDuration <= 0 {
+		store.leaseDuration = DefaultLeaseDuration
+	}
+
+	if store.workerTimeout <= 0 {
+		store.workerTimeout = DefaultWorkerTimeout
+	}
+
+	if store.workerRetention <= 0 {
+		store.workerRetention = DefaultWorkerRetention
+	}
+
+	if store.notifier == nil {
+		store.notifier = NewInProcessNotifier()
 	}
 
 	// Set default max concurrency if not specified
@@ -222,6 +348,9 @@ func (st *Store) MigrateUp(ctx context.Context, tx ...*sql.Tx) error {
 			table.DateTime(COLUMN_CREATED_AT)
 			table.DateTime(COLUMN_UPDATED_AT)
 			table.DateTime(COLUMN_SOFT_DELETED_AT)
+			for _, migration := range taskDefinitionColumnMigrations() {
+				migration.define(table)
+			}
 		})
 		if err != nil {
 			if st.debugEnabled {
@@ -231,6 +360,13 @@ func (st *Store) MigrateUp(ctx context.Context, tx ...*sql.Tx) error {
 		}
 	}
 
+	if err := st.migrateColumns(st.taskDefinitionTableName, taskDefinitionColumnMigrations()); err != nil {
+		if st.debugEnabled {
+			st.logger.Error("MigrateUp failed for task_definition columns", "error", err)
+		}
+		return err
+	}
+
 	if st.db.Schema().HasTable(st.taskQueueTableName) {
 		if st.debugEnabled {
 			st.logger.Info("MigrateUp: task_queue table already exists", "table", st.taskQueueTableName)
@@ -251,6 +387,9 @@ func (st *Store) MigrateUp(ctx context.Context, tx ...*sql.Tx) error {
 			table.DateTime(COLUMN_CREATED_AT)
 			table.DateTime(COLUMN_UPDATED_AT)
 			table.DateTime(COLUMN_SOFT_DELETED_AT)
+			for _, migration := range taskQueueColumnMigrations() {
+				migration.define(table)
+			}
 		})
 		if err != nil {
 			if st.debugEnabled {
@@ -260,6 +399,127 @@ func (st *Store) MigrateUp(ctx context.Context, tx ...*sql.Tx) error {
 		}
 	}
 
+	if err := st.migrateColumns(st.taskQueueTableName, taskQueueColumnMigrations()); err != nil {
+		if st.debugEnabled {
+			st.logger.Error("MigrateUp failed for task_queue columns", "error", err)
+		}
+		return err
+	}
+
+	if err := st.migrateUniqueIndex(st.taskQueueTableName, st.taskQueueTableName+"_"+COLUMN_UNIQUE_KEY+"_unique", COLUMN_UNIQUE_KEY); err != nil {
+		if st.debugEnabled {
+			st.logger.Error("MigrateUp failed for task_queue unique key index", "error", err)
+		}
+		return err
+	}
+
+	if st.db.Schema().HasTable(st.batchTableName) {
+		if st.debugEnabled {
+			st.logger.Info("MigrateUp: batch table already exists", "table", st.batchTableName)
+		}
+	} else {
+		err := st.db.Schema().Create(st.batchTableName, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_ID, 50)
+			table.Primary(COLUMN_ID)
+			table.String(COLUMN_STATUS, 50)
+			table.String(COLUMN_QUEUE_NAME, 100)
+			table.Integer(COLUMN_TOTAL_COUNT)
+			table.Integer(COLUMN_PENDING_COUNT)
+			table.Integer(COLUMN_SUCCEEDED_COUNT)
+			table.Integer(COLUMN_FAILED_COUNT)
+			table.String(COLUMN_ON_COMPLETE_ALIAS, 100)
+			table.String(COLUMN_ON_SUCCESS_ALIAS, 100)
+			table.String(COLUMN_ON_FAILURE_ALIAS, 100)
+			table.DateTime(COLUMN_COMPLETED_AT)
+			table.DateTime(COLUMN_CREATED_AT)
+			table.DateTime(COLUMN_UPDATED_AT)
+			table.DateTime(COLUMN_SOFT_DELETED_AT)
+		})
+		if err != nil {
+			if st.debugEnabled {
+				st.logger.Error("MigrateUp failed for batch", "error", err)
+			}
+			return err
+		}
+	}
+
+	if st.db.Schema().HasTable(st.queueTableName) {
+		if st.debugEnabled {
+			st.logger.Info("MigrateUp: queue table already exists", "table", st.queueTableName)
+		}
+	} else {
+		err := st.db.Schema().Create(st.queueTableName, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_QUEUE_NAME, 100)
+			table.Primary(COLUMN_QUEUE_NAME)
+			table.String(COLUMN_STATUS, 50)
+			table.DateTime(COLUMN_PAUSED_AT)
+			table.DateTime(COLUMN_CREATED_AT)
+			table.DateTime(COLUMN_UPDATED_AT)
+			for _, migration := range queueColumnMigrations() {
+				migration.define(table)
+			}
+		})
+		if err != nil {
+			if st.debugEnabled {
+				st.logger.Error("MigrateUp failed for queue", "error", err)
+			}
+			return err
+		}
+	}
+
+	if err := st.migrateColumns(st.queueTableName, queueColumnMigrations()); err != nil {
+		if st.debugEnabled {
+			st.logger.Error("MigrateUp failed for queue columns", "error", err)
+		}
+		return err
+	}
+
+	if st.db.Schema().HasTable(st.workerTableName) {
+		if st.debugEnabled {
+			st.logger.Info("MigrateUp: worker table already exists", "table", st.workerTableName)
+		}
+	} else {
+		err := st.db.Schema().Create(st.workerTableName, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_ID, 255)
+			table.Primary(COLUMN_ID)
+			table.String(COLUMN_QUEUE_NAME, 100)
+			table.Integer(COLUMN_MAX_CONCURRENCY)
+			table.Integer(COLUMN_IN_FLIGHT)
+			table.String(COLUMN_VERSION, 100)
+			table.DateTime(COLUMN_STARTED_AT)
+			table.DateTime(COLUMN_HEARTBEAT_AT)
+			table.DateTime(COLUMN_CREATED_AT)
+			table.DateTime(COLUMN_UPDATED_AT)
+		})
+		if err != nil {
+			if st.debugEnabled {
+				st.logger.Error("MigrateUp failed for worker", "error", err)
+			}
+			return err
+		}
+	}
+
+	if st.db.Schema().HasTable(st.claimTableName) {
+		if st.debugEnabled {
+			st.logger.Info("MigrateUp: claim table already exists", "table", st.claimTableName)
+		}
+	} else {
+		err := st.db.Schema().Create(st.claimTableName, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_ID, 50)
+			table.Primary(COLUMN_ID)
+			table.String(COLUMN_QUEUE_NAME, 100)
+			table.String(COLUMN_TASK_ID, 50)
+			table.String(COLUMN_TASK_QUEUE_ID, 50)
+			table.DateTime(COLUMN_CLAIMED_AT)
+		})
+		if err != nil {
+			if st.debugEnabled {
+				st.logger.Error("MigrateUp failed for claim", "error", err)
+			}
+			return err
+		}
+	}
+
 	if st.db.Schema().HasTable(st.scheduleTableName) {
 		if st.debugEnabled {
 			st.logger.Info("MigrateUp: schedule table already exists", "table", st.scheduleTableName)
@@ -296,6 +556,138 @@ func (st *Store) MigrateUp(ctx context.Context, tx ...*sql.Tx) error {
 	return nil
 }
 
+// columnMigration describes a column added after the initial table layout.
+// It is part of the table when it is created, and is added to existing
+// tables which are missing it.
+type columnMigration struct {
+	column string
+	define func(table contractsschema.Blueprint)
+}
+
+func taskDefinitionColumnMigrations() []columnMigration {
+	return []columnMigration{
+		{COLUMN_RETRY_POLICY, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_RETRY_POLICY, 255).Default("")
+		}},
+		{COLUMN_PRIORITY, func(table contractsschema.Blueprint) {
+			table.Integer(COLUMN_PRIORITY).Default(DefaultPriority)
+		}},
+		{COLUMN_DEAD_LETTER_QUEUE_NAME, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_DEAD_LETTER_QUEUE_NAME, 100).Default("")
+		}},
+		{COLUMN_TIMEOUT_SECONDS, func(table contractsschema.Blueprint) {
+			table.Integer(COLUMN_TIMEOUT_SECONDS).Default(0)
+		}},
+		{COLUMN_RATE_LIMIT, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_RATE_LIMIT, 255).Default("")
+		}},
+		{COLUMN_MAX_CONCURRENCY, func(table contractsschema.Blueprint) {
+			table.Integer(COLUMN_MAX_CONCURRENCY).Default(0)
+		}},
+		{COLUMN_PARAMETER_SCHEMA, func(table contractsschema.Blueprint) {
+			table.Text(COLUMN_PARAMETER_SCHEMA).Nullable()
+		}},
+	}
+}
+
+func queueColumnMigrations() []columnMigration {
+	return []columnMigration{
+		{COLUMN_RATE_LIMIT, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_RATE_LIMIT, 255).Default("")
+		}},
+	}
+}
+
+func taskQueueColumnMigrations() []columnMigration {
+	return []columnMigration{
+		{COLUMN_AVAILABLE_AT, func(table contractsschema.Blueprint) {
+			table.DateTime(COLUMN_AVAILABLE_AT).Default(NULL_DATETIME)
+		}},
+		{COLUMN_RETRY_POLICY, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_RETRY_POLICY, 255).Default("")
+		}},
+		{COLUMN_PRIORITY, func(table contractsschema.Blueprint) {
+			table.Integer(COLUMN_PRIORITY).Default(DefaultPriority)
+		}},
+		{COLUMN_UNIQUE_KEY, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_UNIQUE_KEY, 255).Nullable()
+		}},
+		{COLUMN_UNIQUE_UNTIL, func(table contractsschema.Blueprint) {
+			table.DateTime(COLUMN_UNIQUE_UNTIL).Default(NULL_DATETIME)
+		}},
+		{COLUMN_DEPENDS_ON, func(table contractsschema.Blueprint) {
+			table.Text(COLUMN_DEPENDS_ON).Nullable()
+		}},
+		{COLUMN_DEPENDENCY_FAILURE_POLICY, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_DEPENDENCY_FAILURE_POLICY, 50).Default("")
+		}},
+		{COLUMN_BATCH_ID, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_BATCH_ID, 50).Default("")
+		}},
+		{COLUMN_DEAD_LETTER_QUEUE_NAME, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_DEAD_LETTER_QUEUE_NAME, 100).Default("")
+		}},
+		{COLUMN_DEAD_LETTERED_AT, func(table contractsschema.Blueprint) {
+			table.DateTime(COLUMN_DEAD_LETTERED_AT).Default(NULL_DATETIME)
+		}},
+		{COLUMN_TIMEOUT_SECONDS, func(table contractsschema.Blueprint) {
+			table.Integer(COLUMN_TIMEOUT_SECONDS).Default(0)
+		}},
+		{COLUMN_LEASE_OWNER, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_LEASE_OWNER, 255).Default("")
+		}},
+		{COLUMN_LEASE_EXPIRES_AT, func(table contractsschema.Blueprint) {
+			table.DateTime(COLUMN_LEASE_EXPIRES_AT).Default(NULL_DATETIME)
+		}},
+		{COLUMN_WORKER_ID, func(table contractsschema.Blueprint) {
+			table.String(COLUMN_WORKER_ID, 255).Default("")
+		}},
+		{COLUMN_CLAIMED_AT, func(table contractsschema.Blueprint) {
+			table.DateTime(COLUMN_CLAIMED_AT).Default(NULL_DATETIME)
+		}},
+		{COLUMN_ERROR_MESSAGE, func(table contractsschema.Blueprint) {
+			table.Text(COLUMN_ERROR_MESSAGE).Nullable()
+		}},
+		{COLUMN_CANCEL_REQUESTED_AT, func(table contractsschema.Blueprint) {
+			table.DateTime(COLUMN_CANCEL_REQUESTED_AT).Default(NULL_DATETIME)
+		}},
+	}
+}
+
+// migrateColumns adds the columns missing from an existing table
+func (st *Store) migrateColumns(tableName string, migrations []columnMigration) error {
+	for _, migration := range migrations {
+		if st.db.Schema().HasColumn(tableName, migration.column) {
+			continue
+		}
+
+		if err := st.db.Schema().Table(tableName, migration.define); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateUniqueIndex creates a unique index on the column, if missing.
+//
+// The index is created with plain SQL, as it must be enforced by every
+// database. Rows without a value (NULL) must not conflict with each other,
+// which most databases allow in a unique index. SQL Server allows a single
+// NULL, so there the index is filtered to the rows with a value.
+func (st *Store) migrateUniqueIndex(tableName string, indexName string, column string) error {
+	if st.db.Schema().HasIndex(tableName, indexName) {
+		return nil
+	}
+
+	sql := "CREATE UNIQUE INDEX " + indexName + " ON " + tableName + " (" + column + ")"
+	if st.isSQLServer {
+		sql += " WHERE " + column + " IS NOT NULL"
+	}
+
+	return st.db.Schema().Sql(sql)
+}
+
 // MigrateDown drops all tables
 func (st *Store) MigrateDown(ctx context.Context, tx ...*sql.Tx) error {
 	if st.db.Schema().HasTable(st.scheduleTableName) {
@@ -307,6 +699,42 @@ func (st *Store) MigrateDown(ctx context.Context, tx ...*sql.Tx) error {
 		}
 	}
 
+	if st.db.Schema().HasTable(st.claimTableName) {
+		if err := st.db.Schema().Drop(st.claimTableName); err != nil {
+			if st.debugEnabled {
+				st.logger.Error("MigrateDown failed for claim", "error", err)
+			}
+			return err
+		}
+	}
+
+	if st.db.Schema().HasTable(st.workerTableName) {
+		if err := st.db.Schema().Drop(st.workerTableName); err != nil {
+			if st.debugEnabled {
+				st.logger.Error("MigrateDown failed for worker", "error", err)
+			}
+			return err
+		}
+	}
+
+	if st.db.Schema().HasTable(st.queueTableName) {
+		if err := st.db.Schema().Drop(st.queueTableName); err != nil {
+			if st.debugEnabled {
+				st.logger.Error("MigrateDown failed for queue", "error", err)
+			}
+			return err
+		}
+	}
+
+	if st.db.Schema().HasTable(st.batchTableName) {
+		if err := st.db.Schema().Drop(st.batchTableName); err != nil {
+			if st.debugEnabled {
+				st.logger.Error("MigrateDown failed for batch", "error", err)
+			}
+			return err
+		}
+	}
+
 	if st.db.Schema().HasTable(st.taskQueueTableName) {
 		if err := st.db.Schema().Drop(st.taskQueueTableName); err != nil {
 			if st.debugEnabled {
@@ -377,6 +805,52 @@ func (st *Store) SetScheduleTableName(tableName string) {
 	st.scheduleTableName = tableName
 }
 
+// GetBatchTableName returns the batch table name
+func (st *Store) GetBatchTableName() string {
+	return st.batchTableName
+}
+
+// SetBatchTableName sets the batch table name
+func (st *Store) SetBatchTableName(tableName string) {
+	st.batchTableName = tableName
+}
+
+// GetQueueTableName returns the queue table name
+func (st *Store) GetQueueTableName() string {
+	return st.queueTableName
+}
+
+// SetQueueTableName sets the queue table name
+func (st *Store) SetQueueTableName(tableName string) {
+	st.queueTableName = tableName
+}
+
+// GetWorkerTableName returns the worker table name
+func (st *Store) GetWorkerTableName() string {
+	return st.workerTableName
+}
+
+// SetWorkerTableName sets the worker table name
+func (st *Store) SetWorkerTableName(tableName string) {
+	st.workerTableName = tableName
+}
+
+// GetClaimTableName returns the claim table name
+func (st *Store) GetClaimTableName() string {
+	return st.claimTableName
+}
+
+// SetClaimTableName sets the claim table name
+func (st *Store) SetClaimTableName(tableName string) {
+	st.claimTableName = tableName
+}
+
+// GetNotifier returns the notifier waking up the runners of a queue when
+// tasks are enqueued (see NewStoreOptions.Notifier)
+func (st *Store) GetNotifier() NotifierInterface {
+	return st.notifier
+}
+
 // SetErrorHandler - sets a custom error handler for queue processing errors
 func (st *Store) SetErrorHandler(handler func(queueName, taskID string, err error)) StoreInterface {
 	st.errorHandler = handler
@@ -399,6 +873,8 @@ func (store *Store) TaskQueueRunDefault(
 // TaskQueueRunSerial starts a queue processor that handles tasks one at a time (serially).
 // Each task must complete before the next one starts.
 // The processor runs in a background goroutine and can be stopped via TaskQueueStopByName.
+// Tasks whose lease expired are reaped before each claim. unstuckMinutes is
+// no longer used.
 //
 // Deprecated: Use NewTaskQueueRunner instead. This method will be removed in a future version.
 // See docs/runners.md for the recommended approach.
@@ -437,13 +913,15 @@ func (store *Store) TaskQueueRunSerial(
 			runner.wg.Done()
 		}()
 
-		store.queueRunLoopSync(runCtx, queueName, processSeconds, unstuckMinutes)
+		store.queueRunLoopSync(runCtx, queueName, processSeconds)
 	}()
 }
 
 // TaskQueueRunConcurrent starts a queue processor that handles multiple tasks concurrently.
 // Tasks are processed in parallel up to the configured MaxConcurrency limit.
 // The processor runs in a background goroutine and can be stopped via TaskQueueStopByName.
+// Tasks whose lease expired are reaped before each claim. unstuckMinutes is
+// no longer used.
 //
 // Deprecated: Use NewTaskQueueRunner instead. This method will be removed in a future version.
 // See docs/runners.md for the recommended approach.
@@ -486,7 +964,7 @@ func (store *Store) TaskQueueRunConcurrent(
 			runner.wg.Done()
 		}()
 
-		store.queueRunLoopAsync(runCtx, queueName, processSeconds, unstuckMinutes, runner)
+		store.queueRunLoopAsync(runCtx, queueName, processSeconds, runner)
 	}()
 }
 
@@ -494,14 +972,13 @@ func (store *Store) queueRunLoopSync(
 	ctx context.Context,
 	queueName string,
 	processSeconds int,
-	unstuckMinutes int,
 ) {
 	if processSeconds <= 0 {
 		processSeconds = 10
 	}
-	if unstuckMinutes <= 0 {
-		unstuckMinutes = 1
-	}
+
+	wakeups, unsubscribe := store.notifier.Subscribe(queueName)
+	defer unsubscribe()
 
 	for {
 		select {
@@ -510,17 +987,15 @@ func (store *Store) queueRunLoopSync(
 		default:
 		}
 
-		store.TaskQueueUnstuckByQueue(ctx, queueName, unstuckMinutes)
-
-		if !sleepWithContext(ctx, time.Second) {
-			return
+		if _, err := store.TaskQueueReapExpiredLeases(ctx, queueName); err != nil && store.debugEnabled {
+			log.Println("TaskQueueReapExpiredLeases error:", err)
 		}
 
 		if err := store.TaskQueueProcessNextByQueue(ctx, queueName); err != nil && store.debugEnabled {
 			log.Println("TaskQueueProcessNext error:", err)
 		}
 
-		if !sleepWithContext(ctx, time.Duration(processSeconds)*time.Second) {
+		if !sleepOrWakeWithContext(ctx, time.Duration(processSeconds)*time.Second, wakeups) {
 			return
 		}
 	}
@@ -530,15 +1005,14 @@ func (store *Store) queueRunLoopAsync(
 	ctx context.Context,
 	queueName string,
 	processSeconds int,
-	unstuckMinutes int,
 	runner *queueRunner,
 ) {
 	if processSeconds <= 0 {
 		processSeconds = 10
 	}
-	if unstuckMinutes <= 0 {
-		unstuckMinutes = 1
-	}
+
+	wakeups, unsubscribe := store.notifier.Subscribe(queueName)
+	defer unsubscribe()
 
 	// When context is done, wait for all tasks to complete
 	defer func() {
@@ -552,10 +1026,8 @@ func (store *Store) queueRunLoopAsync(
 		default:
 		}
 
-		store.TaskQueueUnstuckByQueue(ctx, queueName, unstuckMinutes)
-
-		if !sleepWithContext(ctx, time.Second) {
-			return
+		if _, err := store.TaskQueueReapExpiredLeases(ctx, queueName); err != nil && store.debugEnabled {
+			log.Println("TaskQueueReapExpiredLeases error:", err)
 		}
 
 		// Acquire semaphore slot (blocks if at max concurrency)
@@ -581,7 +1053,7 @@ func (store *Store) queueRunLoopAsync(
 
 		if nextTask == nil {
 			<-runner.semaphore // Release slot when no task available
-			if !sleepWithContext(ctx, time.Duration(processSeconds)*time.Second) {
+			if !sleepOrWakeWithContext(ctx, time.Duration(processSeconds)*time.Second, wakeups) {
 				return
 			}
 			continue
@@ -608,7 +1080,7 @@ func (store *Store) queueRunLoopAsync(
 			}
 		}(nextTask)
 
-		if !sleepWithContext(ctx, time.Duration(processSeconds)*time.Second) {
+		if !sleepOrWakeWithContext(ctx, time.Duration(processSeconds)*time.Second, wakeups) {
 			return
 		}
 	}
@@ -635,6 +1107,22 @@ func sleepWithContext(ctx context.Context, d time.Duration) bool {
 	}
 }
 
+// sleepOrWakeWithContext is sleepWithContext, but also returns (true) as
+// soon as a wakeup is received, i.e. when a task is enqueued
+func sleepOrWakeWithContext(ctx context.Context, d time.Duration, wakeups <-chan struct{}) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	case <-wakeups:
+		return true
+	}
+}
+
 // TaskQueueStop stops the default queue processor.
 // It blocks until the worker goroutine and all tasks have fully completed.
 //
@@ -687,10 +1175,17 @@ func (store *Store) TaskQueueStopByName(queueName string) {
 // 1. Checks is there are running tasks in progress
 // 2. If running for more than the specified wait minutes mark as failed
 // =================================================================
+//
+// Deprecated: Running tasks are leased, and reaped once their lease expired.
+// Use TaskQueueReapExpiredLeases instead.
 func (store *Store) TaskQueueUnstuck(ctx context.Context, waitMinutes int) {
 	store.TaskQueueUnstuckByQueue(ctx, "", waitMinutes)
 }
 
+// TaskQueueUnstuckByQueue is TaskQueueUnstuck for a single queue.
+//
+// Deprecated: Running tasks are leased, and reaped once their lease expired.
+// Use TaskQueueReapExpiredLeases instead.
 func (store *Store) TaskQueueUnstuckByQueue(ctx context.Context, queueName string, waitMinutes int) {
 	runningTasks := store.TaskQueueFindRunningByQueue(ctx, queueName, 3)
 
@@ -715,19 +1210,35 @@ func (store *Store) QueuedTaskProcessWithContext(ctx context.Context, queuedTask
 		return false, errors.New("queued task is nil")
 	}
 
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
 	attempts := queuedTask.GetAttempts() + 1
 
 	queuedTask.AppendDetails("Task started")
 	queuedTask.SetStatus(TaskQueueStatusRunning)
 	queuedTask.SetAttempts(attempts)
 	queuedTask.SetStartedAt(carbon.Now(carbon.UTC).StdTime())
+	workerID := store.workerIDFromContext(ctx)
+	if isNullTime(queuedTask.GetClaimedAt()) || queuedTask.GetWorkerID() != workerID {
+		queuedTask.SetClaimedAt(carbon.Now(carbon.UTC).StdTime())
+	}
+	queuedTask.SetWorkerID(workerID)
+	queuedTask.SetLeaseOwner(workerID)
+	queuedTask.SetLeaseExpiresAt(carbon.Now(carbon.UTC).StdTime().Add(store.leaseDuration))
 
-	err := store.TaskQueueUpdate(ctx, queuedTask)
+	started, err := store.taskQueueUpdateUnlessCanceled(ctx, queuedTask)
 
 	if err != nil {
 		return false, err
 	}
 
+	// Canceled after it was claimed, but before it started
+	if !started {
+		return false, store.queuedTaskCanceled(ctx, queuedTask)
+	}
+
 	// 2. Find task definition
 	task, err := store.TaskDefinitionFindByID(ctx, queuedTask.GetTaskID())
 
@@ -739,7 +1250,7 @@ func (store *Store) QueuedTaskProcessWithContext(ctx context.Context, queuedTask
 		queuedTask.AppendDetails("Task DOES NOT exist")
 		queuedTask.SetStatus(TaskQueueStatusFailed)
 		queuedTask.SetCompletedAt(carbon.Now(carbon.UTC).StdTime())
-		err = store.TaskQueueUpdate(ctx, queuedTask)
+		err = store.queuedTaskFinish(ctx, queuedTask)
 
 		if err != nil {
 			if store.debugEnabled {
@@ -752,34 +1263,322 @@ func (store *Store) QueuedTaskProcessWithContext(ctx context.Context, queuedTask
 		return false, nil
 	}
 
-	// 3. Get handler and check if it supports context
-	handlerFunc := store.taskHandlerFuncWithContext(task.GetAlias(), ctx)
+	// 3. Get handler and check if it supports context. The handler context
+	// is canceled when the queued task is asked to stop (see
+	// TaskQueueCancel), or when its lease is lost. The lease is extended
+	// while it runs.
+	handlerCtx, cancelHandler := context.WithCancelCause(ctx)
+	defer cancelHandler(nil)
+
+	go store.queuedTaskHeartbeat(handlerCtx, queuedTask.GetID(), cancelHandler)
+
+	// The handler context has a deadline, when a timeout is set
+	timeout := queuedTaskTimeout(task, queuedTask)
+	if timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		handlerCtx, cancelTimeout = context.WithTimeoutCause(handlerCtx, timeout, ErrTaskQueueTimedOut)
+		defer cancelTimeout()
+	}
 
-	result := handlerFunc(queuedTask)
+	handlerFunc := store.taskHandlerFuncWithContext(task.GetAlias(), handlerCtx)
 
-	if result {
-		queuedTask.AppendDetails("Task completed")
-		err = store.TaskQueueSuccess(ctx, queuedTask)
+	result, returned, handlerErr := queuedTaskRunHandler(handlerCtx, timeout, handlerFunc, queuedTask)
 
+	// The handler still runs after it timed out. It keeps the queued task
+	// it was given, so the outcome is recorded on a fresh copy, with the
+	// details the handler logged until then.
+	if !returned {
+		details := queuedTask.GetDetails()
+		queuedTask, err = store.TaskQueueFindByID(ctx, queuedTask.GetID())
 		if err != nil {
-			if store.debugEnabled {
-				log.Println(err)
-			}
+			return true, err
+		}
+		if queuedTask == nil {
+			return true, errors.New("queued task not found")
 		}
+		queuedTask.SetDetails(details)
+	}
+
+	if errors.Is(context.Cause(handlerCtx), ErrTaskQueueCanceled) || errors.Is(context.Cause(handlerCtx), ErrTaskQueueLeaseLost) {
+		err = store.queuedTaskCanceled(ctx, queuedTask)
+	} else if errors.Is(context.Cause(handlerCtx), ErrTaskQueueTimedOut) {
+		queuedTask.AppendDetails("Task timed out after " + timeout.String())
+		queuedTask.SetErrorMessage(ErrTaskQueueTimedOut.Error())
+		err = store.queuedTaskFailOrRetry(ctx, task, queuedTask)
+	} else if errors.Is(handlerErr, ErrTaskQueuePanicked) {
+		err = store.queuedTaskPanicked(ctx, task, queuedTask, handlerErr)
+	} else if result {
+		queuedTask.AppendDetails("Task completed")
+		queuedTask.SetCompletedAt(carbon.Now(carbon.UTC).StdTime())
+		queuedTask.SetStatus(TaskQueueStatusSuccess)
+		queuedTask.SetErrorMessage("")
+		err = store.queuedTaskFinish(ctx, queuedTask)
+	} else if handlerErr != nil {
+		err = store.queuedTaskErrored(ctx, task, queuedTask, handlerErr)
 	} else {
-		queuedTask.AppendDetails("Task failed")
-		err = store.TaskQueueFail(ctx, queuedTask)
+		err = store.queuedTaskFailOrRetry(ctx, task, queuedTask)
+	}
 
-		if err != nil {
-			if store.debugEnabled {
-				log.Println(err)
-			}
+	if err != nil {
+		if store.debugEnabled {
+			log.Println(err)
 		}
 	}
 
 	return true, nil
 }
 
+// ErrTaskQueueCanceled is the cause of the cancellation of the context
+// passed to TaskHandlerWithContext.HandleWithContext, when the queued task
+// is canceled (see TaskQueueCancel). Use context.Cause(ctx) to check it.
+var ErrTaskQueueCanceled = errors.New("queued task canceled")
+
+// ErrTaskQueueTimedOut is the cause of the cancellation of the context
+// passed to TaskHandlerWithContext.HandleWithContext, when the queued task
+// runs longer than its timeout. Use context.Cause(ctx) to check it.
+var ErrTaskQueueTimedOut = errors.New("queued task timed out")
+
+// ErrTaskQueuePanicked is wrapped by the error passed to the error handler
+// (see SetErrorHandler), when the handler of a queued task panics
+var ErrTaskQueuePanicked = errors.New("queued task panicked")
+
+// queuedTaskPanicked marks a queued task, which handler panicked, as
+// permanently failed, recording the panic value and the stack trace in its
+// details. The error handler is called with panicErr, if one is set.
+func (store *Store) queuedTaskPanicked(ctx context.Context, task TaskDefinitionInterface, queuedTask TaskQueueInterface, panicErr error) error {
+	details := "Task panicked: " + panicErr.Error()
+	var taskPanic *taskPanicError
+	if errors.As(panicErr, &taskPanic) {
+		details = fmt.Sprintf("Task panicked: %v\n%s", taskPanic.value, taskPanic.stack)
+	}
+	queuedTask.AppendDetails(details)
+	queuedTask.SetErrorMessage(panicErr.Error())
+
+	if marker, ok := queuedTask.(permanentFailureMarker); ok {
+		marker.setPermanentFailure(true)
+	}
+
+	if store.errorHandler != nil {
+		store.errorHandler(queuedTask.GetQueueName(), queuedTask.GetID(), panicErr)
+	} else if store.debugEnabled {
+		store.logger.Error("queued task panicked", "id", queuedTask.GetID(), "error", panicErr)
+	}
+
+	return store.queuedTaskFailOrRetry(ctx, task, queuedTask)
+}
+
+// queuedTaskErrored marks a queued task, which handler returned the error
+// handlerErr, as failed or queued for another attempt. The error is
+// recorded in its error message, and the error handler is called with it,
+// if one is set. A permanent error (see PermanentError) is not retried.
+func (store *Store) queuedTaskErrored(ctx context.Context, task TaskDefinitionInterface, queuedTask TaskQueueInterface, handlerErr error) error {
+	queuedTask.AppendDetails("Task failed with error: " + handlerErr.Error())
+	queuedTask.SetErrorMessage(handlerErr.Error())
+
+	if IsPermanentError(handlerErr) {
+		if marker, ok := queuedTask.(permanentFailureMarker); ok {
+			marker.setPermanentFailure(true)
+		}
+	}
+
+	if store.errorHandler != nil {
+		store.errorHandler(queuedTask.GetQueueName(), queuedTask.GetID(), handlerErr)
+	} else if store.debugEnabled {
+		log.Println("QueuedTaskProcess error:", handlerErr)
+	}
+
+	return store.queuedTaskFailOrRetry(ctx, task, queuedTask)
+}
+
+// queuedTaskTimeout returns the execution timeout of the queued task,
+// which is the timeout of the queued task, or else the one of its task
+// definition. 0 means no timeout.
+func queuedTaskTimeout(task TaskDefinitionInterface, queuedTask TaskQueueInterface) time.Duration {
+	if queuedTask.GetTimeoutSeconds() > 0 {
+		return time.Duration(queuedTask.GetTimeoutSeconds()) * time.Second
+	}
+
+	return time.Duration(task.GetTimeoutSeconds()) * time.Second
+}
+
+// queuedTaskRunHandler runs the handler of the queued task.
+//
+// Business logic:
+//   - without a timeout the handler runs in the calling goroutine
+//   - with a timeout the handler runs in its own goroutine. Once ctx times
+//     out the handler is abandoned and returned is false. A handler which
+//     does not watch ctx keeps running, but what it returns is ignored
+//   - when ctx is canceled for another reason (the task was canceled, or
+//     the worker stops) the handler is waited for, as before
+//   - the error returned by the handler, if any, is returned. A panic of
+//     the handler is recovered, and returned as an error wrapping
+//     ErrTaskQueuePanicked
+func queuedTaskRunHandler(ctx context.Context, timeout time.Duration, handlerFunc func(queuedTask TaskQueueInterface) (bool, error), queuedTask TaskQueueInterface) (result bool, returned bool, handlerErr error) {
+	if timeout <= 0 {
+		result, handlerErr = queuedTaskCallHandler(handlerFunc, queuedTask)
+		return result, true, handlerErr
+	}
+
+	type outcome struct {
+		result     bool
+		handlerErr error
+	}
+
+	done := make(chan outcome, 1)
+	go func() {
+		result, handlerErr := queuedTaskCallHandler(handlerFunc, queuedTask)
+		done <- outcome{result: result, handlerErr: handlerErr}
+	}()
+
+	select {
+	case o := <-done:
+		return o.result, true, o.handlerErr
+	case <-ctx.Done():
+	}
+
+	if errors.Is(context.Cause(ctx), ErrTaskQueueTimedOut) {
+		select {
+		case o := <-done:
+			return o.result, true, o.handlerErr
+		default:
+			return false, false, nil
+		}
+	}
+
+	o := <-done
+	return o.result, true, o.handlerErr
+}
+
+// queuedTaskCallHandler calls the handler, recovering from a panic.
+// The panic value and the stack trace are returned as an error wrapping
+// ErrTaskQueuePanicked.
+func queuedTaskCallHandler(handlerFunc func(queuedTask TaskQueueInterface) (bool, error), queuedTask TaskQueueInterface) (result bool, handlerErr error) {
+	defer func() {
+		if value := recover(); value != nil {
+			result = false
+			handlerErr = &taskPanicError{value: value, stack: debug.Stack()}
+		}
+	}()
+
+	return handlerFunc(queuedTask)
+}
+
+// taskPanicError is the error of a handler which panicked
+type taskPanicError struct {
+	value any
+	stack []byte
+}
+
+func (e *taskPanicError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrTaskQueuePanicked, e.value)
+}
+
+func (e *taskPanicError) Unwrap() error {
+	return ErrTaskQueuePanicked
+}
+
+// queuedTaskFinish saves the outcome of a processed queued task, releases
+// its lease, and runs the completion hooks of a succeeded or failed task.
+// The outcome is only saved while the task is still running under the
+// lease of this store, so a task which was reaped meanwhile keeps its
+// status, and a task which was asked to stop is canceled instead.
+func (store *Store) queuedTaskFinish(ctx context.Context, queuedTask TaskQueueInterface) error {
+	queuedTask.SetLeaseOwner("")
+	queuedTask.SetLeaseExpiresAt(time.Time{})
+
+	saved, err := store.taskQueueUpdateIfLeaseHeld(ctx, queuedTask, store.workerIDFromContext(ctx), time.Time{})
+	if err != nil {
+		return err
+	}
+
+	if !saved {
+		return store.queuedTaskCanceled(ctx, queuedTask)
+	}
+
+	if queuedTask.IsSuccess() || queuedTask.IsFailed() {
+		return store.taskQueueCompleted(ctx, queuedTask)
+	}
+
+	return nil
+}
+
+// queuedTaskCanceled records the cancellation of a queued task which was
+// asked to stop while it was processed (see TaskQueueCancel), keeping the
+// details of its run, and releases its lease. The tasks depending on it,
+// and its batch, are only updated then. Nothing is recorded if the task
+// was not asked to stop, or is no longer running under the lease of the
+// worker.
+func (store *Store) queuedTaskCanceled(ctx context.Context, queuedTask TaskQueueInterface) error {
+	current, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		return err
+	}
+
+	workerID := store.workerIDFromContext(ctx)
+	if current == nil || !current.IsCancelRequested() || current.GetLeaseOwner() != workerID {
+		return nil
+	}
+
+	queuedTask.AppendDetails("Task canceled")
+	queuedTask.SetStatus(TaskQueueStatusCanceled)
+	queuedTask.SetCompletedAt(carbon.Now(carbon.UTC).StdTime())
+	queuedTask.SetLeaseOwner("")
+	queuedTask.SetLeaseExpiresAt(time.Time{})
+
+	saved, err := store.taskQueueUpdateIfLeaseHeld(ctx, queuedTask, workerID, time.Time{})
+	if err != nil || !saved {
+		return err
+	}
+
+	return store.taskQueueCompleted(ctx, queuedTask)
+}
+
+// queuedTaskFailOrRetry fails a queued task whose handler did not succeed,
+// or puts it back in the queue when its retry policy allows another attempt
+// (see queuedTaskSetFailedOrRetry), and saves the outcome.
+func (store *Store) queuedTaskFailOrRetry(ctx context.Context, task TaskDefinitionInterface, queuedTask TaskQueueInterface) error {
+	queuedTaskSetFailedOrRetry(task, queuedTask)
+	return store.queuedTaskFinish(ctx, queuedTask)
+}
+
+// queuedTaskSetFailedOrRetry marks a queued task as failed, or as queued
+// for another attempt when its retry policy allows one. It is not saved.
+// The retry policy of the queued task takes precedence over the one of
+// its task definition. A task which failed for good is moved to the dead
+// letter queue of its task definition, if it has one.
+func queuedTaskSetFailedOrRetry(task TaskDefinitionInterface, queuedTask TaskQueueInterface) {
+	policy := queuedTask.GetRetryPolicy()
+	if policy == nil {
+		policy = task.GetRetryPolicy()
+	}
+
+	isPermanent := false
+	if marker, ok := queuedTask.(permanentFailureMarker); ok {
+		isPermanent = marker.isPermanentFailure()
+		marker.setPermanentFailure(false)
+	}
+
+	attempts := queuedTask.GetAttempts()
+
+	if policy == nil || isPermanent || !policy.CanRetry(attempts) {
+		queuedTask.AppendDetails("Task failed")
+		if task.GetDeadLetterQueueName() != "" {
+			queuedTask.SetDeadLetterQueueName(task.GetDeadLetterQueueName())
+			queuedTask.SetDeadLetteredAt(carbon.Now(carbon.UTC).StdTime())
+			queuedTask.AppendDetails("Task moved to dead letter queue " + task.GetDeadLetterQueueName())
+		}
+		queuedTask.SetCompletedAt(carbon.Now(carbon.UTC).StdTime())
+		queuedTask.SetStatus(TaskQueueStatusFailed)
+		return
+	}
+
+	delay := policy.NextDelay(attempts)
+	queuedTask.AppendDetails(fmt.Sprintf("Task failed. Retry %d of %d scheduled in %s", attempts, policy.MaxAttempts-1, delay))
+	queuedTask.SetStatus(TaskQueueStatusQueued)
+	queuedTask.SetAvailableAt(carbon.Now(carbon.UTC).StdTime().Add(delay))
+	queuedTask.SetCompletedAt(time.Time{})
+}
+
 // TaskDefinitionExecuteCli - CLI tool to find a task by its alias and execute its handler
 // - alias "list" is reserved. it lists all the available commands
 func (store *Store) TaskDefinitionExecuteCli(alias string, args []string) bool {
@@ -798,8 +1597,9 @@ func (store *Store) TaskDefinitionExecuteCli(alias string, args []string) bool {
 	}
 
 	// Finds the task and executes its handler
-	for _, taskHandler := range store.TaskHandlerList() {
+	for index, taskHandler := range store.TaskHandlerList() {
 		if strings.EqualFold(unifyName(taskHandler.Alias()), unifyName(alias)) {
+			taskHandler = store.taskHandlerForExecution(index)
 			taskHandler.SetOptions(argumentsMap)
 			taskHandler.Handle()
 			return true
@@ -817,35 +1617,44 @@ func unifyName(name string) string {
 }
 
 // taskHandlerFuncWithContext finds the TaskHandler and returns a function that
-// checks if the handler implements TaskHandlerWithContext. If it does, it calls
-// HandleWithContext(ctx), otherwise it falls back to Handle() for backward compatibility.
-func (store *Store) taskHandlerFuncWithContext(taskAlias string, ctx context.Context) func(queuedTask TaskQueueInterface) bool {
+// calls HandleE(ctx) if the handler implements TaskHandlerWithError, or else
+// HandleWithContext(ctx) if it implements TaskHandlerWithContext, otherwise it
+// falls back to Handle() for backward compatibility.
+// Handlers added with a factory get a fresh instance for each call.
+func (store *Store) taskHandlerFuncWithContext(taskAlias string, ctx context.Context) func(queuedTask TaskQueueInterface) (bool, error) {
 	unifyName := func(name string) string {
 		name = strings.ReplaceAll(name, "-", "")
 		name = strings.ReplaceAll(name, "_", "")
 		return name
 	}
 
-	for _, taskHandler := range store.taskHandlers {
-		if strings.EqualFold(unifyName(taskHandler.Alias()), unifyName(taskAlias)) {
-			return func(queuedTask TaskQueueInterface) bool {
+	for index, registeredHandler := range store.taskHandlers {
+		if strings.EqualFold(unifyName(registeredHandler.Alias()), unifyName(taskAlias)) {
+			return func(queuedTask TaskQueueInterface) (bool, error) {
+				taskHandler := store.taskHandlerForExecution(index)
 				taskHandler.SetQueuedTask(queuedTask)
 
+				// Check if handler implements TaskHandlerWithError
+				if errorHandler, ok := taskHandler.(TaskHandlerWithError); ok {
+					err := errorHandler.HandleE(ctx)
+					return err == nil, err
+				}
+
 				// Check if handler implements TaskHandlerWithContext
 				if contextHandler, ok := taskHandler.(TaskHandlerWithContext); ok {
-					return contextHandler.HandleWithContext(ctx)
+					return contextHandler.HandleWithContext(ctx), nil
 				}
 
 				// Fall back to standard Handle() for backward compatibility
-				return taskHandler.Handle()
+				return taskHandler.Handle(), nil
 			}
 		}
 	}
 
-	return func(queuedTask TaskQueueInterface) bool {
+	return func(queuedTask TaskQueueInterface) (bool, error) {
 		queuedTask.AppendDetails("No handler for alias: " + taskAlias)
 		_ = store.TaskQueueUpdate(ctx, queuedTask)
-		return false
+		return false, nil
 	}
 }
 
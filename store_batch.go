@@ -0,0 +1,287 @@
+package taskstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/dromara/carbon/v2"
+	"github.com/spf13/cast"
+)
+
+// BatchTask is a task to enqueue as part of a batch
+type BatchTask struct {
+	// Alias is the alias of the task definition to enqueue
+	Alias string
+
+	// Parameters are the parameters of the queued task
+	Parameters map[string]any
+
+	// Options are the enqueue options of the queued task. Optional.
+	Options EnqueueOptions
+}
+
+// BatchEnqueue creates the batch and enqueues its tasks to the queue of the
+// batch. Once all the tasks finished, the callback task definitions of the
+// batch are enqueued, with the batch summary as parameters.
+//
+// Business logic:
+//   - all the aliases are checked before anything is created
+//   - batch tasks cannot have a unique key, as a task deduplicated to an
+//     existing queued task would not be part of the batch
+//   - the batch is pending while its tasks are enqueued, so it does not
+//     complete before all of them are enqueued
+//   - if a task cannot be enqueued, the tasks already enqueued are
+//     canceled, and the batch fails (without its callbacks)
+//   - a batch without tasks completes immediately
+func (store *Store) BatchEnqueue(ctx context.Context, batch BatchInterface, tasks []BatchTask) ([]TaskQueueInterface, error) {
+	if batch == nil {
+		return nil, errors.New("batch is nil")
+	}
+
+	aliases := []string{batch.GetOnCompleteAlias(), batch.GetOnSuccessAlias(), batch.GetOnFailureAlias()}
+	for _, task := range tasks {
+		if task.Options.UniqueKey != "" {
+			return nil, errors.New("batch task with alias '" + task.Alias + "' cannot have a unique key")
+		}
+		aliases = append(aliases, task.Alias)
+	}
+
+	for _, alias := range aliases {
+		if alias == "" {
+			continue
+		}
+
+		definition, err := store.TaskDefinitionFindByAlias(ctx, alias)
+		if err != nil {
+			return nil, err
+		}
+		if definition == nil {
+			return nil, errors.New("task with alias '" + alias + "' not found")
+		}
+	}
+
+	batch.SetStatus(BatchStatusPending)
+	if err := store.batchCreate(ctx, batch); err != nil {
+		return nil, err
+	}
+
+	queuedTasks := make([]TaskQueueInterface, 0, len(tasks))
+	for _, task := range tasks {
+		options := task.Options
+		options.batchID = batch.GetID()
+
+		queuedTask, err := store.TaskDefinitionEnqueueByAliasWithOptions(ctx, batch.GetQueueName(), task.Alias, task.Parameters, options)
+		if err != nil {
+			if failErr := store.batchFail(ctx, batch, queuedTasks); failErr != nil {
+				return nil, errors.Join(err, failErr)
+			}
+			return nil, err
+		}
+		queuedTasks = append(queuedTasks, queuedTask)
+	}
+
+	batch.SetStatus(BatchStatusRunning)
+	if err := store.batchUpdate(ctx, batch); err != nil {
+		return queuedTasks, err
+	}
+
+	// Some tasks may have finished while the others were enqueued
+	if err := store.batchResolve(ctx, batch.GetID()); err != nil {
+		return queuedTasks, err
+	}
+
+	return queuedTasks, nil
+}
+
+// BatchFindByID finds a batch by ID
+func (store *Store) BatchFindByID(ctx context.Context, id string) (BatchInterface, error) {
+	if id == "" {
+		return nil, errors.New("batch id is empty")
+	}
+
+	var found batch
+	err := store.db.Query().
+		Model(&batch{}).
+		Table(store.batchTableName).
+		Where(COLUMN_ID+" = ?", id).
+		First(&found)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || err.Error() == "no rows found" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &found, nil
+}
+
+func (store *Store) batchCreate(ctx context.Context, batch BatchInterface) error {
+	if batch.GetCreatedAt().IsZero() {
+		batch.SetCreatedAt(carbon.Now(carbon.UTC).StdTime())
+	}
+	if batch.GetUpdatedAt().IsZero() {
+		batch.SetUpdatedAt(carbon.Now(carbon.UTC).StdTime())
+	}
+
+	row := batchToRow(batch)
+	row[COLUMN_ID] = batch.GetID()
+	row[COLUMN_CREATED_AT] = batch.GetCreatedAt().Format("2006-01-02 15:04:05")
+
+	return store.db.Query().Table(store.batchTableName).Create(row)
+}
+
+func (store *Store) batchUpdate(ctx context.Context, batch BatchInterface) error {
+	batch.SetUpdatedAt(carbon.Now(carbon.UTC).StdTime())
+
+	_, err := store.db.Query().
+		Table(store.batchTableName).
+		Where(COLUMN_ID+" = ?", batch.GetID()).
+		Update(batchToRow(batch))
+	return err
+}
+
+// batchResolve recounts the tasks of a running batch. Once all of them
+// finished, the batch completes and its callback tasks are enqueued.
+func (store *Store) batchResolve(ctx context.Context, batchID string) error {
+	batch, err := store.BatchFindByID(ctx, batchID)
+	if err != nil {
+		return err
+	}
+	if batch == nil || !batch.IsRunning() {
+		return nil
+	}
+
+	if err := store.batchCount(ctx, batch); err != nil {
+		return err
+	}
+
+	if batch.GetPendingCount() == 0 {
+		status := BatchStatusSuccess
+		if batch.GetFailedCount() > 0 {
+			status = BatchStatusFailed
+		}
+
+		batch.SetStatus(status).SetCompletedAt(carbon.Now(carbon.UTC).StdTime())
+	}
+
+	batch.SetUpdatedAt(carbon.Now(carbon.UTC).StdTime())
+
+	// Only the first of concurrent resolvers completes the batch, so the
+	// callbacks are enqueued once, and stale counts do not reopen it
+	result, err := store.db.Query().
+		Table(store.batchTableName).
+		Where(COLUMN_ID+" = ?", batch.GetID()).
+		Where(COLUMN_STATUS+" = ?", BatchStatusRunning).
+		Update(batchToRow(batch))
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected < 1 || !batch.IsCompleted() {
+		return nil
+	}
+
+	return store.batchEnqueueCallbacks(ctx, batch)
+}
+
+// batchCount sets the counts of a batch from the status of its tasks
+func (store *Store) batchCount(ctx context.Context, batch BatchInterface) error {
+	total, err := store.TaskQueueCount(ctx, TaskQueueQuery().SetBatchID(batch.GetID()))
+	if err != nil {
+		return err
+	}
+
+	succeeded, err := store.TaskQueueCount(ctx, TaskQueueQuery().
+		SetBatchID(batch.GetID()).
+		SetStatus(TaskQueueStatusSuccess))
+	if err != nil {
+		return err
+	}
+
+	failed, err := store.TaskQueueCount(ctx, TaskQueueQuery().
+		SetBatchID(batch.GetID()).
+		SetStatusIn([]string{TaskQueueStatusFailed, TaskQueueStatusCanceled}))
+	if err != nil {
+		return err
+	}
+
+	batch.SetTotalCount(int(total)).
+		SetSucceededCount(int(succeeded)).
+		SetFailedCount(int(failed)).
+		SetPendingCount(int(total - succeeded - failed))
+
+	return nil
+}
+
+// batchFail fails a pending batch whose tasks could not all be enqueued.
+// The tasks already enqueued are canceled (unless they finished
+// meanwhile), and the counts of the batch are those of its tasks.
+func (store *Store) batchFail(ctx context.Context, batch BatchInterface, queuedTasks []TaskQueueInterface) error {
+	for _, queuedTask := range queuedTasks {
+		current, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+		if err != nil {
+			return err
+		}
+		if current == nil || current.IsSuccess() || current.IsFailed() || current.IsCanceled() {
+			continue
+		}
+
+		if err := store.TaskQueueCancel(ctx, queuedTask.GetID()); err != nil {
+			return err
+		}
+	}
+
+	if err := store.batchCount(ctx, batch); err != nil {
+		return err
+	}
+
+	batch.SetStatus(BatchStatusFailed).SetCompletedAt(carbon.Now(carbon.UTC).StdTime())
+
+	return store.batchUpdate(ctx, batch)
+}
+
+// batchEnqueueCallbacks enqueues the callback tasks of a completed batch
+func (store *Store) batchEnqueueCallbacks(ctx context.Context, batch BatchInterface) error {
+	parameters := map[string]any{
+		"batch_id":        batch.GetID(),
+		"batch_status":    batch.GetStatus(),
+		"total_count":     cast.ToString(batch.GetTotalCount()),
+		"succeeded_count": cast.ToString(batch.GetSucceededCount()),
+		"failed_count":    cast.ToString(batch.GetFailedCount()),
+	}
+
+	aliases := []string{batch.GetOnCompleteAlias()}
+	if batch.IsSuccess() {
+		aliases = append(aliases, batch.GetOnSuccessAlias())
+	} else {
+		aliases = append(aliases, batch.GetOnFailureAlias())
+	}
+
+	for _, alias := range aliases {
+		if alias == "" {
+			continue
+		}
+
+		if _, err := store.TaskDefinitionEnqueueByAlias(ctx, batch.GetQueueName(), alias, parameters); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func batchToRow(batch BatchInterface) map[string]any {
+	return map[string]any{
+		COLUMN_STATUS:            batch.GetStatus(),
+		COLUMN_QUEUE_NAME:        batch.GetQueueName(),
+		COLUMN_TOTAL_COUNT:       batch.GetTotalCount(),
+		COLUMN_PENDING_COUNT:     batch.GetPendingCount(),
+		COLUMN_SUCCEEDED_COUNT:   batch.GetSucceededCount(),
+		COLUMN_FAILED_COUNT:      batch.GetFailedCount(),
+		COLUMN_ON_COMPLETE_ALIAS: batch.GetOnCompleteAlias(),
+		COLUMN_ON_SUCCESS_ALIAS:  batch.GetOnSuccessAlias(),
+		COLUMN_ON_FAILURE_ALIAS:  batch.GetOnFailureAlias(),
+		COLUMN_COMPLETED_AT:      batch.GetCompletedAt().Format("2006-01-02 15:04:05"),
+		COLUMN_UPDATED_AT:        batch.GetUpdatedAt().Format("2006-01-02 15:04:05"),
+		COLUMN_SOFT_DELETED_AT:   batch.GetSoftDeletedAt().Format("2006-01-02 15:04:05"),
+	}
+}
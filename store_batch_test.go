@@ -0,0 +1,257 @@
+package taskstore
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_Store_BatchEnqueue(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("BatchEnqueue: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := newTestTaskHandler()
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	for _, alias := range []string{"BatchCompleted", "BatchSucceeded", "BatchFailed"} {
+		definition := NewTaskDefinition().SetAlias(alias).SetTitle(alias)
+		if err := store.TaskDefinitionCreate(ctx, definition); err != nil {
+			t.Fatalf("TaskDefinitionCreate: Error[%v]", err)
+		}
+	}
+
+	newBatch := func() BatchInterface {
+		return NewBatch().
+			SetOnCompleteAlias("BatchCompleted").
+			SetOnSuccessAlias("BatchSucceeded").
+			SetOnFailureAlias("BatchFailed")
+	}
+
+	batchTasks := func(n int) []BatchTask {
+		tasks := make([]BatchTask, n)
+		for i := range tasks {
+			tasks[i] = BatchTask{Alias: handler.Alias(), Parameters: map[string]any{}}
+		}
+		return tasks
+	}
+
+	callbacks := func(batchID string) []string {
+		t.Helper()
+		queued, err := store.TaskQueueList(ctx, TaskQueueQuery().SetStatus(TaskQueueStatusQueued))
+		if err != nil {
+			t.Fatalf("TaskQueueList: Error[%v]", err)
+		}
+
+		aliases := []string{}
+		for _, queuedTask := range queued {
+			parameters, err := queuedTask.ParametersMap()
+			if err != nil {
+				t.Fatalf("ParametersMap: Error[%v]", err)
+			}
+			if parameters["batch_id"] == batchID {
+				aliases = append(aliases, parameters["task_alias"])
+			}
+		}
+		return aliases
+	}
+
+	findBatch := func(batchID string) BatchInterface {
+		t.Helper()
+		batch, err := store.BatchFindByID(ctx, batchID)
+		if err != nil {
+			t.Fatalf("BatchFindByID: Error[%v]", err)
+		}
+		if batch == nil {
+			t.Fatalf("BatchFindByID: Expected batch %s, got nil", batchID)
+		}
+		return batch
+	}
+
+	t.Run("all tasks succeed", func(t *testing.T) {
+		batch := newBatch()
+		queuedTasks, err := store.BatchEnqueue(ctx, batch, batchTasks(3))
+		if err != nil {
+			t.Fatalf("BatchEnqueue: Error[%v]", err)
+		}
+		if len(queuedTasks) != 3 {
+			t.Fatalf("Expected 3 queued tasks, got %d", len(queuedTasks))
+		}
+		for _, queuedTask := range queuedTasks {
+			if queuedTask.GetBatchID() != batch.GetID() {
+				t.Fatalf("Expected batch ID %s, got %s", batch.GetID(), queuedTask.GetBatchID())
+			}
+		}
+
+		dbBatch := findBatch(batch.GetID())
+		if !dbBatch.IsRunning() || dbBatch.GetTotalCount() != 3 || dbBatch.GetPendingCount() != 3 {
+			t.Fatalf("Expected a running batch with 3 pending tasks, got %s with %d/%d", dbBatch.GetStatus(), dbBatch.GetPendingCount(), dbBatch.GetTotalCount())
+		}
+
+		if err := store.TaskQueueSuccess(ctx, queuedTasks[0]); err != nil {
+			t.Fatalf("TaskQueueSuccess: Error[%v]", err)
+		}
+
+		dbBatch = findBatch(batch.GetID())
+		if dbBatch.GetPendingCount() != 2 || dbBatch.GetSucceededCount() != 1 {
+			t.Fatalf("Expected 2 pending and 1 succeeded, got %d and %d", dbBatch.GetPendingCount(), dbBatch.GetSucceededCount())
+		}
+		if len(callbacks(batch.GetID())) != 0 {
+			t.Fatal("Expected no callbacks before the batch completed")
+		}
+
+		for _, queuedTask := range queuedTasks[1:] {
+			if err := store.TaskQueueSuccess(ctx, queuedTask); err != nil {
+				t.Fatalf("TaskQueueSuccess: Error[%v]", err)
+			}
+		}
+
+		dbBatch = findBatch(batch.GetID())
+		if !dbBatch.IsSuccess() || dbBatch.GetSucceededCount() != 3 || dbBatch.GetPendingCount() != 0 {
+			t.Fatalf("Expected a successful batch, got %s with %d succeeded", dbBatch.GetStatus(), dbBatch.GetSucceededCount())
+		}
+		if dbBatch.GetCompletedAt().IsZero() {
+			t.Fatal("Expected the batch completed at to be set")
+		}
+
+		aliases := callbacks(batch.GetID())
+		if len(aliases) != 2 {
+			t.Fatalf("Expected the complete and success callbacks, got %v", aliases)
+		}
+		for _, alias := range aliases {
+			if alias != "BatchCompleted" && alias != "BatchSucceeded" {
+				t.Fatalf("Unexpected callback %s", alias)
+			}
+		}
+	})
+
+	t.Run("a task fails", func(t *testing.T) {
+		batch := newBatch()
+		queuedTasks, err := store.BatchEnqueue(ctx, batch, batchTasks(2))
+		if err != nil {
+			t.Fatalf("BatchEnqueue: Error[%v]", err)
+		}
+
+		if err := store.TaskQueueFail(ctx, queuedTasks[0]); err != nil {
+			t.Fatalf("TaskQueueFail: Error[%v]", err)
+		}
+		if err := store.TaskQueueSuccess(ctx, queuedTasks[1]); err != nil {
+			t.Fatalf("TaskQueueSuccess: Error[%v]", err)
+		}
+
+		dbBatch := findBatch(batch.GetID())
+		if !dbBatch.IsFailed() || dbBatch.GetFailedCount() != 1 || dbBatch.GetSucceededCount() != 1 {
+			t.Fatalf("Expected a failed batch, got %s with %d failed", dbBatch.GetStatus(), dbBatch.GetFailedCount())
+		}
+
+		aliases := callbacks(batch.GetID())
+		if len(aliases) != 2 {
+			t.Fatalf("Expected the complete and failure callbacks, got %v", aliases)
+		}
+		for _, alias := range aliases {
+			if alias != "BatchCompleted" && alias != "BatchFailed" {
+				t.Fatalf("Unexpected callback %s", alias)
+			}
+		}
+	})
+
+	t.Run("callbacks are enqueued once", func(t *testing.T) {
+		batch := newBatch()
+		queuedTasks, err := store.BatchEnqueue(ctx, batch, batchTasks(1))
+		if err != nil {
+			t.Fatalf("BatchEnqueue: Error[%v]", err)
+		}
+
+		if err := store.TaskQueueSuccess(ctx, queuedTasks[0]); err != nil {
+			t.Fatalf("TaskQueueSuccess: Error[%v]", err)
+		}
+		if err := store.batchResolve(ctx, batch.GetID()); err != nil {
+			t.Fatalf("batchResolve: Error[%v]", err)
+		}
+
+		if aliases := callbacks(batch.GetID()); len(aliases) != 2 {
+			t.Fatalf("Expected 2 callbacks, got %v", aliases)
+		}
+	})
+
+	t.Run("empty batch completes immediately", func(t *testing.T) {
+		batch := newBatch()
+		if _, err := store.BatchEnqueue(ctx, batch, []BatchTask{}); err != nil {
+			t.Fatalf("BatchEnqueue: Error[%v]", err)
+		}
+
+		if !findBatch(batch.GetID()).IsSuccess() {
+			t.Fatal("Expected the empty batch to succeed")
+		}
+	})
+
+	t.Run("unknown alias creates nothing", func(t *testing.T) {
+		batch := newBatch()
+		tasks := append(batchTasks(1), BatchTask{Alias: "UnknownAlias"})
+		if _, err := store.BatchEnqueue(ctx, batch, tasks); err == nil {
+			t.Fatal("Expected an error for an unknown alias")
+		}
+
+		dbBatch, err := store.BatchFindByID(ctx, batch.GetID())
+		if err != nil {
+			t.Fatalf("BatchFindByID: Error[%v]", err)
+		}
+		if dbBatch != nil {
+			t.Fatal("Expected no batch to be created")
+		}
+	})
+
+	t.Run("unique key creates nothing", func(t *testing.T) {
+		batch := newBatch()
+		tasks := batchTasks(2)
+		tasks[1].Options.UniqueKey = "batch-unique"
+		if _, err := store.BatchEnqueue(ctx, batch, tasks); err == nil {
+			t.Fatal("Expected an error for a unique key")
+		}
+
+		dbBatch, err := store.BatchFindByID(ctx, batch.GetID())
+		if err != nil {
+			t.Fatalf("BatchFindByID: Error[%v]", err)
+		}
+		if dbBatch != nil {
+			t.Fatal("Expected no batch to be created")
+		}
+	})
+
+	t.Run("failed enqueue fails the batch", func(t *testing.T) {
+		batch := newBatch()
+		tasks := batchTasks(3)
+		tasks[2].Options.DependsOn = []string{"unknown-task-id"}
+		if _, err := store.BatchEnqueue(ctx, batch, tasks); err == nil {
+			t.Fatal("Expected an error for an unknown dependency")
+		}
+
+		dbBatch := findBatch(batch.GetID())
+		if dbBatch.GetStatus() != BatchStatusFailed {
+			t.Fatalf("Expected status %s, got %s", BatchStatusFailed, dbBatch.GetStatus())
+		}
+		if dbBatch.GetTotalCount() != 2 || dbBatch.GetFailedCount() != 2 || dbBatch.GetPendingCount() != 0 {
+			t.Fatalf("Expected 2 total, 2 failed and 0 pending, got %d, %d and %d",
+				dbBatch.GetTotalCount(), dbBatch.GetFailedCount(), dbBatch.GetPendingCount())
+		}
+
+		enqueued, err := store.TaskQueueList(ctx, TaskQueueQuery().SetBatchID(batch.GetID()))
+		if err != nil {
+			t.Fatalf("TaskQueueList: Error[%v]", err)
+		}
+		for _, queuedTask := range enqueued {
+			if !queuedTask.IsCanceled() {
+				t.Fatalf("Expected task %s to be canceled, got %s", queuedTask.GetID(), queuedTask.GetStatus())
+			}
+		}
+
+		if aliases := callbacks(batch.GetID()); len(aliases) != 0 {
+			t.Fatalf("Expected no callbacks, got %v", aliases)
+		}
+	})
+}
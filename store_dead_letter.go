@@ -0,0 +1,141 @@
+package taskstore
+
+import (
+	"context"
+	"time"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	"github.com/dromara/carbon/v2"
+)
+
+// DeadLetterCount returns the number of dead letters matching the query.
+// Use SetDeadLetterQueueName to count a single dead letter queue.
+func (store *Store) DeadLetterCount(ctx context.Context, query TaskQueueQueryInterface) (int64, error) {
+	if query == nil {
+		query = TaskQueueQuery()
+	}
+
+	if err := query.Validate(); err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err := store.buildDeadLetterQuery(query).Count(&count)
+	return count, err
+}
+
+// DeadLetterFindByID finds a dead letter by ID.
+// Returns (nil, nil) if the queued task does not exist or is not a dead letter.
+func (store *Store) DeadLetterFindByID(ctx context.Context, id string) (TaskQueueInterface, error) {
+	queuedTask, err := store.TaskQueueFindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if queuedTask == nil || queuedTask.GetDeadLetterQueueName() == "" {
+		return nil, nil
+	}
+
+	return queuedTask, nil
+}
+
+// DeadLetterList returns the dead letters matching the query.
+// Use SetDeadLetterQueueName to list a single dead letter queue.
+func (store *Store) DeadLetterList(ctx context.Context, query TaskQueueQueryInterface) ([]TaskQueueInterface, error) {
+	if query == nil {
+		query = TaskQueueQuery()
+	}
+
+	if err := query.Validate(); err != nil {
+		return []TaskQueueInterface{}, err
+	}
+
+	q := store.buildDeadLetterQuery(query)
+	if !query.HasOrderBy() {
+		q = q.OrderBy(COLUMN_DEAD_LETTERED_AT, DESC)
+	}
+
+	var queues []taskQueue
+	if err := q.Get(&queues); err != nil {
+		return []TaskQueueInterface{}, err
+	}
+
+	list := make([]TaskQueueInterface, len(queues))
+	for i, que := range queues {
+		queue := que
+		list[i] = &queue
+	}
+	return list, nil
+}
+
+// DeadLetterPurge permanently deletes the dead letters with the given IDs.
+// IDs of queued tasks which are not dead letters are ignored.
+// Returns the number of deleted dead letters.
+func (store *Store) DeadLetterPurge(ctx context.Context, ids []string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	result, err := store.db.Query().
+		Table(store.taskQueueTableName).
+		WhereIn(COLUMN_ID, args).
+		Where(COLUMN_DEAD_LETTER_QUEUE_NAME+" <> ?", "").
+		Delete()
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected, nil
+}
+
+// DeadLetterReplay puts the dead letters with the given IDs back in their
+// queue, as new attempts with a fresh retry budget. IDs of queued tasks
+// which are not dead letters are ignored.
+// Returns the number of replayed dead letters.
+func (store *Store) DeadLetterReplay(ctx context.Context, ids []string) (int64, error) {
+	replayed := int64(0)
+
+	for _, id := range ids {
+		queuedTask, err := store.DeadLetterFindByID(ctx, id)
+		if err != nil {
+			return replayed, err
+		}
+		if queuedTask == nil {
+			continue
+		}
+
+		queuedTask.AppendDetails("Task replayed from dead letter queue " + queuedTask.GetDeadLetterQueueName())
+		queuedTask.SetStatus(TaskQueueStatusQueued).
+			SetAttempts(0).
+			SetAvailableAt(carbon.Now(carbon.UTC).StdTime()).
+			SetStartedAt(time.Time{}).
+			SetCompletedAt(time.Time{}).
+			SetDeadLetterQueueName("").
+			SetDeadLetteredAt(time.Time{})
+
+		if err := store.TaskQueueUpdate(ctx, queuedTask); err != nil {
+			return replayed, err
+		}
+
+		store.notifier.Notify(queuedTask.GetQueueName())
+
+		replayed++
+	}
+
+	return replayed, nil
+}
+
+func (store *Store) buildDeadLetterQuery(query TaskQueueQueryInterface) contractsorm.Query {
+	q := store.buildTaskQueueQuery(query).Table(store.taskQueueTableName)
+
+	if !query.HasDeadLetterQueueName() {
+		q = q.Where(COLUMN_DEAD_LETTER_QUEUE_NAME+" <> ?", "")
+	}
+
+	return q
+}
@@ -0,0 +1,203 @@
+package taskstore
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func Test_Store_DeadLetter(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("DeadLetter: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := newTestTaskHandler()
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	definition, err := store.TaskDefinitionFindByAlias(ctx, handler.Alias())
+	if err != nil {
+		t.Fatalf("TaskDefinitionFindByAlias: Error[%v]", err)
+	}
+	definition.SetDeadLetterQueueName("poison").
+		SetRetryPolicy(&RetryPolicy{MaxAttempts: 2})
+	if err := store.TaskDefinitionUpdate(ctx, definition); err != nil {
+		t.Fatalf("TaskDefinitionUpdate: Error[%v]", err)
+	}
+
+	failTask := func() TaskQueueInterface {
+		t.Helper()
+		queuedTask, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{
+			"completeWithFail": "yes",
+		})
+		if err != nil {
+			t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+		}
+
+		for range 2 {
+			dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+			if err != nil {
+				t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+			}
+			if _, err := store.QueuedTaskProcessWithContext(ctx, dbTask); err != nil {
+				t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+			}
+		}
+		return queuedTask
+	}
+
+	t.Run("exhausted tasks are dead lettered", func(t *testing.T) {
+		queuedTask, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{
+			"completeWithFail": "yes",
+		})
+		if err != nil {
+			t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+		}
+
+		// The first failure is retried, so it is not a dead letter yet
+		if _, err := store.QueuedTaskProcessWithContext(ctx, queuedTask); err != nil {
+			t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+		}
+		deadLetter, err := store.DeadLetterFindByID(ctx, queuedTask.GetID())
+		if err != nil {
+			t.Fatalf("DeadLetterFindByID: Error[%v]", err)
+		}
+		if deadLetter != nil {
+			t.Fatal("Expected a task with attempts left not to be a dead letter")
+		}
+
+		dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+		if err != nil {
+			t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+		}
+		if _, err := store.QueuedTaskProcessWithContext(ctx, dbTask); err != nil {
+			t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+		}
+
+		deadLetter, err = store.DeadLetterFindByID(ctx, queuedTask.GetID())
+		if err != nil {
+			t.Fatalf("DeadLetterFindByID: Error[%v]", err)
+		}
+		if deadLetter == nil {
+			t.Fatal("Expected the exhausted task to be a dead letter")
+		}
+		if deadLetter.GetDeadLetterQueueName() != "poison" {
+			t.Fatalf("Expected dead letter queue poison, got %s", deadLetter.GetDeadLetterQueueName())
+		}
+		if deadLetter.GetStatus() != TaskQueueStatusFailed {
+			t.Fatalf("Expected status %s, got %s", TaskQueueStatusFailed, deadLetter.GetStatus())
+		}
+		if isNullTime(deadLetter.GetDeadLetteredAt()) {
+			t.Fatal("Expected dead lettered at to be set")
+		}
+		if !strings.Contains(deadLetter.GetDetails(), "Task moved to dead letter queue poison") {
+			t.Fatalf("Expected details to mention the dead letter queue, got %s", deadLetter.GetDetails())
+		}
+	})
+
+	t.Run("list and count", func(t *testing.T) {
+		failTask()
+
+		// A failed task without a dead letter queue is not listed
+		other := NewTaskQueue().SetTaskID("OTHER").SetStatus(TaskQueueStatusFailed)
+		if err := store.TaskQueueCreate(ctx, other); err != nil {
+			t.Fatalf("TaskQueueCreate: Error[%v]", err)
+		}
+
+		list, err := store.DeadLetterList(ctx, TaskQueueQuery())
+		if err != nil {
+			t.Fatalf("DeadLetterList: Error[%v]", err)
+		}
+		if len(list) != 2 {
+			t.Fatalf("Expected 2 dead letters, got %d", len(list))
+		}
+
+		count, err := store.DeadLetterCount(ctx, TaskQueueQuery().SetDeadLetterQueueName("poison"))
+		if err != nil {
+			t.Fatalf("DeadLetterCount: Error[%v]", err)
+		}
+		if count != 2 {
+			t.Fatalf("Expected 2 dead letters in poison, got %d", count)
+		}
+
+		count, err = store.DeadLetterCount(ctx, TaskQueueQuery().SetDeadLetterQueueName("other"))
+		if err != nil {
+			t.Fatalf("DeadLetterCount: Error[%v]", err)
+		}
+		if count != 0 {
+			t.Fatalf("Expected no dead letters in other, got %d", count)
+		}
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		queuedTask := failTask()
+
+		replayed, err := store.DeadLetterReplay(ctx, []string{queuedTask.GetID(), "UNKNOWN"})
+		if err != nil {
+			t.Fatalf("DeadLetterReplay: Error[%v]", err)
+		}
+		if replayed != 1 {
+			t.Fatalf("Expected 1 replayed dead letter, got %d", replayed)
+		}
+
+		dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+		if err != nil {
+			t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+		}
+		if dbTask.GetStatus() != TaskQueueStatusQueued {
+			t.Fatalf("Expected status %s, got %s", TaskQueueStatusQueued, dbTask.GetStatus())
+		}
+		if dbTask.GetAttempts() != 0 {
+			t.Fatalf("Expected the attempts to be reset, got %d", dbTask.GetAttempts())
+		}
+		if dbTask.GetDeadLetterQueueName() != "" {
+			t.Fatalf("Expected the task not to be a dead letter, got %s", dbTask.GetDeadLetterQueueName())
+		}
+
+		claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+		if err != nil {
+			t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+		}
+		if claimedTask == nil || claimedTask.GetID() != queuedTask.GetID() {
+			t.Fatalf("Expected the replayed task to be claimed, got %v", claimedTask)
+		}
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		queuedTask := failTask()
+
+		other := NewTaskQueue().SetTaskID("OTHER").SetStatus(TaskQueueStatusFailed)
+		if err := store.TaskQueueCreate(ctx, other); err != nil {
+			t.Fatalf("TaskQueueCreate: Error[%v]", err)
+		}
+
+		purged, err := store.DeadLetterPurge(ctx, []string{queuedTask.GetID(), other.GetID()})
+		if err != nil {
+			t.Fatalf("DeadLetterPurge: Error[%v]", err)
+		}
+		if purged != 1 {
+			t.Fatalf("Expected 1 purged dead letter, got %d", purged)
+		}
+
+		dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+		if err != nil {
+			t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+		}
+		if dbTask != nil {
+			t.Fatal("Expected the dead letter to be deleted")
+		}
+
+		dbOther, err := store.TaskQueueFindByID(ctx, other.GetID())
+		if err != nil {
+			t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+		}
+		if dbOther == nil {
+			t.Fatal("Expected the task which is not a dead letter to be kept")
+		}
+	})
+}
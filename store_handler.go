@@ -3,11 +3,96 @@ package taskstore
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 )
 
 var errTaskMissing = errors.New("task not found")
 
+// TaskHandlerAdd registers a handler instance for the task definition with
+// its alias. The instance is shared by all the executions of the task
+// definition, so tasks running concurrently overwrite each other's queued
+// task on it. Use TaskHandlerAddFactory for handlers of tasks running
+// concurrently.
 func (store *Store) TaskHandlerAdd(ctx context.Context, taskHandler TaskDefinitionHandlerInterface, createIfMissing bool) error {
+	if err := store.taskHandlerEnsureDefinition(ctx, taskHandler, createIfMissing); err != nil {
+		return err
+	}
+
+	store.taskHandlers = append(store.taskHandlers, taskHandler)
+	store.taskHandlerFactories = append(store.taskHandlerFactories, nil)
+
+	return nil
+}
+
+// TaskHandlerAddFactory registers a handler factory for the task definition
+// with the alias. Each execution of a queued task (and of the CLI) gets a
+// fresh handler from the factory, so tasks of the same task definition can
+// run concurrently. The factory is called once when registering, to check
+// the alias of its handlers, and to create the task definition if missing.
+func (store *Store) TaskHandlerAddFactory(ctx context.Context, alias string, factory func() TaskDefinitionHandlerInterface, createIfMissing bool) error {
+	if factory == nil {
+		return errors.New("task handler factory is nil")
+	}
+
+	taskHandler := factory()
+	if taskHandler == nil {
+		return errors.New("task handler factory returned nil")
+	}
+
+	if !strings.EqualFold(unifyName(taskHandler.Alias()), unifyName(alias)) {
+		return fmt.Errorf("task handler factory creates handlers with alias %q, expected %q", taskHandler.Alias(), alias)
+	}
+
+	if err := store.taskHandlerEnsureDefinition(ctx, taskHandler, createIfMissing); err != nil {
+		return err
+	}
+
+	store.taskHandlers = append(store.taskHandlers, taskHandler)
+	store.taskHandlerFactories = append(store.taskHandlerFactories, factory)
+
+	return nil
+}
+
+// TaskHandlerFunc registers a function as the handler of the task
+// definition with the alias, creating the task definition with the title if
+// missing. The function gets a fresh TaskContext for each execution, so
+// tasks of the same task definition can run concurrently.
+func (store *Store) TaskHandlerFunc(ctx context.Context, alias string, title string, fn TaskFunc) error {
+	if fn == nil {
+		return errors.New("task handler function is nil")
+	}
+
+	return store.TaskHandlerAddFactory(ctx, alias, func() TaskDefinitionHandlerInterface {
+		return &funcTaskHandler{store: store, alias: alias, title: title, fn: fn}
+	}, true)
+}
+
+// taskHandlerForExecution returns the handler registered at the index, or
+// a fresh handler when it was registered with a factory
+func (store *Store) taskHandlerForExecution(index int) TaskDefinitionHandlerInterface {
+	if index < len(store.taskHandlerFactories) && store.taskHandlerFactories[index] != nil {
+		if taskHandler := store.taskHandlerFactories[index](); taskHandler != nil {
+			return taskHandler
+		}
+	}
+
+	return store.taskHandlers[index]
+}
+
+// taskHandlerEnsureDefinition checks the task definition of the handler
+// exists, creating it when missing if createIfMissing is true. The parameter
+// schema declared by the handler, if any, is stored with the task definition.
+func (store *Store) taskHandlerEnsureDefinition(ctx context.Context, taskHandler TaskDefinitionHandlerInterface, createIfMissing bool) error {
+	var schema []TaskParameter
+	parametersHandler, declaresParameters := taskHandler.(TaskHandlerWithParameters)
+	if declaresParameters {
+		schema = parametersHandler.Parameters()
+		if err := validateParameterSchema(schema); err != nil {
+			return err
+		}
+	}
+
 	alias := taskHandler.Alias()
 	task, err := store.TaskDefinitionFindByAlias(ctx, alias)
 
@@ -28,16 +113,23 @@ func (store *Store) TaskHandlerAdd(ctx context.Context, taskHandler TaskDefiniti
 			SetStatus(TaskDefinitionStatusActive).
 			SetAlias(alias).
 			SetTitle(title).
-			SetDescription(description)
+			SetDescription(description).
+			SetParameterSchema(schema)
 
 		err := store.TaskDefinitionCreate(ctx, task)
 
 		if err != nil {
 			return err
 		}
+
+		return nil
 	}
 
-	store.taskHandlers = append(store.taskHandlers, taskHandler)
+	// The declared parameters may have changed since it was created
+	if declaresParameters && parameterSchemaToJSON(task.GetParameterSchema()) != parameterSchemaToJSON(schema) {
+		task.SetParameterSchema(schema)
+		return store.TaskDefinitionUpdate(ctx, task)
+	}
 
 	return nil
 }
@@ -2,7 +2,11 @@ package taskstore
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func Test_Store_TaskHandlerList(t *testing.T) {
@@ -137,3 +141,136 @@ func (h *testHandler2) Handle() bool {
 }
 
 var _ TaskDefinitionHandlerInterface = (*testHandler2)(nil)
+
+func Test_Store_TaskHandlerAddFactory(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskHandlerAddFactory: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	if err := store.TaskHandlerAddFactory(ctx, "TestHandlerAlias", nil, true); err == nil {
+		t.Fatal("TaskHandlerAddFactory: Expected an error for a nil factory")
+	}
+
+	factory := func() TaskDefinitionHandlerInterface { return new(testHandler) }
+	if err := store.TaskHandlerAddFactory(ctx, "OtherAlias", factory, true); err == nil {
+		t.Fatal("TaskHandlerAddFactory: Expected an error for a mismatched alias")
+	}
+
+	if err := store.TaskHandlerAddFactory(ctx, "TestHandlerAlias", factory, false); err != errTaskMissing {
+		t.Fatalf("TaskHandlerAddFactory: Expected errTaskMissing, got %v", err)
+	}
+
+	if err := store.TaskHandlerAddFactory(ctx, "TestHandlerAlias", factory, true); err != nil {
+		t.Fatalf("TaskHandlerAddFactory: Error[%v]", err)
+	}
+
+	definition, err := store.TaskDefinitionFindByAlias(ctx, "TestHandlerAlias")
+	if err != nil {
+		t.Fatalf("TaskDefinitionFindByAlias: Error[%v]", err)
+	}
+	if definition == nil {
+		t.Fatal("TaskHandlerAddFactory: Expected the task definition to be created")
+	}
+
+	if len(store.TaskHandlerList()) != 1 {
+		t.Fatalf("TaskHandlerList() should return 1 handler, got %d", len(store.TaskHandlerList()))
+	}
+}
+
+// echoHandler copies its "value" parameter to its output, once all the
+// handlers sharing its barrier started, so their executions overlap
+type echoHandler struct {
+	TaskDefinitionHandlerBase
+	barrier *sync.WaitGroup
+}
+
+func (h *echoHandler) Alias() string {
+	return "EchoHandler"
+}
+
+func (h *echoHandler) Title() string {
+	return "Echo Handler"
+}
+
+func (h *echoHandler) Description() string {
+	return "Copies its value parameter to its output"
+}
+
+func (h *echoHandler) Handle() bool {
+	h.barrier.Done()
+
+	overlapping := make(chan struct{})
+	go func() {
+		h.barrier.Wait()
+		close(overlapping)
+	}()
+	select {
+	case <-overlapping:
+	case <-time.After(5 * time.Second):
+	}
+
+	value := h.GetParam("value")
+	h.LogInfo("Echoing " + value)
+	h.SetOutput(value)
+	return true
+}
+
+func TestTaskQueueRunner_FactoryHandlersRunConcurrently(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("initStore: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+	const taskCount = 4
+
+	var barrier sync.WaitGroup
+	barrier.Add(taskCount)
+	factory := func() TaskDefinitionHandlerInterface {
+		return &echoHandler{barrier: &barrier}
+	}
+	if err := store.TaskHandlerAddFactory(ctx, "EchoHandler", factory, true); err != nil {
+		t.Fatalf("TaskHandlerAddFactory: Error[%v]", err)
+	}
+
+	values := map[string]string{}
+	for i := range taskCount {
+		value := fmt.Sprintf("value-%d", i)
+		queuedTask, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, "EchoHandler", map[string]any{
+			"value": value,
+		})
+		if err != nil {
+			t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+		}
+		values[queuedTask.GetID()] = value
+	}
+
+	runner := NewTaskQueueRunner(store, TaskQueueRunnerOptions{
+		QueueName:      DefaultQueueName,
+		MaxConcurrency: taskCount,
+	})
+	if err := runner.RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce: Error[%v]", err)
+	}
+
+	for id, value := range values {
+		dbTask, err := store.TaskQueueFindByID(ctx, id)
+		if err != nil {
+			t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+		}
+		if dbTask.GetStatus() != TaskQueueStatusSuccess {
+			t.Fatalf("Expected status %s, got %s", TaskQueueStatusSuccess, dbTask.GetStatus())
+		}
+		if dbTask.GetOutput() != value {
+			t.Fatalf("Expected output %s, got %s", value, dbTask.GetOutput())
+		}
+		if !strings.Contains(dbTask.GetDetails(), "Echoing "+value) {
+			t.Fatalf("Expected the details to contain %q, got %s", "Echoing "+value, dbTask.GetDetails())
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package taskstore
+
+import (
+	"context"
+
+	"github.com/dromara/carbon/v2"
+)
+
+// queueState is the persisted state of a queue. Queues without a row are
+// active, so a row is only created when a queue is first paused.
+type queueState struct {
+	QueueNameField string `db:"queue_name"`
+	StatusField    string `db:"status"`
+	PausedAtField  string `db:"paused_at"`
+	RateLimitField string `db:"rate_limit"`
+	CreatedAtField string `db:"created_at"`
+	UpdatedAtField string `db:"updated_at"`
+}
+
+// QueueGetRateLimit returns the rate limit of the queue, or nil if its
+// tasks are not rate limited
+func (store *Store) QueueGetRateLimit(ctx context.Context, queueName string) (*RateLimit, error) {
+	var states []queueState
+	err := store.db.Query().
+		Table(store.queueTableName).
+		Where(COLUMN_QUEUE_NAME+" = ?", normalizeQueueName(queueName)).
+		Get(&states)
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return nil, nil
+	}
+
+	return rateLimitFromJSON(states[0].RateLimitField), nil
+}
+
+// QueueIsPaused returns true if the queue is paused
+func (store *Store) QueueIsPaused(ctx context.Context, queueName string) (bool, error) {
+	var count int64
+	err := store.db.Query().
+		Table(store.queueTableName).
+		Where(COLUMN_QUEUE_NAME+" = ?", normalizeQueueName(queueName)).
+		Where(COLUMN_STATUS+" = ?", QueueStatusPaused).
+		Count(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// QueuePause pauses the queue. No tasks are claimed from a paused queue,
+// by any process, until it is resumed. Tasks already running are not
+// affected, and tasks can still be enqueued to it.
+func (store *Store) QueuePause(ctx context.Context, queueName string) error {
+	return store.queueSetStatus(ctx, normalizeQueueName(queueName), QueueStatusPaused)
+}
+
+// QueuePausedList returns the names of the paused queues
+func (store *Store) QueuePausedList(ctx context.Context) ([]string, error) {
+	var states []queueState
+	err := store.db.Query().
+		Table(store.queueTableName).
+		Where(COLUMN_STATUS+" = ?", QueueStatusPaused).
+		OrderBy(COLUMN_QUEUE_NAME, ASC).
+		Get(&states)
+	if err != nil {
+		return []string{}, err
+	}
+
+	names := make([]string, len(states))
+	for i, state := range states {
+		names[i] = state.QueueNameField
+	}
+	return names, nil
+}
+
+// QueueResume resumes a paused queue, so its tasks are claimed again
+func (store *Store) QueueResume(ctx context.Context, queueName string) error {
+	queueName = normalizeQueueName(queueName)
+
+	if err := store.queueSetStatus(ctx, queueName, QueueStatusActive); err != nil {
+		return err
+	}
+
+	store.notifier.Notify(queueName)
+
+	return nil
+}
+
+// QueueSetRateLimit caps how many tasks are claimed from the queue within
+// a sliding window, by any process. Tasks over the limit stay queued.
+// A nil rate limit removes the limit.
+func (store *Store) QueueSetRateLimit(ctx context.Context, queueName string, rateLimit *RateLimit) error {
+	queueName = normalizeQueueName(queueName)
+	now := carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)
+
+	var count int64
+	err := store.db.Query().
+		Table(store.queueTableName).
+		Where(COLUMN_QUEUE_NAME+" = ?", queueName).
+		Count(&count)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		_, err := store.db.Query().
+			Table(store.queueTableName).
+			Where(COLUMN_QUEUE_NAME+" = ?", queueName).
+			Update(map[string]any{
+				COLUMN_RATE_LIMIT: rateLimitToJSON(rateLimit),
+				COLUMN_UPDATED_AT: now,
+			})
+		return err
+	}
+
+	// Removing the limit of a queue which has none leaves nothing to persist
+	if rateLimit == nil || !rateLimit.IsEnabled() {
+		return nil
+	}
+
+	return store.db.Query().Table(store.queueTableName).Create(map[string]any{
+		COLUMN_QUEUE_NAME: queueName,
+		COLUMN_STATUS:     QueueStatusActive,
+		COLUMN_PAUSED_AT:  NULL_DATETIME,
+		COLUMN_RATE_LIMIT: rateLimitToJSON(rateLimit),
+		COLUMN_CREATED_AT: now,
+		COLUMN_UPDATED_AT: now,
+	})
+}
+
+// queueSetStatus updates the status of the queue, creating its row
+// if it does not exist yet
+func (store *Store) queueSetStatus(ctx context.Context, queueName string, status string) error {
+	now := carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)
+
+	pausedAt := NULL_DATETIME
+	if status == QueueStatusPaused {
+		pausedAt = now
+	}
+
+	// The existence of the row is counted, as the rows affected by an
+	// update which changes nothing are 0 on some databases (i.e. MySQL)
+	var count int64
+	err := store.db.Query().
+		Table(store.queueTableName).
+		Where(COLUMN_QUEUE_NAME+" = ?", queueName).
+		Count(&count)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		_, err := store.db.Query().
+			Table(store.queueTableName).
+			Where(COLUMN_QUEUE_NAME+" = ?", queueName).
+			Update(map[string]any{
+				COLUMN_STATUS:     status,
+				COLUMN_PAUSED_AT:  pausedAt,
+				COLUMN_UPDATED_AT: now,
+			})
+		return err
+	}
+
+	// Resuming a queue which was never paused leaves nothing to persist
+	if status == QueueStatusActive {
+		return nil
+	}
+
+	return store.db.Query().Table(store.queueTableName).Create(map[string]any{
+		COLUMN_QUEUE_NAME: queueName,
+		COLUMN_STATUS:     status,
+		COLUMN_PAUSED_AT:  pausedAt,
+		COLUMN_CREATED_AT: now,
+		COLUMN_UPDATED_AT: now,
+	})
+}
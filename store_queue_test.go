@@ -0,0 +1,132 @@
+package taskstore
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func Test_Store_QueuePauseResume(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("QueuePauseResume: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	paused, err := store.QueueIsPaused(ctx, "emails")
+	if err != nil {
+		t.Fatalf("QueueIsPaused: Error[%v]", err)
+	}
+	if paused {
+		t.Fatal("Expected a queue to be active by default")
+	}
+
+	// Resuming a queue which is not paused is a no-op
+	if err := store.QueueResume(ctx, "emails"); err != nil {
+		t.Fatalf("QueueResume: Error[%v]", err)
+	}
+
+	if err := store.QueuePause(ctx, "emails"); err != nil {
+		t.Fatalf("QueuePause: Error[%v]", err)
+	}
+
+	// Pausing twice is a no-op
+	if err := store.QueuePause(ctx, "emails"); err != nil {
+		t.Fatalf("QueuePause: Error[%v]", err)
+	}
+
+	paused, err = store.QueueIsPaused(ctx, "emails")
+	if err != nil {
+		t.Fatalf("QueueIsPaused: Error[%v]", err)
+	}
+	if !paused {
+		t.Fatal("Expected the queue to be paused")
+	}
+
+	// An empty queue name is the default queue
+	if err := store.QueuePause(ctx, ""); err != nil {
+		t.Fatalf("QueuePause: Error[%v]", err)
+	}
+
+	pausedList, err := store.QueuePausedList(ctx)
+	if err != nil {
+		t.Fatalf("QueuePausedList: Error[%v]", err)
+	}
+	if !slices.Equal(pausedList, []string{DefaultQueueName, "emails"}) {
+		t.Fatalf("Expected paused queues [%s emails], got %v", DefaultQueueName, pausedList)
+	}
+
+	if err := store.QueueResume(ctx, "emails"); err != nil {
+		t.Fatalf("QueueResume: Error[%v]", err)
+	}
+
+	paused, err = store.QueueIsPaused(ctx, "emails")
+	if err != nil {
+		t.Fatalf("QueueIsPaused: Error[%v]", err)
+	}
+	if paused {
+		t.Fatal("Expected the queue to be resumed")
+	}
+
+	pausedList, err = store.QueuePausedList(ctx)
+	if err != nil {
+		t.Fatalf("QueuePausedList: Error[%v]", err)
+	}
+	if !slices.Equal(pausedList, []string{DefaultQueueName}) {
+		t.Fatalf("Expected paused queues [%s], got %v", DefaultQueueName, pausedList)
+	}
+}
+
+func Test_Store_TaskQueueClaimNext_SkipsPausedQueues(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	for _, queueName := range []string{"paused-queue", "active-queue"} {
+		task := NewTaskQueue().
+			SetTaskID("TASK_" + queueName).
+			SetQueueName(queueName).
+			SetStatus(TaskQueueStatusQueued)
+		if err := store.TaskQueueCreate(ctx, task); err != nil {
+			t.Fatalf("TaskQueueCreate: Error[%v]", err)
+		}
+	}
+
+	if err := store.QueuePause(ctx, "paused-queue"); err != nil {
+		t.Fatalf("QueuePause: Error[%v]", err)
+	}
+
+	claimedTask, err := store.TaskQueueClaimNext(ctx, "paused-queue")
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimedTask != nil {
+		t.Fatal("Expected no task to be claimed from a paused queue")
+	}
+
+	claimedTask, err = store.TaskQueueClaimNext(ctx, "active-queue")
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimedTask == nil {
+		t.Fatal("Expected the task of the active queue to be claimed")
+	}
+
+	if err := store.QueueResume(ctx, "paused-queue"); err != nil {
+		t.Fatalf("QueueResume: Error[%v]", err)
+	}
+
+	claimedTask, err = store.TaskQueueClaimNext(ctx, "paused-queue")
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimedTask == nil {
+		t.Fatal("Expected the task to be claimed once the queue is resumed")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"slices"
 
 	contractsorm "github.com/dracory/neat/contracts/database/orm"
 	"github.com/dromara/carbon/v2"
@@ -39,17 +40,24 @@ func (store *Store) TaskDefinitionCreate(ctx context.Context, task TaskDefinitio
 	}
 
 	row := map[string]any{
-		COLUMN_ID:              task.GetID(),
-		COLUMN_STATUS:          task.GetStatus(),
-		COLUMN_ALIAS:           task.GetAlias(),
-		COLUMN_TITLE:           task.GetTitle(),
-		COLUMN_DESCRIPTION:     task.GetDescription(),
-		COLUMN_MEMO:            task.GetMemo(),
-		COLUMN_IS_RECURRING:    task.GetIsRecurring(),
-		COLUMN_RECURRENCE_RULE: task.GetRecurrenceRule(),
-		COLUMN_CREATED_AT:      task.GetCreatedAt().Format("2006-01-02 15:04:05"),
-		COLUMN_UPDATED_AT:      task.GetUpdatedAt().Format("2006-01-02 15:04:05"),
-		COLUMN_SOFT_DELETED_AT: task.GetSoftDeletedAt().Format("2006-01-02 15:04:05"),
+		COLUMN_ID:                     task.GetID(),
+		COLUMN_STATUS:                 task.GetStatus(),
+		COLUMN_ALIAS:                  task.GetAlias(),
+		COLUMN_TITLE:                  task.GetTitle(),
+		COLUMN_DESCRIPTION:            task.GetDescription(),
+		COLUMN_MEMO:                   task.GetMemo(),
+		COLUMN_PRIORITY:               task.GetPriority(),
+		COLUMN_IS_RECURRING:           task.GetIsRecurring(),
+		COLUMN_RECURRENCE_RULE:        task.GetRecurrenceRule(),
+		COLUMN_RETRY_POLICY:           retryPolicyToJSON(task.GetRetryPolicy()),
+		COLUMN_DEAD_LETTER_QUEUE_NAME: task.GetDeadLetterQueueName(),
+		COLUMN_TIMEOUT_SECONDS:        task.GetTimeoutSeconds(),
+		COLUMN_RATE_LIMIT:             rateLimitToJSON(task.GetRateLimit()),
+		COLUMN_MAX_CONCURRENCY:        task.GetMaxConcurrency(),
+		COLUMN_PARAMETER_SCHEMA:       parameterSchemaToJSON(task.GetParameterSchema()),
+		COLUMN_CREATED_AT:             task.GetCreatedAt().Format("2006-01-02 15:04:05"),
+		COLUMN_UPDATED_AT:             task.GetUpdatedAt().Format("2006-01-02 15:04:05"),
+		COLUMN_SOFT_DELETED_AT:        task.GetSoftDeletedAt().Format("2006-01-02 15:04:05"),
 	}
 
 	return store.db.Query().Table(store.taskDefinitionTableName).Create(row)
@@ -151,15 +159,22 @@ func (store *Store) TaskDefinitionUpdate(ctx context.Context, task TaskDefinitio
 	task.SetUpdatedAt(carbon.Now(carbon.UTC).StdTime())
 
 	row := map[string]any{
-		COLUMN_STATUS:          task.GetStatus(),
-		COLUMN_ALIAS:           task.GetAlias(),
-		COLUMN_TITLE:           task.GetTitle(),
-		COLUMN_DESCRIPTION:     task.GetDescription(),
-		COLUMN_MEMO:            task.GetMemo(),
-		COLUMN_IS_RECURRING:    task.GetIsRecurring(),
-		COLUMN_RECURRENCE_RULE: task.GetRecurrenceRule(),
-		COLUMN_UPDATED_AT:      task.GetUpdatedAt().Format("2006-01-02 15:04:05"),
-		COLUMN_SOFT_DELETED_AT: task.GetSoftDeletedAt().Format("2006-01-02 15:04:05"),
+		COLUMN_STATUS:                 task.GetStatus(),
+		COLUMN_ALIAS:                  task.GetAlias(),
+		COLUMN_TITLE:                  task.GetTitle(),
+		COLUMN_DESCRIPTION:            task.GetDescription(),
+		COLUMN_MEMO:                   task.GetMemo(),
+		COLUMN_PRIORITY:               task.GetPriority(),
+		COLUMN_IS_RECURRING:           task.GetIsRecurring(),
+		COLUMN_RECURRENCE_RULE:        task.GetRecurrenceRule(),
+		COLUMN_RETRY_POLICY:           retryPolicyToJSON(task.GetRetryPolicy()),
+		COLUMN_DEAD_LETTER_QUEUE_NAME: task.GetDeadLetterQueueName(),
+		COLUMN_TIMEOUT_SECONDS:        task.GetTimeoutSeconds(),
+		COLUMN_RATE_LIMIT:             rateLimitToJSON(task.GetRateLimit()),
+		COLUMN_MAX_CONCURRENCY:        task.GetMaxConcurrency(),
+		COLUMN_PARAMETER_SCHEMA:       parameterSchemaToJSON(task.GetParameterSchema()),
+		COLUMN_UPDATED_AT:             task.GetUpdatedAt().Format("2006-01-02 15:04:05"),
+		COLUMN_SOFT_DELETED_AT:        task.GetSoftDeletedAt().Format("2006-01-02 15:04:05"),
 	}
 
 	_, err := store.db.Query().
@@ -175,6 +190,18 @@ func (store *Store) TaskDefinitionEnqueueByAlias(
 	queueName string,
 	taskAlias string,
 	parameters map[string]any,
+) (TaskQueueInterface, error) {
+	return store.TaskDefinitionEnqueueByAliasWithOptions(ctx, queueName, taskAlias, parameters, EnqueueOptions{})
+}
+
+// TaskDefinitionEnqueueByAliasWithOptions finds a task by its alias and appends
+// it to the queue, applying the provided enqueue options
+func (store *Store) TaskDefinitionEnqueueByAliasWithOptions(
+	ctx context.Context,
+	queueName string,
+	taskAlias string,
+	parameters map[string]any,
+	options EnqueueOptions,
 ) (TaskQueueInterface, error) {
 	task, err := store.TaskDefinitionFindByAlias(ctx, taskAlias)
 	if err != nil {
@@ -184,6 +211,15 @@ func (store *Store) TaskDefinitionEnqueueByAlias(
 		return nil, errors.New("task with alias '" + taskAlias + "' not found")
 	}
 
+	parameters, err = validateParameters(task.GetAlias(), task.GetParameterSchema(), parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.enqueueOptionsValidateDependencies(ctx, options); err != nil {
+		return nil, err
+	}
+
 	parameters = queuePrependTaskAliasToParameters(taskAlias, parameters)
 
 	parametersBytes, jsonErr := json.Marshal(parameters)
@@ -198,14 +234,74 @@ func (store *Store) TaskDefinitionEnqueueByAlias(
 		SetTaskID(task.GetID()).
 		SetAttempts(0).
 		SetParameters(parametersStr).
-		SetStatus(TaskQueueStatusQueued)
+		SetStatus(TaskQueueStatusQueued).
+		SetPriority(task.GetPriority()).
+		SetAvailableAt(options.availableAt()).
+		SetBatchID(options.batchID)
 
-	err = store.TaskQueueCreate(ctx, queuedTask)
-	if err != nil {
+	if options.Priority != nil {
+		queuedTask.SetPriority(*options.Priority)
+	}
+
+	if options.RetryPolicy != nil {
+		queuedTask.SetRetryPolicy(options.RetryPolicy)
+	}
+
+	if options.Timeout > 0 {
+		queuedTask.SetTimeoutSeconds(options.timeoutSeconds())
+	}
+
+	if len(options.DependsOn) > 0 {
+		queuedTask.SetDependsOn(options.DependsOn)
+		queuedTask.SetDependencyFailurePolicy(options.DependencyFailurePolicy)
+		queuedTask.SetStatus(TaskQueueStatusPaused)
+	}
+
+	if options.UniqueKey != "" {
+		queuedTask.SetUniqueKey(options.UniqueKey)
+
+		if options.UniqueFor > 0 {
+			queuedTask.SetUniqueUntil(carbon.Now(carbon.UTC).StdTime().Add(options.UniqueFor))
+		}
+
+		createdTask, err := store.taskQueueCreateUnique(ctx, queuedTask)
+		if err != nil || createdTask != queuedTask {
+			return createdTask, err
+		}
+	} else if err := store.TaskQueueCreate(ctx, queuedTask); err != nil {
+		return queuedTask, err
+	}
+
+	// The dependencies may have completed before the task was created
+	if err := store.taskQueueResolveDependencies(ctx, queuedTask); err != nil {
 		return queuedTask, err
 	}
 
-	return queuedTask, err
+	return queuedTask, nil
+}
+
+// enqueueOptionsValidateDependencies checks the queued tasks to depend on exist,
+// and the dependency failure policy is known
+func (store *Store) enqueueOptionsValidateDependencies(ctx context.Context, options EnqueueOptions) error {
+	if len(options.DependsOn) == 0 {
+		return nil
+	}
+
+	if !slices.Contains([]string{"", DependencyFailureCancel, DependencyFailureBlock}, options.DependencyFailurePolicy) {
+		return errors.New("dependency failure policy '" + options.DependencyFailurePolicy + "' is not supported")
+	}
+
+	for _, taskQueueID := range options.DependsOn {
+		parent, err := store.TaskQueueFindByID(ctx, taskQueueID)
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			return errors.New("dependency queued task '" + taskQueueID + "' not found")
+		}
+	}
+
+	return nil
 }
 
 // queuePrependTaskAliasToParameters prepends a task alias to the queue parameters so that its easy to distinguish
@@ -2,7 +2,9 @@ package taskstore
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 )
 
 func Test_queuePrependTaskAliasToParameters(t *testing.T) {
@@ -175,3 +177,498 @@ func Test_Store_TaskDefinitionCreate(t *testing.T) {
 		t.Fatalf("TaskDefinitionCreate: Error in Creating TaskDefinition: received [%v]", err)
 	}
 }
+
+func Test_Store_TaskDefinitionEnqueueByAliasWithOptions_Delayed(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAliasWithOptions: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := newTestTaskHandler()
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	tests := []struct {
+		name      string
+		queueName string
+		options   EnqueueOptions
+		claimable bool
+	}{
+		{
+			name:      "no delay",
+			queueName: "queue_now",
+			options:   EnqueueOptions{},
+			claimable: true,
+		},
+		{
+			name:      "delay",
+			queueName: "queue_delay",
+			options:   EnqueueOptions{Delay: 24 * time.Hour},
+			claimable: false,
+		},
+		{
+			name:      "run at in the future",
+			queueName: "queue_run_at_future",
+			options:   EnqueueOptions{RunAt: time.Now().Add(time.Hour)},
+			claimable: false,
+		},
+		{
+			name:      "run at in the past",
+			queueName: "queue_run_at_past",
+			options:   EnqueueOptions{RunAt: time.Now().Add(-time.Hour)},
+			claimable: true,
+		},
+		{
+			name:      "run at takes precedence over delay",
+			queueName: "queue_run_at_precedence",
+			options:   EnqueueOptions{RunAt: time.Now().Add(-time.Hour), Delay: time.Hour},
+			claimable: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queuedTask, err := store.TaskDefinitionEnqueueByAliasWithOptions(ctx, tt.queueName, handler.Alias(), map[string]any{}, tt.options)
+			if err != nil {
+				t.Fatalf("TaskDefinitionEnqueueByAliasWithOptions: Error[%v]", err)
+			}
+
+			claimedTask, err := store.TaskQueueClaimNext(ctx, tt.queueName)
+			if err != nil {
+				t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+			}
+
+			if tt.claimable && (claimedTask == nil || claimedTask.GetID() != queuedTask.GetID()) {
+				t.Fatalf("TaskQueueClaimNext: Expected task %s to be claimed", queuedTask.GetID())
+			}
+
+			if !tt.claimable && claimedTask != nil {
+				t.Fatalf("TaskQueueClaimNext: Expected no task to be claimed, got %s", claimedTask.GetID())
+			}
+		})
+	}
+}
+
+func Test_Store_TaskDefinitionEnqueueByAliasWithOptions_Priority(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAliasWithOptions: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := newTestTaskHandler()
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	definition, err := store.TaskDefinitionFindByAlias(ctx, handler.Alias())
+	if err != nil {
+		t.Fatalf("TaskDefinitionFindByAlias: Error[%v]", err)
+	}
+	definition.SetPriority(5)
+	if err := store.TaskDefinitionUpdate(ctx, definition); err != nil {
+		t.Fatalf("TaskDefinitionUpdate: Error[%v]", err)
+	}
+
+	// Default priority comes from the task definition
+	queuedTask, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+	}
+	if queuedTask.GetPriority() != 5 {
+		t.Fatalf("Expected priority 5, got %d", queuedTask.GetPriority())
+	}
+
+	// Priority can be overridden per enqueue
+	priority := 20
+	queuedTask, err = store.TaskDefinitionEnqueueByAliasWithOptions(ctx, DefaultQueueName, handler.Alias(), map[string]any{}, EnqueueOptions{
+		Priority: &priority,
+	})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAliasWithOptions: Error[%v]", err)
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetPriority() != 20 {
+		t.Fatalf("Expected priority 20, got %d", dbTask.GetPriority())
+	}
+}
+
+func Test_Store_TaskDefinitionEnqueueByAliasWithOptions_UniqueKey(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAliasWithOptions: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := newTestTaskHandler()
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	enqueue := func(options EnqueueOptions) TaskQueueInterface {
+		t.Helper()
+		queuedTask, err := store.TaskDefinitionEnqueueByAliasWithOptions(ctx, DefaultQueueName, handler.Alias(), map[string]any{}, options)
+		if err != nil {
+			t.Fatalf("TaskDefinitionEnqueueByAliasWithOptions: Error[%v]", err)
+		}
+		return queuedTask
+	}
+
+	t.Run("held while queued or running", func(t *testing.T) {
+		options := EnqueueOptions{UniqueKey: "payment_1"}
+
+		first := enqueue(options)
+		second := enqueue(options)
+		if second.GetID() != first.GetID() {
+			t.Fatalf("Expected the existing task %s, got %s", first.GetID(), second.GetID())
+		}
+
+		if err := store.TaskQueueSuccess(ctx, first); err != nil {
+			t.Fatalf("TaskQueueSuccess: Error[%v]", err)
+		}
+
+		third := enqueue(options)
+		if third.GetID() == first.GetID() {
+			t.Fatal("Expected a new task once the existing task completed")
+		}
+
+		released, err := store.TaskQueueFindByID(ctx, first.GetID())
+		if err != nil {
+			t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+		}
+		if released.GetUniqueKey() != "" {
+			t.Fatalf("Expected the unique key to be released, got %s", released.GetUniqueKey())
+		}
+	})
+
+	t.Run("held for a time window", func(t *testing.T) {
+		options := EnqueueOptions{UniqueKey: "payment_2", UniqueFor: time.Hour}
+
+		first := enqueue(options)
+		if err := store.TaskQueueSuccess(ctx, first); err != nil {
+			t.Fatalf("TaskQueueSuccess: Error[%v]", err)
+		}
+
+		second := enqueue(options)
+		if second.GetID() != first.GetID() {
+			t.Fatalf("Expected the existing task %s within the window, got %s", first.GetID(), second.GetID())
+		}
+
+		first.SetUniqueUntil(time.Now().UTC().Add(-time.Minute))
+		if err := store.TaskQueueUpdate(ctx, first); err != nil {
+			t.Fatalf("TaskQueueUpdate: Error[%v]", err)
+		}
+
+		third := enqueue(options)
+		if third.GetID() == first.GetID() {
+			t.Fatal("Expected a new task once the window passed")
+		}
+	})
+
+	t.Run("held while queued past the time window", func(t *testing.T) {
+		options := EnqueueOptions{UniqueKey: "payment_4", UniqueFor: time.Hour}
+
+		first := enqueue(options)
+		first.SetUniqueUntil(time.Now().UTC().Add(-time.Minute))
+		if err := store.TaskQueueUpdate(ctx, first); err != nil {
+			t.Fatalf("TaskQueueUpdate: Error[%v]", err)
+		}
+
+		second := enqueue(options)
+		if second.GetID() != first.GetID() {
+			t.Fatalf("Expected the existing queued task %s past the window, got %s", first.GetID(), second.GetID())
+		}
+	})
+
+	t.Run("tasks without a key are not deduplicated", func(t *testing.T) {
+		first := enqueue(EnqueueOptions{})
+		second := enqueue(EnqueueOptions{})
+		if second.GetID() == first.GetID() {
+			t.Fatal("Expected separate tasks without a unique key")
+		}
+	})
+
+	t.Run("unique index rejects duplicate keys", func(t *testing.T) {
+		first := NewTaskQueue().SetTaskID("TASK_UNIQUE").SetUniqueKey("payment_3")
+		if err := store.TaskQueueCreate(ctx, first); err != nil {
+			t.Fatalf("TaskQueueCreate: Error[%v]", err)
+		}
+
+		second := NewTaskQueue().SetTaskID("TASK_UNIQUE").SetUniqueKey("payment_3")
+		if err := store.TaskQueueCreate(ctx, second); err == nil {
+			t.Fatal("Expected an error creating a task with a duplicate unique key")
+		}
+	})
+}
+
+func Test_Store_TaskDefinitionEnqueueByAliasWithOptions_DependsOn(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAliasWithOptions: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := newTestTaskHandler()
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	enqueue := func(options EnqueueOptions) TaskQueueInterface {
+		t.Helper()
+		queuedTask, err := store.TaskDefinitionEnqueueByAliasWithOptions(ctx, DefaultQueueName, handler.Alias(), map[string]any{}, options)
+		if err != nil {
+			t.Fatalf("TaskDefinitionEnqueueByAliasWithOptions: Error[%v]", err)
+		}
+		return queuedTask
+	}
+
+	statusOf := func(queuedTask TaskQueueInterface) string {
+		t.Helper()
+		dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+		if err != nil {
+			t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+		}
+		return dbTask.GetStatus()
+	}
+
+	t.Run("chain runs in order", func(t *testing.T) {
+		export := enqueue(EnqueueOptions{})
+		transform := enqueue(EnqueueOptions{DependsOn: []string{export.GetID()}})
+		upload := enqueue(EnqueueOptions{DependsOn: []string{transform.GetID()}})
+
+		if statusOf(transform) != TaskQueueStatusPaused || statusOf(upload) != TaskQueueStatusPaused {
+			t.Fatal("Expected the dependent tasks to be blocked")
+		}
+
+		if err := store.TaskQueueSuccess(ctx, export); err != nil {
+			t.Fatalf("TaskQueueSuccess: Error[%v]", err)
+		}
+		if statusOf(transform) != TaskQueueStatusQueued {
+			t.Fatalf("Expected transform to be queued, got %s", statusOf(transform))
+		}
+		if statusOf(upload) != TaskQueueStatusPaused {
+			t.Fatalf("Expected upload to stay blocked, got %s", statusOf(upload))
+		}
+
+		if err := store.TaskQueueSuccess(ctx, transform); err != nil {
+			t.Fatalf("TaskQueueSuccess: Error[%v]", err)
+		}
+		if statusOf(upload) != TaskQueueStatusQueued {
+			t.Fatalf("Expected upload to be queued, got %s", statusOf(upload))
+		}
+	})
+
+	t.Run("waits for all dependencies", func(t *testing.T) {
+		first := enqueue(EnqueueOptions{})
+		second := enqueue(EnqueueOptions{})
+		joined := enqueue(EnqueueOptions{DependsOn: []string{first.GetID(), second.GetID()}})
+
+		if err := store.TaskQueueSuccess(ctx, first); err != nil {
+			t.Fatalf("TaskQueueSuccess: Error[%v]", err)
+		}
+		if statusOf(joined) != TaskQueueStatusPaused {
+			t.Fatalf("Expected the task to stay blocked, got %s", statusOf(joined))
+		}
+
+		if err := store.TaskQueueSuccess(ctx, second); err != nil {
+			t.Fatalf("TaskQueueSuccess: Error[%v]", err)
+		}
+		if statusOf(joined) != TaskQueueStatusQueued {
+			t.Fatalf("Expected the task to be queued, got %s", statusOf(joined))
+		}
+	})
+
+	t.Run("failure cancels dependents", func(t *testing.T) {
+		export := enqueue(EnqueueOptions{})
+		transform := enqueue(EnqueueOptions{DependsOn: []string{export.GetID()}})
+		upload := enqueue(EnqueueOptions{DependsOn: []string{transform.GetID()}})
+
+		if err := store.TaskQueueFail(ctx, export); err != nil {
+			t.Fatalf("TaskQueueFail: Error[%v]", err)
+		}
+		if statusOf(transform) != TaskQueueStatusCanceled {
+			t.Fatalf("Expected transform to be canceled, got %s", statusOf(transform))
+		}
+		if statusOf(upload) != TaskQueueStatusCanceled {
+			t.Fatalf("Expected upload to be canceled, got %s", statusOf(upload))
+		}
+	})
+
+	t.Run("failure blocks dependents", func(t *testing.T) {
+		export := enqueue(EnqueueOptions{})
+		transform := enqueue(EnqueueOptions{
+			DependsOn:               []string{export.GetID()},
+			DependencyFailurePolicy: DependencyFailureBlock,
+		})
+
+		if err := store.TaskQueueFail(ctx, export); err != nil {
+			t.Fatalf("TaskQueueFail: Error[%v]", err)
+		}
+		if statusOf(transform) != TaskQueueStatusPaused {
+			t.Fatalf("Expected transform to stay blocked, got %s", statusOf(transform))
+		}
+
+		if err := store.TaskQueueRetry(ctx, export, 0); err != nil {
+			t.Fatalf("TaskQueueRetry: Error[%v]", err)
+		}
+		if err := store.TaskQueueSuccess(ctx, export); err != nil {
+			t.Fatalf("TaskQueueSuccess: Error[%v]", err)
+		}
+		if statusOf(transform) != TaskQueueStatusQueued {
+			t.Fatalf("Expected transform to be queued, got %s", statusOf(transform))
+		}
+	})
+
+	t.Run("completed dependencies are resolved on enqueue", func(t *testing.T) {
+		succeeded := enqueue(EnqueueOptions{})
+		if err := store.TaskQueueSuccess(ctx, succeeded); err != nil {
+			t.Fatalf("TaskQueueSuccess: Error[%v]", err)
+		}
+		failed := enqueue(EnqueueOptions{})
+		if err := store.TaskQueueFail(ctx, failed); err != nil {
+			t.Fatalf("TaskQueueFail: Error[%v]", err)
+		}
+
+		queued := enqueue(EnqueueOptions{DependsOn: []string{succeeded.GetID()}})
+		if statusOf(queued) != TaskQueueStatusQueued {
+			t.Fatalf("Expected the task to be queued, got %s", statusOf(queued))
+		}
+
+		canceled := enqueue(EnqueueOptions{DependsOn: []string{failed.GetID()}})
+		if statusOf(canceled) != TaskQueueStatusCanceled {
+			t.Fatalf("Expected the task to be canceled, got %s", statusOf(canceled))
+		}
+	})
+
+	t.Run("unknown dependencies are rejected", func(t *testing.T) {
+		_, err := store.TaskDefinitionEnqueueByAliasWithOptions(ctx, DefaultQueueName, handler.Alias(), map[string]any{}, EnqueueOptions{
+			DependsOn: []string{"UNKNOWN"},
+		})
+		if err == nil {
+			t.Fatal("Expected an error for an unknown dependency")
+		}
+	})
+}
+
+// parametersHandler declares the parameters it expects
+type parametersHandler struct {
+	TaskDefinitionHandlerBase
+	parameters []TaskParameter
+}
+
+func (h *parametersHandler) Alias() string {
+	return "ParametersHandler"
+}
+
+func (h *parametersHandler) Title() string {
+	return "Parameters Handler"
+}
+
+func (h *parametersHandler) Description() string {
+	return "Declares the parameters it expects"
+}
+
+func (h *parametersHandler) Handle() bool {
+	return true
+}
+
+func (h *parametersHandler) Parameters() []TaskParameter {
+	return h.parameters
+}
+
+var _ TaskHandlerWithParameters = (*parametersHandler)(nil)
+
+func Test_Store_TaskDefinitionEnqueueByAlias_ParameterSchema(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	invalid := &parametersHandler{parameters: []TaskParameter{{Name: "user_id", Type: "uuid"}}}
+	if err := store.TaskHandlerAdd(ctx, invalid, true); err == nil {
+		t.Fatal("TaskHandlerAdd: Expected an error for an invalid parameter schema")
+	}
+
+	handler := &parametersHandler{parameters: []TaskParameter{
+		{Name: "user_id", Type: ParameterTypeInteger, Required: true, Description: "User to welcome"},
+		{Name: "template", Type: ParameterTypeString, Default: "welcome"},
+	}}
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	// The schema is stored with the task definition
+	definition, err := store.TaskDefinitionFindByAlias(ctx, handler.Alias())
+	if err != nil {
+		t.Fatalf("TaskDefinitionFindByAlias: Error[%v]", err)
+	}
+	if len(definition.GetParameterSchema()) != 2 || definition.GetParameterSchema()[0].Description != "User to welcome" {
+		t.Fatalf("Expected the parameter schema to be stored, got %v", definition.GetParameterSchema())
+	}
+
+	_, err = store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{
+		"template": 5,
+	})
+	var validationErr *ParameterValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a *ParameterValidationError, got %v", err)
+	}
+	if len(validationErr.Errors) != 2 {
+		t.Fatalf("Expected 2 parameter errors, got %v", validationErr.Errors)
+	}
+
+	count, err := store.TaskQueueCount(ctx, TaskQueueQuery())
+	if err != nil {
+		t.Fatalf("TaskQueueCount: Error[%v]", err)
+	}
+	if count != 0 {
+		t.Fatalf("Expected no task to be enqueued, got %d", count)
+	}
+
+	// The defaults are filled, also when enqueuing typed parameters
+	queuedTask, err := Enqueue(ctx, store, DefaultQueueName, handler.Alias(), struct {
+		UserID int `json:"user_id"`
+	}{UserID: 7})
+	if err != nil {
+		t.Fatalf("Enqueue: Error[%v]", err)
+	}
+
+	parameters, err := queuedTask.ParametersMap()
+	if err != nil {
+		t.Fatalf("ParametersMap: Error[%v]", err)
+	}
+	if parameters["user_id"] != "7" || parameters["template"] != "welcome" {
+		t.Fatalf("Expected the default to be filled, got %v", parameters)
+	}
+
+	// The schema is updated when the declared parameters change
+	handler.parameters = handler.parameters[:1]
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	definition, err = store.TaskDefinitionFindByAlias(ctx, handler.Alias())
+	if err != nil {
+		t.Fatalf("TaskDefinitionFindByAlias: Error[%v]", err)
+	}
+	if len(definition.GetParameterSchema()) != 1 {
+		t.Fatalf("Expected the parameter schema to be updated, got %v", definition.GetParameterSchema())
+	}
+}
@@ -2,7 +2,9 @@ package taskstore
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"slices"
 	"time"
 
 	contractsorm "github.com/dracory/neat/contracts/database/orm"
@@ -40,23 +42,19 @@ func (store *Store) TaskQueueCreate(ctx context.Context, queue TaskQueueInterfac
 		queue.SetUpdatedAt(carbon.Now(carbon.UTC).StdTime())
 	}
 
-	row := map[string]any{
-		COLUMN_ID:              queue.GetID(),
-		COLUMN_QUEUE_NAME:      queue.GetQueueName(),
-		COLUMN_TASK_ID:         queue.GetTaskID(),
-		COLUMN_PARAMETERS:      queue.GetParameters(),
-		COLUMN_STATUS:          queue.GetStatus(),
-		COLUMN_OUTPUT:          queue.GetOutput(),
-		COLUMN_DETAILS:         queue.GetDetails(),
-		COLUMN_ATTEMPTS:        queue.GetAttempts(),
-		COLUMN_STARTED_AT:      queue.GetStartedAt().Format("2006-01-02 15:04:05"),
-		COLUMN_COMPLETED_AT:    queue.GetCompletedAt().Format("2006-01-02 15:04:05"),
-		COLUMN_CREATED_AT:      queue.GetCreatedAt().Format("2006-01-02 15:04:05"),
-		COLUMN_UPDATED_AT:      queue.GetUpdatedAt().Format("2006-01-02 15:04:05"),
-		COLUMN_SOFT_DELETED_AT: queue.GetSoftDeletedAt().Format("2006-01-02 15:04:05"),
+	row := taskQueueToRow(queue)
+	row[COLUMN_ID] = queue.GetID()
+	row[COLUMN_CREATED_AT] = queue.GetCreatedAt().Format("2006-01-02 15:04:05")
+
+	if err := store.db.Query().Table(store.taskQueueTableName).Create(row); err != nil {
+		return err
 	}
 
-	return store.db.Query().Table(store.taskQueueTableName).Create(row)
+	// Wake up the runners of the queue, rather than leaving the task
+	// until their next poll
+	store.notifier.Notify(queue.GetQueueName())
+
+	return nil
 }
 
 func (store *Store) TaskQueueDelete(ctx context.Context, queue TaskQueueInterface) error {
@@ -77,11 +75,99 @@ func (store *Store) TaskQueueDeleteByID(ctx context.Context, id string) error {
 	return err
 }
 
-// TaskQueueFail fails a queued task
+// TaskQueueCancel cancels a queued task.
+//
+// Business logic:
+//   - a queued or blocked (paused) task is canceled right away, so it is
+//     never claimed
+//   - a running task is asked to stop (see IsCancelRequested). The worker
+//     processing it notices within a second, cancels the context passed to
+//     TaskHandlerWithContext.HandleWithContext, and records the
+//     cancellation once the handler returned. Handlers which do not watch
+//     the context run to the end, but their outcome is not saved. If the
+//     worker is gone, the cancellation is recorded once its lease expired
+//     (see TaskQueueReapExpiredLeases).
+//   - a running task without a live lease is canceled right away
+//   - a task which already finished cannot be canceled
+func (store *Store) TaskQueueCancel(ctx context.Context, id string) error {
+	queue, err := store.TaskQueueFindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if queue == nil {
+		return errors.New("queued task not found")
+	}
+
+	if queue.IsCanceled() || queue.IsCancelRequested() {
+		return nil
+	}
+
+	if !queue.IsQueued() && !queue.IsPaused() && !queue.IsRunning() {
+		return errors.New("queued task with status " + queue.GetStatus() + " cannot be canceled")
+	}
+
+	now := carbon.Now(carbon.UTC).StdTime()
+	leaseOwner := queue.GetLeaseOwner()
+	leaseExpiresAt := queue.GetLeaseExpiresAt()
+
+	if queue.IsRunning() && leaseOwner != "" && leaseExpiresAt.After(now) {
+		result, err := store.db.Query().
+			Table(store.taskQueueTableName).
+			Where(COLUMN_ID+" = ?", id).
+			Where(COLUMN_STATUS+" = ?", TaskQueueStatusRunning).
+			Where(COLUMN_LEASE_OWNER+" = ?", leaseOwner).
+			Update(map[string]any{
+				COLUMN_CANCEL_REQUESTED_AT: now.Format("2006-01-02 15:04:05"),
+				COLUMN_UPDATED_AT:          now.Format("2006-01-02 15:04:05"),
+			})
+		if err != nil {
+			return err
+		}
+
+		// The task finished, or lost its lease, meanwhile
+		if result.RowsAffected == 0 {
+			return store.TaskQueueCancel(ctx, id)
+		}
+
+		return nil
+	}
+
+	status := queue.GetStatus()
+
+	queue.AppendDetails("Task canceled")
+	queue.SetStatus(TaskQueueStatusCanceled)
+	queue.SetCompletedAt(now)
+	queue.SetLeaseOwner("")
+	queue.SetLeaseExpiresAt(time.Time{})
+
+	// The task may have been claimed, or finished, meanwhile
+	var saved bool
+	if status == TaskQueueStatusRunning {
+		saved, err = store.taskQueueUpdateIfLeaseHeld(ctx, queue, leaseOwner, leaseExpiresAt)
+	} else {
+		saved, err = store.taskQueueUpdateIfStatus(ctx, queue, status)
+	}
+	if err != nil {
+		return err
+	}
+	if !saved {
+		return store.TaskQueueCancel(ctx, id)
+	}
+
+	return store.taskQueueCompleted(ctx, queue)
+}
+
+// TaskQueueFail fails a queued task. The queued tasks depending on it
+// are canceled or left blocked, according to their dependency failure policy,
+// and its batch is completed once all the batch tasks finished.
 func (store *Store) TaskQueueFail(ctx context.Context, queue TaskQueueInterface) error {
 	queue.SetCompletedAt(carbon.Now(carbon.UTC).StdTime())
 	queue.SetStatus(TaskQueueStatusFailed)
-	return store.TaskQueueUpdate(ctx, queue)
+	if err := store.TaskQueueUpdate(ctx, queue); err != nil {
+		return err
+	}
+	return store.taskQueueCompleted(ctx, queue)
 }
 
 // TaskQueueFindByID finds a Queue by ID
@@ -100,6 +186,83 @@ func (store *Store) TaskQueueFindByID(ctx context.Context, id string) (TaskQueue
 	return nil, nil
 }
 
+// TaskQueueFindByUniqueKey finds the queued task with the given unique key.
+// Returns (nil, nil) if no queued task has the key.
+func (store *Store) TaskQueueFindByUniqueKey(ctx context.Context, uniqueKey string) (TaskQueueInterface, error) {
+	if uniqueKey == "" {
+		return nil, errors.New("queue unique key is empty")
+	}
+
+	var queues []taskQueue
+	err := store.db.Query().
+		Table(store.taskQueueTableName).
+		Where(COLUMN_UNIQUE_KEY+" = ?", uniqueKey).
+		Limit(1).
+		Get(&queues)
+	if err != nil {
+		return nil, err
+	}
+	if len(queues) < 1 {
+		return nil, nil
+	}
+	return &queues[0], nil
+}
+
+// taskQueueCreateUnique creates a queued task which has a unique key.
+// If another queued task still holds the key, that task is returned
+// instead, and no new task is created.
+//
+// Business logic:
+//   - the unique index guarantees only one queued task has the key
+//   - a task whose key is no longer held releases it to the new task
+//   - if a concurrent enqueue takes the key first, its task is returned
+func (store *Store) taskQueueCreateUnique(ctx context.Context, queuedTask TaskQueueInterface) (TaskQueueInterface, error) {
+	existing, err := store.TaskQueueFindByUniqueKey(ctx, queuedTask.GetUniqueKey())
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		if isUniqueKeyHeld(existing, carbon.Now(carbon.UTC).StdTime()) {
+			return existing, nil
+		}
+
+		_, err := store.db.Query().
+			Table(store.taskQueueTableName).
+			Where(COLUMN_ID+" = ?", existing.GetID()).
+			Where(COLUMN_UNIQUE_KEY+" = ?", existing.GetUniqueKey()).
+			Update(map[string]any{COLUMN_UNIQUE_KEY: nil})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := store.TaskQueueCreate(ctx, queuedTask); err != nil {
+		existing, findErr := store.TaskQueueFindByUniqueKey(ctx, queuedTask.GetUniqueKey())
+		if findErr == nil && existing != nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+
+	return queuedTask, nil
+}
+
+// isUniqueKeyHeld reports whether the queued task still holds its unique
+// key: while it has not finished (queued, running or paused), and after that
+// until its unique until time, if any
+func isUniqueKeyHeld(queuedTask TaskQueueInterface, now time.Time) bool {
+	if queuedTask.GetUniqueKey() == "" {
+		return false
+	}
+
+	if queuedTask.IsQueued() || queuedTask.IsRunning() || queuedTask.IsPaused() {
+		return true
+	}
+
+	return !isNullTime(queuedTask.GetUniqueUntil()) && now.Before(queuedTask.GetUniqueUntil())
+}
+
 func (store *Store) TaskQueueFindRunning(ctx context.Context, limit int) []TaskQueueInterface {
 	return store.TaskQueueFindRunningByQueue(ctx, DefaultQueueName, limit)
 }
@@ -155,23 +318,46 @@ func (store *Store) TaskQueueFindRunningByQueue(ctx context.Context, queueName s
 
 func (store *Store) TaskQueueFindNextQueuedTaskByQueue(ctx context.Context, queueName string) (TaskQueueInterface, error) {
 	queueName = normalizeQueueName(queueName)
-	queuedTasks, errList := store.TaskQueueList(ctx, TaskQueueQuery().SetStatus(TaskQueueStatusQueued).
+	query := TaskQueueQuery().
+		SetStatus(TaskQueueStatusQueued).
 		SetQueueName(queueName).
+		SetAvailableAtLte(carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)).
 		SetLimit(1).
-		SetOrderBy(COLUMN_CREATED_AT).
-		SetSortOrder(ASC))
-	if errList != nil {
-		return nil, errList
+		SetOrderBy(COLUMN_PRIORITY).
+		SetSortOrder(DESC)
+
+	// Tasks with the same priority are taken oldest first
+	var queuedTasks []taskQueue
+	err := store.buildTaskQueueQuery(query).
+		Table(store.taskQueueTableName).
+		OrderBy(COLUMN_CREATED_AT, ASC).
+		Get(&queuedTasks)
+	if err != nil {
+		return nil, err
 	}
 	if len(queuedTasks) < 1 {
 		return nil, nil
 	}
-	return queuedTasks[0], nil
+	return &queuedTasks[0], nil
 }
 
 // TaskQueueClaimNext atomically claims the next queued task for processing.
-// It uses SELECT FOR UPDATE within a transaction to prevent race conditions
-// where multiple workers might try to process the same task.
+// It uses SELECT FOR UPDATE and a conditional update within a transaction
+// to prevent race conditions where multiple workers might try to process
+// the same task (see TaskQueueClaimBatch). Tasks which
+// are not yet available (i.e. delayed or waiting for a retry) are skipped.
+// Tasks are claimed by priority (highest first), then by age (oldest first).
+// Nothing is claimed from a paused queue (see QueuePause). Tasks over a
+// rate limit (see RateLimit) or over the concurrency limit of their task
+// definition (see TaskDefinitionInterface.GetMaxConcurrency) stay queued.
+//
+// The worker ID carried by ctx (see ContextWithWorkerID) and the time of
+// the claim are recorded on the task, for debugging.
+//
+// The claimed task is leased for the lease duration of the store. While it
+// is processed the lease is extended with heartbeats (see
+// TaskQueueHeartbeat). A task whose lease expired, because its worker
+// stopped abruptly, is reaped (see TaskQueueReapExpiredLeases).
 //
 // Returns:
 //   - TaskQueueInterface: The claimed task (status updated to "running")
@@ -179,56 +365,240 @@ func (store *Store) TaskQueueFindNextQueuedTaskByQueue(ctx context.Context, queu
 //
 // Returns (nil, nil) if no tasks are available to claim.
 func (store *Store) TaskQueueClaimNext(ctx context.Context, queueName string) (TaskQueueInterface, error) {
+	tasks, err := store.TaskQueueClaimBatch(ctx, queueName, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+	return tasks[0], nil
+}
+
+// TaskQueueClaimBatch atomically claims up to n queued tasks for
+// processing, in the order and under the rules of TaskQueueClaimNext, but
+// within a single transaction with one select and one update. Use it
+// instead of repeated calls to TaskQueueClaimNext when processing many
+// small tasks, so the database does not become the bottleneck.
+//
+// Fewer than n tasks are claimed when fewer are available, or when the
+// rate and concurrency limits allow fewer. Returns an empty list if no
+// tasks are available to claim.
+//
+// The tasks are claimed with a conditional update, which only succeeds
+// while they are still queued. If another claimer took any of them first,
+// the claim is rolled back and retried, so a task is never claimed twice,
+// on any database. On SQLite, which has no row locks, the claim
+// transaction takes the write lock up front, so concurrent claimers (i.e.
+// processes sharing one database file) wait for each other instead of
+// failing.
+func (store *Store) TaskQueueClaimBatch(ctx context.Context, queueName string, n int) ([]TaskQueueInterface, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 	queueName = normalizeQueueName(queueName)
 
+	if n <= 0 {
+		return []TaskQueueInterface{}, nil
+	}
+
+	for attempt := 1; ; attempt++ {
+		tasks, err := store.taskQueueClaimBatchOnce(ctx, queueName, n)
+		if !errors.Is(err, errTaskQueueClaimConflict) {
+			return tasks, err
+		}
+		if attempt == taskQueueClaimAttempts {
+			// Others keep claiming the tasks first, leave them to those
+			return []TaskQueueInterface{}, nil
+		}
+	}
+}
+
+// taskQueueClaimAttempts is how many times a claim is attempted when other
+// claimers take the selected tasks first
+const taskQueueClaimAttempts = 3
+
+// errTaskQueueClaimConflict reports that another claimer took a selected
+// task first
+var errTaskQueueClaimConflict = errors.New("queued task claimed by another worker")
+
+// taskQueueClaimBatchOnce makes a single attempt of TaskQueueClaimBatch.
+// Returns errTaskQueueClaimConflict if another claimer took any of the
+// selected tasks first; nothing is claimed then.
+func (store *Store) taskQueueClaimBatchOnce(ctx context.Context, queueName string, n int) ([]TaskQueueInterface, error) {
 	tx, err := store.db.Query().Begin()
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	q := tx.Table(store.taskQueueTableName).
+	if store.isSQLite {
+		// A write (matching no rows) takes the write lock of the database
+		// before anything is read, as in an immediate transaction. Reading
+		// first would fail the claim once another claimer commits.
+		_, err := txTable(tx, store.taskQueueTableName).
+			Where(COLUMN_ID+" = ?", "").
+			Update(map[string]any{COLUMN_UPDATED_AT: carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC)})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The queue is read within the transaction (and locked on databases
+	// other than SQLite), so no tasks are claimed once it is paused
+	q := txTable(tx, store.queueTableName).
+		Where(COLUMN_QUEUE_NAME+" = ?", queueName)
+	if !store.isSQLite {
+		q = q.LockForUpdate()
+	}
+
+	var states []queueState
+	if err := q.Find(&states); err != nil {
+		return nil, err
+	}
+
+	var queueRateLimit *RateLimit
+	for _, state := range states {
+		if state.StatusField == QueueStatusPaused {
+			return []TaskQueueInterface{}, nil
+		}
+		queueRateLimit = rateLimitFromJSON(state.RateLimitField)
+	}
+
+	now := carbon.Now(carbon.UTC)
+
+	// Only the limits of the task definitions with tasks to claim are
+	// checked (and locked)
+	var candidateTaskIDs []any
+	err = txTable(tx, store.taskQueueTableName).
 		Where(COLUMN_STATUS+" = ?", TaskQueueStatusQueued).
 		Where(COLUMN_QUEUE_NAME+" = ?", queueName).
+		Where(COLUMN_AVAILABLE_AT+" <= ?", now.ToDateTimeString(carbon.UTC)).
+		Distinct(COLUMN_TASK_ID).
+		Pluck(COLUMN_TASK_ID, &candidateTaskIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidateTaskIDs) == 0 {
+		return []TaskQueueInterface{}, nil
+	}
+
+	queueRemaining, taskRemaining, err := store.taskQueueRateLimitRemaining(tx, queueName, queueRateLimit, candidateTaskIDs, now.StdTime())
+	if err != nil {
+		return nil, err
+	}
+	if queueRemaining == 0 {
+		return []TaskQueueInterface{}, nil
+	}
+	if queueRemaining > 0 {
+		n = min(n, queueRemaining)
+	}
+
+	concurrencyRemaining, err := store.taskQueueConcurrencyLimitRemaining(tx, candidateTaskIDs)
+	if err != nil {
+		return nil, err
+	}
+	for taskID, remaining := range concurrencyRemaining {
+		if rateRemaining, ok := taskRemaining[taskID]; ok {
+			remaining = min(remaining, rateRemaining)
+		}
+		taskRemaining[taskID] = remaining
+	}
+
+	limitedTaskIDs := []any{}
+	for taskID, remaining := range taskRemaining {
+		if remaining == 0 {
+			limitedTaskIDs = append(limitedTaskIDs, taskID)
+		}
+	}
+
+	q = txTable(tx, store.taskQueueTableName).
+		Where(COLUMN_STATUS+" = ?", TaskQueueStatusQueued).
+		Where(COLUMN_QUEUE_NAME+" = ?", queueName).
+		Where(COLUMN_AVAILABLE_AT+" <= ?", now.ToDateTimeString(carbon.UTC))
+	if len(limitedTaskIDs) > 0 {
+		q = q.WhereNotIn(COLUMN_TASK_ID, limitedTaskIDs)
+	}
+	q = q.OrderBy(COLUMN_PRIORITY, DESC).
 		OrderBy(COLUMN_CREATED_AT, ASC).
-		Limit(1)
+		Limit(n)
 	if !store.isSQLite {
 		q = q.LockForUpdate()
 	}
 
-	var tasks []taskQueue
-	if err := q.Find(&tasks); err != nil {
+	var candidates []taskQueue
+	if err := q.Find(&candidates); err != nil {
 		return nil, err
 	}
+
+	// Limited task definitions may allow fewer of their tasks than selected
+	tasks := []taskQueue{}
+	for _, candidate := range candidates {
+		if remaining, ok := taskRemaining[candidate.GetTaskID()]; ok {
+			if remaining == 0 {
+				continue
+			}
+			taskRemaining[candidate.GetTaskID()] = remaining - 1
+		}
+		tasks = append(tasks, candidate)
+	}
 	if len(tasks) == 0 {
-		return nil, nil
+		return []TaskQueueInterface{}, nil
 	}
-	task := tasks[0]
 
-	now := carbon.Now(carbon.UTC)
-	_, err = tx.Table(store.taskQueueTableName).
-		Where(COLUMN_ID+" = ?", task.ShortID.ID).
+	workerID := store.workerIDFromContext(ctx)
+	leaseExpiresAt := now.StdTime().Add(store.leaseDuration)
+
+	ids := make([]any, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ShortID.ID
+	}
+
+	result, err := txTable(tx, store.taskQueueTableName).
+		WhereIn(COLUMN_ID, ids).
+		Where(COLUMN_STATUS+" = ?", TaskQueueStatusQueued).
 		Update(map[string]any{
-			COLUMN_STATUS:     TaskQueueStatusRunning,
-			COLUMN_STARTED_AT: now.ToDateTimeString(carbon.UTC),
-			COLUMN_UPDATED_AT: now.ToDateTimeString(carbon.UTC),
+			COLUMN_STATUS:           TaskQueueStatusRunning,
+			COLUMN_STARTED_AT:       now.ToDateTimeString(carbon.UTC),
+			COLUMN_LEASE_OWNER:      workerID,
+			COLUMN_LEASE_EXPIRES_AT: leaseExpiresAt.Format("2006-01-02 15:04:05"),
+			COLUMN_WORKER_ID:        workerID,
+			COLUMN_CLAIMED_AT:       now.ToDateTimeString(carbon.UTC),
+			COLUMN_UPDATED_AT:       now.ToDateTimeString(carbon.UTC),
+
+			// A new run starts without a cancel request
+			COLUMN_CANCEL_REQUESTED_AT: NULL_DATETIME,
 		})
 	if err != nil {
 		return nil, err
 	}
+	if result.RowsAffected != int64(len(ids)) {
+		return nil, errTaskQueueClaimConflict
+	}
+
+	if err := store.taskQueueClaimsRecord(tx, tasks, now.StdTime()); err != nil {
+		return nil, err
+	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
-	task.StatusField = TaskQueueStatusRunning
-	task.SetStartedAt(now.StdTime())
-	task.SetUpdatedAt(now.StdTime())
+	claimed := make([]TaskQueueInterface, len(tasks))
+	for i := range tasks {
+		task := &tasks[i]
+		task.StatusField = TaskQueueStatusRunning
+		task.SetStartedAt(now.StdTime())
+		task.SetLeaseOwner(workerID)
+		task.SetWorkerID(workerID)
+		task.SetClaimedAt(now.StdTime())
+		task.SetLeaseExpiresAt(leaseExpiresAt)
+		task.CancelRequestedAtField = time.Time{}
+		task.SetUpdatedAt(now.StdTime())
+		claimed[i] = task
+	}
 
-	return &task, nil
+	return claimed, nil
 }
 
 func (store *Store) TaskQueueProcessNextByQueue(ctx context.Context, queueName string) error {
@@ -281,11 +651,148 @@ func (store *Store) TaskQueueSoftDeleteByID(ctx context.Context, id string) erro
 	return store.TaskQueueSoftDelete(ctx, queue)
 }
 
-// TaskQueueSuccess completes a queued task successfully
+// TaskQueueRetry puts a queued task back in the queue, to be claimed
+// again once the delay has passed
+func (store *Store) TaskQueueRetry(ctx context.Context, queue TaskQueueInterface, delay time.Duration) error {
+	queue.SetStatus(TaskQueueStatusQueued)
+	queue.SetAvailableAt(carbon.Now(carbon.UTC).StdTime().Add(delay))
+	queue.SetCompletedAt(time.Time{})
+	return store.TaskQueueUpdate(ctx, queue)
+}
+
+// TaskQueueSuccess completes a queued task successfully. The queued tasks
+// depending on it are queued, once all their dependencies succeeded, and its
+// batch is completed once all the batch tasks finished.
 func (store *Store) TaskQueueSuccess(ctx context.Context, queue TaskQueueInterface) error {
 	queue.SetCompletedAt(carbon.Now(carbon.UTC).StdTime())
 	queue.SetStatus(TaskQueueStatusSuccess)
-	return store.TaskQueueUpdate(ctx, queue)
+	if err := store.TaskQueueUpdate(ctx, queue); err != nil {
+		return err
+	}
+	return store.taskQueueCompleted(ctx, queue)
+}
+
+// taskQueueResolveDependencies updates the status of a queued task which
+// depends on other queued tasks. The task is blocked (paused) until all its
+// dependencies succeeded, and then queued. If a dependency failed or was
+// canceled, the task is canceled or stays blocked, according to its
+// dependency failure policy.
+func (store *Store) taskQueueResolveDependencies(ctx context.Context, queue TaskQueueInterface) error {
+	dependsOn := slices.Compact(slices.Sorted(slices.Values(queue.GetDependsOn())))
+	if len(dependsOn) == 0 {
+		return nil
+	}
+
+	parents, err := store.TaskQueueList(ctx, TaskQueueQuery().SetIDIn(dependsOn))
+	if err != nil {
+		return err
+	}
+
+	succeeded := 0
+	failedParentID := ""
+	for _, parent := range parents {
+		if parent.IsSuccess() {
+			succeeded++
+		}
+		if parent.IsFailed() || parent.IsCanceled() {
+			failedParentID = parent.GetID()
+		}
+	}
+
+	status := TaskQueueStatusPaused
+	if succeeded == len(dependsOn) {
+		status = TaskQueueStatusQueued
+	} else if failedParentID != "" && queue.GetDependencyFailurePolicy() == DependencyFailureCancel {
+		status = TaskQueueStatusCanceled
+	}
+
+	isBlocked := status == TaskQueueStatusPaused && failedParentID != ""
+	if status == queue.GetStatus() && !isBlocked {
+		return nil
+	}
+
+	switch status {
+	case TaskQueueStatusQueued:
+		queue.AppendDetails("Dependencies succeeded. Task queued")
+	case TaskQueueStatusCanceled:
+		queue.AppendDetails("Dependency " + failedParentID + " failed. Task canceled")
+		queue.SetCompletedAt(carbon.Now(carbon.UTC).StdTime())
+	default:
+		if isBlocked {
+			queue.AppendDetails("Dependency " + failedParentID + " failed. Task blocked")
+		}
+	}
+
+	queue.SetStatus(status)
+	if err := store.TaskQueueUpdate(ctx, queue); err != nil {
+		return err
+	}
+
+	if status == TaskQueueStatusQueued {
+		store.notifier.Notify(queue.GetQueueName())
+	}
+
+	if status == TaskQueueStatusCanceled {
+		return store.taskQueueCompleted(ctx, queue)
+	}
+
+	return nil
+}
+
+// taskQueueCompleted updates what depends on a queued task which finished:
+// the queued tasks depending on it, and the batch it belongs to
+func (store *Store) taskQueueCompleted(ctx context.Context, queue TaskQueueInterface) error {
+	if err := store.taskQueueResolveDependents(ctx, queue.GetID()); err != nil {
+		return err
+	}
+
+	if queue.GetBatchID() == "" {
+		return nil
+	}
+
+	return store.batchResolve(ctx, queue.GetBatchID())
+}
+
+// taskQueueResolveDependents resolves the dependencies of the blocked
+// queued tasks which depend on the given queued task
+func (store *Store) taskQueueResolveDependents(ctx context.Context, taskQueueID string) error {
+	var dependents []taskQueue
+	err := store.db.Query().
+		Model(&taskQueue{}).
+		Table(store.taskQueueTableName).
+		Where(COLUMN_STATUS+" = ?", TaskQueueStatusPaused).
+		Where(COLUMN_DEPENDS_ON+" LIKE ?", "%\""+taskQueueID+"\"%").
+		Get(&dependents)
+	if err != nil {
+		return err
+	}
+
+	for i := range dependents {
+		dependent := &dependents[i]
+		if !slices.Contains(dependent.GetDependsOn(), taskQueueID) {
+			continue
+		}
+
+		if err := store.taskQueueResolveDependencies(ctx, dependent); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dependsOnToJSON serializes the IDs of the queued tasks a queued task
+// depends on. No dependencies are stored as NULL.
+func dependsOnToJSON(taskQueueIDs []string) any {
+	if len(taskQueueIDs) == 0 {
+		return nil
+	}
+
+	taskQueueIDsBytes, err := json.Marshal(taskQueueIDs)
+	if err != nil {
+		return nil
+	}
+	return string(taskQueueIDsBytes)
 }
 
 func (store *Store) QueuedTaskForceFail(ctx context.Context, queuedTask TaskQueueInterface, waitMinutes int) error {
@@ -310,19 +817,7 @@ func (store *Store) TaskQueueUpdate(ctx context.Context, queue TaskQueueInterfac
 	}
 	queue.SetUpdatedAt(carbon.Now(carbon.UTC).StdTime())
 
-	row := map[string]any{
-		COLUMN_QUEUE_NAME:      queue.GetQueueName(),
-		COLUMN_TASK_ID:         queue.GetTaskID(),
-		COLUMN_PARAMETERS:      queue.GetParameters(),
-		COLUMN_STATUS:          queue.GetStatus(),
-		COLUMN_OUTPUT:          queue.GetOutput(),
-		COLUMN_DETAILS:         queue.GetDetails(),
-		COLUMN_ATTEMPTS:        queue.GetAttempts(),
-		COLUMN_STARTED_AT:      queue.GetStartedAt().Format("2006-01-02 15:04:05"),
-		COLUMN_COMPLETED_AT:    queue.GetCompletedAt().Format("2006-01-02 15:04:05"),
-		COLUMN_UPDATED_AT:      queue.GetUpdatedAt().Format("2006-01-02 15:04:05"),
-		COLUMN_SOFT_DELETED_AT: queue.GetSoftDeletedAt().Format("2006-01-02 15:04:05"),
-	}
+	row := taskQueueToRow(queue)
 
 	_, err := store.db.Query().
 		Table(store.taskQueueTableName).
@@ -331,6 +826,106 @@ func (store *Store) TaskQueueUpdate(ctx context.Context, queue TaskQueueInterfac
 	return err
 }
 
+// taskQueueUpdateUnlessCanceled updates a queued task, unless it was
+// canceled (or asked to be, see TaskQueueCancel) meanwhile. Returns false
+// if the queued task was canceled.
+func (store *Store) taskQueueUpdateUnlessCanceled(ctx context.Context, queue TaskQueueInterface) (bool, error) {
+	queue.SetUpdatedAt(carbon.Now(carbon.UTC).StdTime())
+
+	result, err := store.db.Query().
+		Table(store.taskQueueTableName).
+		Where(COLUMN_ID+" = ?", queue.GetID()).
+		Where(COLUMN_STATUS+" <> ?", TaskQueueStatusCanceled).
+		Where(COLUMN_CANCEL_REQUESTED_AT+" <= ?", NULL_DATETIME).
+		Update(taskQueueToRow(queue))
+	if err != nil {
+		return false, err
+	}
+
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	// Nothing changed, either because the queued task was canceled (or
+	// asked to), or because the row already had these values, or does
+	// not exist
+	current, err := store.TaskQueueFindByID(ctx, queue.GetID())
+	if err != nil {
+		return false, err
+	}
+	return current == nil || (!current.IsCanceled() && !current.IsCancelRequested()), nil
+}
+
+// taskQueueUpdateDetails saves the details of a running queued task, i.e.
+// to report its progress before it finishes. The other columns are left
+// as they are, so the lease extended by heartbeats is kept. Nothing is
+// saved once the worker (see ContextWithWorkerID) lost the lease of this
+// attempt, i.e. after the handler timed out.
+func (store *Store) taskQueueUpdateDetails(ctx context.Context, queue TaskQueueInterface) error {
+	queue.SetUpdatedAt(carbon.Now(carbon.UTC).StdTime())
+
+	_, err := store.taskQueueUpdateRowIfLeaseHeld(ctx, queue, map[string]any{
+		COLUMN_DETAILS:    queue.GetDetails(),
+		COLUMN_UPDATED_AT: queue.GetUpdatedAt().Format("2006-01-02 15:04:05"),
+	}, store.workerIDFromContext(ctx), time.Time{})
+
+	return err
+}
+
+// taskQueueUpdateIfStatus updates a queued task, only if its status in the
+// database is one of the given statuses. Returns false if it is not.
+func (store *Store) taskQueueUpdateIfStatus(ctx context.Context, queue TaskQueueInterface, statuses ...string) (bool, error) {
+	queue.SetUpdatedAt(carbon.Now(carbon.UTC).StdTime())
+
+	args := make([]any, len(statuses))
+	for i, status := range statuses {
+		args[i] = status
+	}
+
+	result, err := store.db.Query().
+		Table(store.taskQueueTableName).
+		Where(COLUMN_ID+" = ?", queue.GetID()).
+		WhereIn(COLUMN_STATUS, args).
+		Update(taskQueueToRow(queue))
+	if err != nil {
+		return false, err
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func taskQueueToRow(queue TaskQueueInterface) map[string]any {
+	return map[string]any{
+		COLUMN_QUEUE_NAME:                queue.GetQueueName(),
+		COLUMN_TASK_ID:                   queue.GetTaskID(),
+		COLUMN_PARAMETERS:                queue.GetParameters(),
+		COLUMN_STATUS:                    queue.GetStatus(),
+		COLUMN_OUTPUT:                    queue.GetOutput(),
+		COLUMN_DETAILS:                   queue.GetDetails(),
+		COLUMN_ERROR_MESSAGE:             queue.GetErrorMessage(),
+		COLUMN_ATTEMPTS:                  queue.GetAttempts(),
+		COLUMN_PRIORITY:                  queue.GetPriority(),
+		COLUMN_STARTED_AT:                queue.GetStartedAt().Format("2006-01-02 15:04:05"),
+		COLUMN_COMPLETED_AT:              queue.GetCompletedAt().Format("2006-01-02 15:04:05"),
+		COLUMN_AVAILABLE_AT:              queue.GetAvailableAt().Format("2006-01-02 15:04:05"),
+		COLUMN_RETRY_POLICY:              retryPolicyToJSON(queue.GetRetryPolicy()),
+		COLUMN_UNIQUE_KEY:                nullableString(queue.GetUniqueKey()),
+		COLUMN_UNIQUE_UNTIL:              queue.GetUniqueUntil().Format("2006-01-02 15:04:05"),
+		COLUMN_DEPENDS_ON:                dependsOnToJSON(queue.GetDependsOn()),
+		COLUMN_DEPENDENCY_FAILURE_POLICY: queue.GetDependencyFailurePolicy(),
+		COLUMN_BATCH_ID:                  queue.GetBatchID(),
+		COLUMN_DEAD_LETTER_QUEUE_NAME:    queue.GetDeadLetterQueueName(),
+		COLUMN_DEAD_LETTERED_AT:          queue.GetDeadLetteredAt().Format("2006-01-02 15:04:05"),
+		COLUMN_TIMEOUT_SECONDS:           queue.GetTimeoutSeconds(),
+		COLUMN_LEASE_OWNER:               queue.GetLeaseOwner(),
+		COLUMN_LEASE_EXPIRES_AT:          queue.GetLeaseExpiresAt().Format("2006-01-02 15:04:05"),
+		COLUMN_WORKER_ID:                 queue.GetWorkerID(),
+		COLUMN_CLAIMED_AT:                queue.GetClaimedAt().Format("2006-01-02 15:04:05"),
+		COLUMN_UPDATED_AT:                queue.GetUpdatedAt().Format("2006-01-02 15:04:05"),
+		COLUMN_SOFT_DELETED_AT:           queue.GetSoftDeletedAt().Format("2006-01-02 15:04:05"),
+	}
+}
+
 func (store *Store) buildTaskQueueQuery(options TaskQueueQueryInterface) contractsorm.Query {
 	// Use Model() to enable neat's automatic soft delete handling via SoftDeletesMaxDate
 	q := store.db.Query().Model(&taskQueue{})
@@ -339,6 +934,26 @@ func (store *Store) buildTaskQueueQuery(options TaskQueueQueryInterface) contrac
 		return q
 	}
 
+	if options.HasAvailableAtLte() && options.AvailableAtLte() != "" {
+		q = q.Where(COLUMN_AVAILABLE_AT+" <= ?", options.AvailableAtLte())
+	}
+
+	if options.HasBatchID() && options.BatchID() != "" {
+		q = q.Where(COLUMN_BATCH_ID+" = ?", options.BatchID())
+	}
+
+	if options.HasDeadLetterQueueName() && options.DeadLetterQueueName() != "" {
+		q = q.Where(COLUMN_DEAD_LETTER_QUEUE_NAME+" = ?", options.DeadLetterQueueName())
+	}
+
+	if options.HasClaimedAtGte() && options.ClaimedAtGte() != "" {
+		q = q.Where(COLUMN_CLAIMED_AT+" >= ?", options.ClaimedAtGte())
+	}
+
+	if options.HasClaimedAtLte() && options.ClaimedAtLte() != "" {
+		q = q.Where(COLUMN_CLAIMED_AT+" <= ?", options.ClaimedAtLte())
+	}
+
 	if options.HasCreatedAtGte() && options.CreatedAtGte() != "" {
 		q = q.Where(COLUMN_CREATED_AT+" >= ?", options.CreatedAtGte())
 	}
@@ -379,6 +994,10 @@ func (store *Store) buildTaskQueueQuery(options TaskQueueQueryInterface) contrac
 		q = q.Where(COLUMN_QUEUE_NAME+" = ?", options.QueueName())
 	}
 
+	if options.HasWorkerID() && options.WorkerID() != "" {
+		q = q.Where(COLUMN_WORKER_ID+" = ?", options.WorkerID())
+	}
+
 	if options.HasLimit() && options.Limit() > 0 {
 		q = q.Limit(options.Limit())
 	}
@@ -0,0 +1,368 @@
+package taskstore
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dromara/carbon/v2"
+)
+
+// blockingContextHandler blocks until its context is canceled, then
+// returns true, to check a late success does not overwrite the cancellation
+type blockingContextHandler struct {
+	TaskDefinitionHandlerBase
+	started  chan struct{}
+	canceled atomic.Bool
+}
+
+func (h *blockingContextHandler) Alias() string {
+	return "BlockingContextHandler"
+}
+
+func (h *blockingContextHandler) Title() string {
+	return "Blocking Context Handler"
+}
+
+func (h *blockingContextHandler) Description() string {
+	return "Blocks until its context is canceled"
+}
+
+func (h *blockingContextHandler) Handle() bool {
+	return true
+}
+
+func (h *blockingContextHandler) HandleWithContext(ctx context.Context) bool {
+	close(h.started)
+	<-ctx.Done()
+	h.canceled.Store(true)
+	return true
+}
+
+var _ TaskHandlerWithContext = (*blockingContextHandler)(nil)
+
+// selfCancelingHandler cancels its own queued task, then succeeds
+type selfCancelingHandler struct {
+	TaskDefinitionHandlerBase
+	store *Store
+}
+
+func (h *selfCancelingHandler) Alias() string {
+	return "SelfCancelingHandler"
+}
+
+func (h *selfCancelingHandler) Title() string {
+	return "Self Canceling Handler"
+}
+
+func (h *selfCancelingHandler) Description() string {
+	return "Cancels its own queued task"
+}
+
+func (h *selfCancelingHandler) Handle() bool {
+	if err := h.store.TaskQueueCancel(context.Background(), h.GetQueuedTask().GetID()); err != nil {
+		return false
+	}
+	return true
+}
+
+func Test_Store_TaskQueueCancel_Queued(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueCancel: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := newTestTaskHandler()
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	queuedTask, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+	}
+
+	if err := store.TaskQueueCancel(ctx, queuedTask.GetID()); err != nil {
+		t.Fatalf("TaskQueueCancel: Error[%v]", err)
+	}
+
+	// Canceling twice is a no-op
+	if err := store.TaskQueueCancel(ctx, queuedTask.GetID()); err != nil {
+		t.Fatalf("TaskQueueCancel: Error[%v]", err)
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusCanceled {
+		t.Fatalf("Expected status %s, got %s", TaskQueueStatusCanceled, dbTask.GetStatus())
+	}
+	if !strings.Contains(dbTask.GetDetails(), "Task canceled") {
+		t.Fatalf("Expected details to record the cancellation, got %s", dbTask.GetDetails())
+	}
+	if isNullTime(dbTask.GetCompletedAt()) {
+		t.Fatal("Expected completed at to be set")
+	}
+
+	claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimedTask != nil {
+		t.Fatal("Expected a canceled task not to be claimed")
+	}
+
+	if err := store.TaskQueueCancel(ctx, "UNKNOWN"); err == nil {
+		t.Fatal("Expected an error when canceling an unknown task")
+	}
+}
+
+func Test_Store_TaskQueueCancel_Completed(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueCancel: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	queuedTask := NewTaskQueue().
+		SetTaskID("TASK_COMPLETED").
+		SetStatus(TaskQueueStatusSuccess)
+	if err := store.TaskQueueCreate(ctx, queuedTask); err != nil {
+		t.Fatalf("TaskQueueCreate: Error[%v]", err)
+	}
+
+	if err := store.TaskQueueCancel(ctx, queuedTask.GetID()); err == nil {
+		t.Fatal("Expected an error when canceling a completed task")
+	}
+}
+
+func Test_Store_TaskQueueCancel_Running(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueCancel: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := &blockingContextHandler{started: make(chan struct{})}
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	queuedTask, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+	}
+
+	claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = store.QueuedTaskProcessWithContext(ctx, claimedTask)
+	}()
+
+	select {
+	case <-handler.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the handler to start")
+	}
+
+	if err := store.TaskQueueCancel(ctx, queuedTask.GetID()); err != nil {
+		t.Fatalf("TaskQueueCancel: Error[%v]", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the handler to stop once the task is canceled")
+	}
+
+	if !handler.canceled.Load() {
+		t.Fatal("Expected the context of the handler to be canceled")
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusCanceled {
+		t.Fatalf("Expected status %s, got %s", TaskQueueStatusCanceled, dbTask.GetStatus())
+	}
+	if !strings.Contains(dbTask.GetDetails(), "Task started") || !strings.Contains(dbTask.GetDetails(), "Task canceled") {
+		t.Fatalf("Expected details to record the run and the cancellation, got %s", dbTask.GetDetails())
+	}
+	if strings.Contains(dbTask.GetDetails(), "Task completed") {
+		t.Fatalf("Expected the late success not to be recorded, got %s", dbTask.GetDetails())
+	}
+	if isNullTime(dbTask.GetCompletedAt()) {
+		t.Fatal("Expected completed at to be set")
+	}
+}
+
+func Test_Store_TaskQueueCancel_RunningWithoutWorker(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueCancel: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := newTestTaskHandler()
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	// enqueueClaimed enqueues a task with a dependent task, and claims it
+	// for a worker which is gone, and never processes it
+	enqueueClaimed := func() (TaskQueueInterface, TaskQueueInterface) {
+		t.Helper()
+		parent, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{})
+		if err != nil {
+			t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+		}
+		child, err := store.TaskDefinitionEnqueueByAliasWithOptions(ctx, DefaultQueueName, handler.Alias(), map[string]any{}, EnqueueOptions{
+			DependsOn: []string{parent.GetID()},
+		})
+		if err != nil {
+			t.Fatalf("TaskDefinitionEnqueueByAliasWithOptions: Error[%v]", err)
+		}
+
+		claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+		if err != nil {
+			t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+		}
+		if claimedTask == nil || claimedTask.GetID() != parent.GetID() {
+			t.Fatalf("TaskQueueClaimNext: Expected the parent task, got %v", claimedTask)
+		}
+		return claimedTask, child
+	}
+
+	expectStatus := func(id string, status string) TaskQueueInterface {
+		t.Helper()
+		dbTask, err := store.TaskQueueFindByID(ctx, id)
+		if err != nil {
+			t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+		}
+		if dbTask.GetStatus() != status {
+			t.Fatalf("Expected status %s, got %s", status, dbTask.GetStatus())
+		}
+		return dbTask
+	}
+
+	expectCanceled := func(id string) {
+		t.Helper()
+		dbTask := expectStatus(id, TaskQueueStatusCanceled)
+		if !strings.Contains(dbTask.GetDetails(), "Task canceled") {
+			t.Fatalf("Expected details to record the cancellation, got %s", dbTask.GetDetails())
+		}
+		if isNullTime(dbTask.GetCompletedAt()) {
+			t.Fatal("Expected completed at to be set")
+		}
+		if dbTask.GetLeaseOwner() != "" {
+			t.Fatalf("Expected the lease to be released, got owner %s", dbTask.GetLeaseOwner())
+		}
+	}
+
+	t.Run("expired lease is canceled right away", func(t *testing.T) {
+		claimedTask, child := enqueueClaimed()
+
+		claimedTask.SetLeaseExpiresAt(carbon.Now(carbon.UTC).StdTime().Add(-time.Minute))
+		if err := store.TaskQueueUpdate(ctx, claimedTask); err != nil {
+			t.Fatalf("TaskQueueUpdate: Error[%v]", err)
+		}
+
+		if err := store.TaskQueueCancel(ctx, claimedTask.GetID()); err != nil {
+			t.Fatalf("TaskQueueCancel: Error[%v]", err)
+		}
+
+		expectCanceled(claimedTask.GetID())
+		expectStatus(child.GetID(), TaskQueueStatusCanceled)
+	})
+
+	t.Run("live lease is canceled once it expired", func(t *testing.T) {
+		claimedTask, child := enqueueClaimed()
+
+		if err := store.TaskQueueCancel(ctx, claimedTask.GetID()); err != nil {
+			t.Fatalf("TaskQueueCancel: Error[%v]", err)
+		}
+
+		// Only the cancel request is recorded while the lease is held
+		dbTask := expectStatus(claimedTask.GetID(), TaskQueueStatusRunning)
+		if !dbTask.IsCancelRequested() {
+			t.Fatal("Expected the cancel request to be recorded")
+		}
+		expectStatus(child.GetID(), TaskQueueStatusPaused)
+
+		// Canceling again is harmless
+		if err := store.TaskQueueCancel(ctx, claimedTask.GetID()); err != nil {
+			t.Fatalf("TaskQueueCancel: Error[%v]", err)
+		}
+
+		dbTask.SetLeaseExpiresAt(carbon.Now(carbon.UTC).StdTime().Add(-time.Minute))
+		if err := store.TaskQueueUpdate(ctx, dbTask); err != nil {
+			t.Fatalf("TaskQueueUpdate: Error[%v]", err)
+		}
+
+		reaped, err := store.TaskQueueReapExpiredLeases(ctx, DefaultQueueName)
+		if err != nil {
+			t.Fatalf("TaskQueueReapExpiredLeases: Error[%v]", err)
+		}
+		if reaped != 1 {
+			t.Fatalf("Expected 1 reaped task, got %d", reaped)
+		}
+
+		expectCanceled(claimedTask.GetID())
+		expectStatus(child.GetID(), TaskQueueStatusCanceled)
+	})
+}
+
+func Test_Store_TaskQueueCancel_NotOverwrittenBySuccess(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueCancel: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := &selfCancelingHandler{store: store}
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	queuedTask, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+	}
+
+	claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+
+	if _, err := store.QueuedTaskProcessWithContext(ctx, claimedTask); err != nil {
+		t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusCanceled {
+		t.Fatalf("Expected status %s, got %s", TaskQueueStatusCanceled, dbTask.GetStatus())
+	}
+}
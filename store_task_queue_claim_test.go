@@ -0,0 +1,111 @@
+package taskstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// Test_Store_TaskQueueClaimBatch_ConcurrentClaimers runs several claimers,
+// each with its own connection pool as if in separate processes, against
+// one SQLite database file, and checks that no task is claimed twice.
+func Test_Store_TaskQueueClaimBatch_ConcurrentClaimers(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "claim.db")
+
+	store, err := initStore(filename)
+	if err != nil {
+		t.Fatalf("initStore: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	const taskCount = 200
+	const claimerCount = 8
+
+	for i := 0; i < taskCount; i++ {
+		if err := store.TaskQueueCreate(ctx, NewTaskQueue().SetTaskID("TASK_CLAIM")); err != nil {
+			t.Fatalf("TaskQueueCreate: Error[%v]", err)
+		}
+	}
+
+	claimers := make([]*Store, claimerCount)
+	for i := range claimers {
+		db, err := sql.Open("sqlite", filename+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)")
+		if err != nil {
+			t.Fatalf("sql.Open: Error[%v]", err)
+		}
+		defer db.Close()
+
+		claimers[i], err = NewStore(NewStoreOptions{
+			TaskDefinitionTableName: "task_definition",
+			TaskQueueTableName:      "task_queue",
+			ScheduleTableName:       "schedules",
+			DB:                      db,
+		})
+		if err != nil {
+			t.Fatalf("NewStore: Error[%v]", err)
+		}
+	}
+
+	var mu sync.Mutex
+	claimedBy := map[string]string{}
+	duplicates := []string{}
+	errs := []error{}
+
+	var wg sync.WaitGroup
+	for i, claimer := range claimers {
+		wg.Add(1)
+		go func(claimer *Store, workerID string) {
+			defer wg.Done()
+			claimCtx := ContextWithWorkerID(ctx, workerID)
+
+			for {
+				tasks, err := claimer.TaskQueueClaimBatch(claimCtx, DefaultQueueName, 3)
+				if err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+					return
+				}
+				if len(tasks) == 0 {
+					return
+				}
+
+				mu.Lock()
+				for _, task := range tasks {
+					if previous, ok := claimedBy[task.GetID()]; ok {
+						duplicates = append(duplicates, fmt.Sprintf("%s by %s and %s", task.GetID(), previous, workerID))
+					}
+					claimedBy[task.GetID()] = workerID
+				}
+				mu.Unlock()
+			}
+		}(claimer, fmt.Sprintf("claimer-%d", i))
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		t.Fatalf("Expected no claim errors, got %d: %v", len(errs), errs[0])
+	}
+	if len(duplicates) > 0 {
+		t.Fatalf("Expected no task to be claimed twice, got %d: %v", len(duplicates), duplicates)
+	}
+	if len(claimedBy) != taskCount {
+		t.Fatalf("Expected all %d tasks to be claimed, got %d", taskCount, len(claimedBy))
+	}
+
+	// The database agrees with the claimers
+	for taskID, workerID := range claimedBy {
+		task, err := store.TaskQueueFindByID(ctx, taskID)
+		if err != nil {
+			t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+		}
+		if task.GetStatus() != TaskQueueStatusRunning || task.GetWorkerID() != workerID {
+			t.Fatalf("Expected task %s running for %s, got %s for %s", taskID, workerID, task.GetStatus(), task.GetWorkerID())
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package taskstore
+
+import (
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+)
+
+// taskQueueConcurrencyLimitRemaining checks the concurrency limits of the
+// candidate task definitions (those with tasks which may be claimed) within
+// the claim transaction tx, counting their running
+// tasks across all queues and workers. On databases other than SQLite, the
+// rows holding the limits are locked until the claim commits, so concurrent
+// claimers in other processes wait instead of exceeding the limits.
+//
+// Returns how many more tasks may be claimed per concurrency limited task
+// definition, by task definition ID. Tasks of a definition at its limit
+// must stay queued until a running one finishes.
+func (store *Store) taskQueueConcurrencyLimitRemaining(tx contractsorm.Query, candidateTaskIDs []any) (map[string]int, error) {
+	taskRemaining := map[string]int{}
+	if len(candidateTaskIDs) == 0 {
+		return taskRemaining, nil
+	}
+
+	q := txTable(tx, store.taskDefinitionTableName).
+		WhereIn(COLUMN_ID, candidateTaskIDs).
+		Where(COLUMN_MAX_CONCURRENCY+" > ?", 0)
+	if !store.isSQLite {
+		q = q.LockForUpdate()
+	}
+
+	var definitions []taskDefinition
+	if err := q.Find(&definitions); err != nil {
+		return nil, err
+	}
+
+	for _, definition := range definitions {
+		var running int64
+		err := txTable(tx, store.taskQueueTableName).
+			Where(COLUMN_TASK_ID+" = ?", definition.GetID()).
+			Where(COLUMN_STATUS+" = ?", TaskQueueStatusRunning).
+			Count(&running)
+		if err != nil {
+			return nil, err
+		}
+		taskRemaining[definition.GetID()] = max(definition.GetMaxConcurrency()-int(running), 0)
+	}
+
+	return taskRemaining, nil
+}
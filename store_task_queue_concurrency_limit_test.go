@@ -0,0 +1,136 @@
+package taskstore
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_Store_TaskQueueClaimNext_DefinitionConcurrencyLimit(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	definition := NewTaskDefinition().
+		SetAlias("GenerateInvoice").
+		SetTitle("Generate Invoice").
+		SetMaxConcurrency(2)
+	if err := store.TaskDefinitionCreate(ctx, definition); err != nil {
+		t.Fatalf("TaskDefinitionCreate: Error[%v]", err)
+	}
+
+	// The limit holds across queues
+	for _, queueName := range []string{DefaultQueueName, "invoices", DefaultQueueName} {
+		if err := store.TaskQueueCreate(ctx, NewTaskQueue().SetTaskID(definition.GetID()).SetQueueName(queueName)); err != nil {
+			t.Fatalf("TaskQueueCreate: Error[%v]", err)
+		}
+	}
+
+	first, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if first == nil {
+		t.Fatal("Expected the first task to be claimed")
+	}
+
+	second, err := store.TaskQueueClaimNext(ctx, "invoices")
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if second == nil {
+		t.Fatal("Expected the second task to be claimed")
+	}
+
+	third, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if third != nil {
+		t.Fatal("Expected the third task to stay queued while two are running")
+	}
+
+	// Finishing a running task frees a slot
+	if err := store.TaskQueueSuccess(ctx, first); err != nil {
+		t.Fatalf("TaskQueueSuccess: Error[%v]", err)
+	}
+
+	third, err = store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if third == nil {
+		t.Fatal("Expected the third task to be claimed once a slot is free")
+	}
+}
+
+func Test_Store_TaskQueueClaimNext_Singleton(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	singleton := NewTaskDefinition().
+		SetAlias("NightlyReconcile").
+		SetTitle("Nightly Reconcile").
+		SetMaxConcurrency(1)
+	if err := store.TaskDefinitionCreate(ctx, singleton); err != nil {
+		t.Fatalf("TaskDefinitionCreate: Error[%v]", err)
+	}
+
+	other := NewTaskDefinition().
+		SetAlias("SendEmail").
+		SetTitle("Send Email")
+	if err := store.TaskDefinitionCreate(ctx, other); err != nil {
+		t.Fatalf("TaskDefinitionCreate: Error[%v]", err)
+	}
+
+	for _, taskID := range []string{singleton.GetID(), singleton.GetID(), other.GetID()} {
+		if err := store.TaskQueueCreate(ctx, NewTaskQueue().SetTaskID(taskID)); err != nil {
+			t.Fatalf("TaskQueueCreate: Error[%v]", err)
+		}
+	}
+
+	// The second singleton task is skipped, other tasks are not held back
+	expected := []string{singleton.GetID(), other.GetID()}
+	claimedTasks := []TaskQueueInterface{}
+	for _, taskID := range expected {
+		claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+		if err != nil {
+			t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+		}
+		if claimedTask == nil {
+			t.Fatalf("Expected a task of %s to be claimed", taskID)
+		}
+		if claimedTask.GetTaskID() != taskID {
+			t.Fatalf("Expected a task of %s to be claimed, got %s", taskID, claimedTask.GetTaskID())
+		}
+		claimedTasks = append(claimedTasks, claimedTask)
+	}
+
+	claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimedTask != nil {
+		t.Fatal("Expected the second singleton task to stay queued")
+	}
+
+	if err := store.TaskQueueFail(ctx, claimedTasks[0]); err != nil {
+		t.Fatalf("TaskQueueFail: Error[%v]", err)
+	}
+
+	claimedTask, err = store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimedTask == nil || claimedTask.GetTaskID() != singleton.GetID() {
+		t.Fatalf("Expected the second singleton task to be claimed, got %v", claimedTask)
+	}
+}
@@ -0,0 +1,166 @@
+package taskstore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// erroringHandler returns the error named by its "error" parameter
+type erroringHandler struct {
+	TaskDefinitionHandlerBase
+}
+
+func (h *erroringHandler) Alias() string {
+	return "ErroringHandler"
+}
+
+func (h *erroringHandler) Title() string {
+	return "Erroring Handler"
+}
+
+func (h *erroringHandler) Description() string {
+	return "Returns the error named by its error parameter"
+}
+
+func (h *erroringHandler) Handle() bool {
+	return false
+}
+
+func (h *erroringHandler) HandleE(ctx context.Context) error {
+	switch h.GetParam("error") {
+	case "retryable":
+		return errors.New("service unavailable")
+	case "permanent":
+		return PermanentError(errors.New("invalid recipient"))
+	default:
+		return nil
+	}
+}
+
+var _ TaskHandlerWithError = (*erroringHandler)(nil)
+
+func Test_Store_QueuedTaskProcess_HandlerError(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("QueuedTaskProcess: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	handledErrors := map[string]error{}
+	store.SetErrorHandler(func(queueName, taskID string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		handledErrors[taskID] = err
+	})
+
+	handler := &erroringHandler{}
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	definition, err := store.TaskDefinitionFindByAlias(ctx, handler.Alias())
+	if err != nil {
+		t.Fatalf("TaskDefinitionFindByAlias: Error[%v]", err)
+	}
+	definition.SetRetryPolicy(&RetryPolicy{MaxAttempts: 3})
+	if err := store.TaskDefinitionUpdate(ctx, definition); err != nil {
+		t.Fatalf("TaskDefinitionUpdate: Error[%v]", err)
+	}
+
+	tests := []struct {
+		errorParam   string
+		status       string
+		errorMessage string
+		permanent    bool
+	}{
+		{errorParam: "retryable", status: TaskQueueStatusQueued, errorMessage: "service unavailable"},
+		{errorParam: "permanent", status: TaskQueueStatusFailed, errorMessage: "invalid recipient", permanent: true},
+		{errorParam: "none", status: TaskQueueStatusSuccess},
+	}
+
+	for _, tt := range tests {
+		queuedTask, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{
+			"error": tt.errorParam,
+		})
+		if err != nil {
+			t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+		}
+
+		if _, err := store.QueuedTaskProcessWithContext(ctx, queuedTask); err != nil {
+			t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+		}
+
+		dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+		if err != nil {
+			t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+		}
+		if dbTask.GetStatus() != tt.status {
+			t.Fatalf("%s: Expected status %s, got %s", tt.errorParam, tt.status, dbTask.GetStatus())
+		}
+		if dbTask.GetErrorMessage() != tt.errorMessage {
+			t.Fatalf("%s: Expected error message %q, got %q", tt.errorParam, tt.errorMessage, dbTask.GetErrorMessage())
+		}
+		if tt.errorMessage != "" && !strings.Contains(dbTask.GetDetails(), "Task failed with error: "+tt.errorMessage) {
+			t.Fatalf("%s: Expected the error in the details, got %s", tt.errorParam, dbTask.GetDetails())
+		}
+
+		mu.Lock()
+		handledErr, handled := handledErrors[queuedTask.GetID()]
+		mu.Unlock()
+		if handled != (tt.errorMessage != "") {
+			t.Fatalf("%s: Expected the error handler to be called: %v, got %v", tt.errorParam, tt.errorMessage != "", handled)
+		}
+		if handled && IsPermanentError(handledErr) != tt.permanent {
+			t.Fatalf("%s: Expected a permanent error: %v, got %v", tt.errorParam, tt.permanent, handledErr)
+		}
+	}
+}
+
+func Test_Store_QueuedTaskProcess_SuccessClearsErrorMessage(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("QueuedTaskProcess: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := &erroringHandler{}
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	queuedTask, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{
+		"error": "none",
+	})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+	}
+
+	// As left by a failed previous attempt
+	queuedTask.SetErrorMessage("service unavailable")
+	if err := store.TaskQueueUpdate(ctx, queuedTask); err != nil {
+		t.Fatalf("TaskQueueUpdate: Error[%v]", err)
+	}
+
+	if _, err := store.QueuedTaskProcessWithContext(ctx, queuedTask); err != nil {
+		t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusSuccess {
+		t.Fatalf("Expected status %s, got %s", TaskQueueStatusSuccess, dbTask.GetStatus())
+	}
+	if dbTask.GetErrorMessage() != "" {
+		t.Fatalf("Expected the error message to be cleared, got %q", dbTask.GetErrorMessage())
+	}
+}
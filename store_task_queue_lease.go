@@ -0,0 +1,318 @@
+package taskstore
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/dromara/carbon/v2"
+)
+
+// ErrTaskQueueLeaseLost is returned by TaskQueueHeartbeat when the queued
+// task is no longer running under the lease of the worker, i.e. because it
+// was reaped after its lease expired. It is also the cause
+// of the cancellation of the context passed to
+// TaskHandlerWithContext.HandleWithContext in that case.
+var ErrTaskQueueLeaseLost = errors.New("queued task lease lost")
+
+type workerIDContextKey struct{}
+
+// ContextWithWorkerID returns a copy of ctx carrying the ID of the worker
+// which claims and processes queued tasks with it. The ID is recorded on
+// the claimed tasks, and owns their leases. TaskQueueRunner sets it from
+// TaskQueueRunnerOptions.WorkerID. Without it, the worker ID the store
+// generated on creation is used.
+func ContextWithWorkerID(ctx context.Context, workerID string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, workerIDContextKey{}, workerID)
+}
+
+// workerIDFromContext returns the worker ID carried by ctx, or else the
+// worker ID of the store
+func (store *Store) workerIDFromContext(ctx context.Context) string {
+	if ctx != nil {
+		if workerID, ok := ctx.Value(workerIDContextKey{}).(string); ok && workerID != "" {
+			return workerID
+		}
+	}
+	return store.workerID
+}
+
+// TaskQueueHeartbeat extends the lease of a running queued task claimed
+// by the worker (see ContextWithWorkerID), by the lease duration of the
+// store.
+//
+// Leases are extended automatically while a task is processed. Handlers
+// which block the worker in other ways can call it to keep their lease.
+//
+// Returns ErrTaskQueueLeaseLost if the task is not running under the lease
+// of the worker anymore.
+func (store *Store) TaskQueueHeartbeat(ctx context.Context, queuedTaskID string) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if queuedTaskID == "" {
+		return errors.New("queued task id is empty")
+	}
+
+	now := carbon.Now(carbon.UTC).StdTime()
+
+	result, err := store.db.Query().
+		Table(store.taskQueueTableName).
+		Where(COLUMN_ID+" = ?", queuedTaskID).
+		Where(COLUMN_STATUS+" = ?", TaskQueueStatusRunning).
+		Where(COLUMN_LEASE_OWNER+" = ?", store.workerIDFromContext(ctx)).
+		Update(map[string]any{
+			COLUMN_LEASE_EXPIRES_AT: now.Add(store.leaseDuration).Format("2006-01-02 15:04:05"),
+			COLUMN_UPDATED_AT:       now.Format("2006-01-02 15:04:05"),
+		})
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	// Nothing changed, either because the lease was lost, or because the
+	// row already had these values (within the same second)
+	var count int64
+	err = store.db.Query().
+		Table(store.taskQueueTableName).
+		Where(COLUMN_ID+" = ?", queuedTaskID).
+		Where(COLUMN_STATUS+" = ?", TaskQueueStatusRunning).
+		Where(COLUMN_LEASE_OWNER+" = ?", store.workerIDFromContext(ctx)).
+		Count(&count)
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return ErrTaskQueueLeaseLost
+	}
+
+	return nil
+}
+
+// TaskQueueReapExpiredLeases reaps the running tasks of a queue whose lease
+// expired, as their worker stopped without finishing them (i.e. crashed or
+// was killed). An empty queue name reaps the tasks of all queues.
+//
+// Business logic:
+//   - a reaped task counts as a failed attempt. It goes back to the queue
+//     when its retry policy allows another attempt, otherwise it fails
+//     (and is moved to the dead letter queue, if its definition has one)
+//   - running tasks without a lease (claimed before leases were
+//     introduced) are reaped once they started longer than the lease
+//     duration ago
+//   - a task whose lease was extended meanwhile is not reaped
+//   - the claims recorded for the rate limits are pruned (of all queues)
+//     once they are older than the longest rate limit window
+//   - the workers dead for longer than the worker retention are pruned
+//     (see WorkerPruneDead)
+//
+// Returns the number of reaped tasks.
+func (store *Store) TaskQueueReapExpiredLeases(ctx context.Context, queueName string) (int64, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := store.taskQueueClaimsPrune(ctx); err != nil {
+		return 0, err
+	}
+
+	if _, err := store.WorkerPruneDead(ctx); err != nil {
+		return 0, err
+	}
+
+	q := store.db.Query().
+		Table(store.taskQueueTableName).
+		Where(COLUMN_STATUS+" = ?", TaskQueueStatusRunning).
+		Where(COLUMN_LEASE_OWNER+" <> ?", "").
+		Where(COLUMN_LEASE_EXPIRES_AT+" < ?", carbon.Now(carbon.UTC).ToDateTimeString(carbon.UTC))
+	if queueName != "" {
+		q = q.Where(COLUMN_QUEUE_NAME+" = ?", normalizeQueueName(queueName))
+	}
+
+	var expiredTasks []taskQueue
+	if err := q.Find(&expiredTasks); err != nil {
+		return 0, err
+	}
+
+	q = store.db.Query().
+		Table(store.taskQueueTableName).
+		Where(COLUMN_STATUS+" = ?", TaskQueueStatusRunning).
+		Where("("+COLUMN_LEASE_OWNER+" IS NULL OR "+COLUMN_LEASE_OWNER+" = ?)", "").
+		Where("("+COLUMN_LEASE_EXPIRES_AT+" IS NULL OR "+COLUMN_LEASE_EXPIRES_AT+" <= ?)", NULL_DATETIME).
+		Where(COLUMN_STARTED_AT+" < ?", carbon.Now(carbon.UTC).StdTime().Add(-store.leaseDuration).Format("2006-01-02 15:04:05"))
+	if queueName != "" {
+		q = q.Where(COLUMN_QUEUE_NAME+" = ?", normalizeQueueName(queueName))
+	}
+
+	var leaselessTasks []taskQueue
+	if err := q.Find(&leaselessTasks); err != nil {
+		return 0, err
+	}
+	expiredTasks = append(expiredTasks, leaselessTasks...)
+
+	var reaped int64
+	for i := range expiredTasks {
+		ok, err := store.queuedTaskReap(ctx, &expiredTasks[i])
+		if err != nil {
+			return reaped, err
+		}
+		if ok {
+			reaped++
+		}
+	}
+
+	return reaped, nil
+}
+
+// queuedTaskReap fails or requeues a running queued task whose lease
+// expired (or which runs without a lease for too long), or cancels it if it
+// was asked to (see TaskQueueCancel).
+// Returns false if the lease was extended or released meanwhile.
+func (store *Store) queuedTaskReap(ctx context.Context, queuedTask TaskQueueInterface) (bool, error) {
+	leaseOwner := queuedTask.GetLeaseOwner()
+	leaseExpiresAt := queuedTask.GetLeaseExpiresAt()
+
+	task, err := store.TaskDefinitionFindByID(ctx, queuedTask.GetTaskID())
+	if err != nil {
+		return false, err
+	}
+
+	if leaseOwner == "" {
+		queuedTask.AppendDetails("Task running without a lease for longer than the lease duration")
+	} else {
+		queuedTask.AppendDetails("Task lease of worker " + leaseOwner + " expired")
+	}
+	if queuedTask.IsCancelRequested() {
+		queuedTask.AppendDetails("Task canceled")
+		queuedTask.SetStatus(TaskQueueStatusCanceled)
+		queuedTask.SetCompletedAt(carbon.Now(carbon.UTC).StdTime())
+	} else if task == nil {
+		queuedTask.AppendDetails("Task DOES NOT exist")
+		queuedTask.SetStatus(TaskQueueStatusFailed)
+		queuedTask.SetCompletedAt(carbon.Now(carbon.UTC).StdTime())
+	} else {
+		queuedTaskSetFailedOrRetry(task, queuedTask)
+	}
+	queuedTask.SetLeaseOwner("")
+	queuedTask.SetLeaseExpiresAt(time.Time{})
+
+	reaped, err := store.taskQueueUpdateIfLeaseHeld(ctx, queuedTask, leaseOwner, leaseExpiresAt)
+	if err != nil || !reaped {
+		return false, err
+	}
+
+	if queuedTask.IsFailed() || queuedTask.IsCanceled() {
+		return true, store.taskQueueCompleted(ctx, queuedTask)
+	}
+
+	return true, nil
+}
+
+// taskQueueUpdateIfLeaseHeld updates a queued task, only if it is running
+// under the lease of the given owner in the database, in the same attempt.
+// When leaseExpiresAt is not zero, the lease must also still expire at that
+// time. Returns false if it is not.
+//
+// A task which was asked to stop (see TaskQueueCancel) is only updated to
+// record its cancellation, which clears the cancel request.
+func (store *Store) taskQueueUpdateIfLeaseHeld(ctx context.Context, queue TaskQueueInterface, leaseOwner string, leaseExpiresAt time.Time) (bool, error) {
+	queue.SetUpdatedAt(carbon.Now(carbon.UTC).StdTime())
+
+	row := taskQueueToRow(queue)
+	if queue.IsCanceled() {
+		row[COLUMN_CANCEL_REQUESTED_AT] = NULL_DATETIME
+	}
+
+	return store.taskQueueUpdateRowIfLeaseHeld(ctx, queue, row, leaseOwner, leaseExpiresAt)
+}
+
+// taskQueueUpdateRowIfLeaseHeld updates the columns of the row of a queued
+// task, under the conditions of taskQueueUpdateIfLeaseHeld. The attempt
+// fences off a handler which was abandoned when it timed out, from a later
+// attempt of its task claimed by the same worker.
+func (store *Store) taskQueueUpdateRowIfLeaseHeld(ctx context.Context, queue TaskQueueInterface, row map[string]any, leaseOwner string, leaseExpiresAt time.Time) (bool, error) {
+	q := store.db.Query().
+		Table(store.taskQueueTableName).
+		Where(COLUMN_ID+" = ?", queue.GetID()).
+		Where(COLUMN_STATUS+" = ?", TaskQueueStatusRunning).
+		Where(COLUMN_LEASE_OWNER+" = ?", leaseOwner).
+		Where(COLUMN_ATTEMPTS+" = ?", queue.GetAttempts())
+	if !leaseExpiresAt.IsZero() {
+		q = q.Where(COLUMN_LEASE_EXPIRES_AT+" = ?", leaseExpiresAt.Format("2006-01-02 15:04:05"))
+	}
+	if !queue.IsCanceled() {
+		q = q.Where(COLUMN_CANCEL_REQUESTED_AT+" <= ?", NULL_DATETIME)
+	}
+
+	result, err := q.Update(row)
+	if err != nil {
+		return false, err
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// queuedTaskHeartbeat watches a running queued task while it is processed.
+// It checks whether the task was asked to stop (see TaskQueueCancel) at
+// the cancel check interval of the store, and extends its lease at a third
+// of the lease duration. It cancels ctx with ErrTaskQueueCanceled once the
+// task is asked to stop, or with ErrTaskQueueLeaseLost once the lease is
+// lost. It returns when ctx is done.
+func (store *Store) queuedTaskHeartbeat(ctx context.Context, queuedTaskID string, cancel context.CancelCauseFunc) {
+	heartbeatInterval := store.leaseDuration / 3
+
+	ticker := time.NewTicker(min(store.cancelCheckInterval, heartbeatInterval))
+	defer ticker.Stop()
+
+	lastHeartbeat := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if time.Since(lastHeartbeat) >= heartbeatInterval {
+			err := store.TaskQueueHeartbeat(ctx, queuedTaskID)
+			if errors.Is(err, ErrTaskQueueLeaseLost) {
+				cancel(ErrTaskQueueLeaseLost)
+				return
+			}
+			if err != nil {
+				if store.debugEnabled {
+					log.Println(err)
+				}
+			} else {
+				lastHeartbeat = time.Now()
+			}
+		}
+
+		var count int64
+		err := store.db.Query().
+			Table(store.taskQueueTableName).
+			Where(COLUMN_ID+" = ?", queuedTaskID).
+			Where(COLUMN_STATUS+" = ?", TaskQueueStatusRunning).
+			Where(COLUMN_CANCEL_REQUESTED_AT+" > ?", NULL_DATETIME).
+			Count(&count)
+		if err != nil {
+			if store.debugEnabled {
+				log.Println(err)
+			}
+			continue
+		}
+
+		if count > 0 {
+			cancel(ErrTaskQueueCanceled)
+			return
+		}
+	}
+}
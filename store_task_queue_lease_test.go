@@ -0,0 +1,407 @@
+package taskstore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dromara/carbon/v2"
+)
+
+// leaseStealingHandler hands the lease of its own queued task over to
+// another worker, as if it was reaped and claimed again, then succeeds
+type leaseStealingHandler struct {
+	TaskDefinitionHandlerBase
+	store *Store
+}
+
+func (h *leaseStealingHandler) Alias() string {
+	return "LeaseStealingHandler"
+}
+
+func (h *leaseStealingHandler) Title() string {
+	return "Lease Stealing Handler"
+}
+
+func (h *leaseStealingHandler) Description() string {
+	return "Hands its lease over to another worker"
+}
+
+func (h *leaseStealingHandler) Handle() bool {
+	_, err := h.store.GetDB().Exec("UPDATE "+h.store.GetTaskQueueTableName()+" SET "+COLUMN_LEASE_OWNER+" = ? WHERE "+COLUMN_ID+" = ?", "OTHER_WORKER", h.GetQueuedTask().GetID())
+	return err == nil
+}
+
+// createExpiredLeaseTask creates a running queued task whose lease expired
+func createExpiredLeaseTask(t *testing.T, store *Store, taskID string, policy *RetryPolicy) TaskQueueInterface {
+	t.Helper()
+
+	queuedTask := NewTaskQueue().
+		SetTaskID(taskID).
+		SetStatus(TaskQueueStatusRunning).
+		SetAttempts(1).
+		SetRetryPolicy(policy).
+		SetLeaseOwner("CRASHED_WORKER").
+		SetLeaseExpiresAt(carbon.Now(carbon.UTC).StdTime().Add(-time.Minute))
+	if err := store.TaskQueueCreate(context.Background(), queuedTask); err != nil {
+		t.Fatalf("TaskQueueCreate: Error[%v]", err)
+	}
+
+	return queuedTask
+}
+
+func Test_Store_TaskQueueClaimNext_TakesLease(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	queuedTask := NewTaskQueue().SetTaskID("TASK_LEASED")
+	if err := store.TaskQueueCreate(ctx, queuedTask); err != nil {
+		t.Fatalf("TaskQueueCreate: Error[%v]", err)
+	}
+
+	claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimedTask == nil {
+		t.Fatal("Expected a task to be claimed")
+	}
+	if claimedTask.GetLeaseOwner() != store.workerID {
+		t.Fatalf("Expected lease owner %s, got %s", store.workerID, claimedTask.GetLeaseOwner())
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetLeaseOwner() != store.workerID {
+		t.Fatalf("Expected lease owner %s, got %s", store.workerID, dbTask.GetLeaseOwner())
+	}
+	if !dbTask.GetLeaseExpiresAt().After(time.Now().Add(DefaultLeaseDuration - time.Minute)) {
+		t.Fatalf("Expected the lease to expire in %s, got %s", DefaultLeaseDuration, dbTask.GetLeaseExpiresAt())
+	}
+}
+
+func Test_Store_TaskQueueClaimNext_RecordsWorker(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	defaultTask := NewTaskQueue().SetTaskID("TASK_DEFAULT_WORKER")
+	if err := store.TaskQueueCreate(ctx, defaultTask); err != nil {
+		t.Fatalf("TaskQueueCreate: Error[%v]", err)
+	}
+	workerTask := NewTaskQueue().SetTaskID("TASK_WORKER_1")
+	if err := store.TaskQueueCreate(ctx, workerTask); err != nil {
+		t.Fatalf("TaskQueueCreate: Error[%v]", err)
+	}
+
+	// Without a worker ID in the context, the one of the store is used
+	claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimedTask.GetWorkerID() != store.workerID {
+		t.Fatalf("Expected worker ID %s, got %s", store.workerID, claimedTask.GetWorkerID())
+	}
+
+	claimedTask, err = store.TaskQueueClaimNext(ContextWithWorkerID(ctx, "WORKER_1"), DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimedTask.GetWorkerID() != "WORKER_1" || claimedTask.GetLeaseOwner() != "WORKER_1" {
+		t.Fatalf("Expected worker ID and lease owner WORKER_1, got %s and %s", claimedTask.GetWorkerID(), claimedTask.GetLeaseOwner())
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, claimedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetWorkerID() != "WORKER_1" {
+		t.Fatalf("Expected worker ID WORKER_1, got %s", dbTask.GetWorkerID())
+	}
+	if isNullTime(dbTask.GetClaimedAt()) {
+		t.Fatal("Expected claimed at to be set")
+	}
+
+	list, err := store.TaskQueueList(ctx, TaskQueueQuery().
+		SetWorkerID("WORKER_1").
+		SetClaimedAtGte(carbon.Now(carbon.UTC).SubMinute().ToDateTimeString(carbon.UTC)))
+	if err != nil {
+		t.Fatalf("TaskQueueList: Error[%v]", err)
+	}
+	if len(list) != 1 || list[0].GetID() != claimedTask.GetID() {
+		t.Fatalf("Expected only the task claimed by WORKER_1, got %d tasks", len(list))
+	}
+}
+
+func Test_Store_TaskQueueHeartbeat(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueHeartbeat: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	leasedTask := NewTaskQueue().
+		SetTaskID("TASK_LEASED").
+		SetStatus(TaskQueueStatusRunning).
+		SetLeaseOwner(store.workerID).
+		SetLeaseExpiresAt(carbon.Now(carbon.UTC).StdTime().Add(time.Second))
+	if err := store.TaskQueueCreate(ctx, leasedTask); err != nil {
+		t.Fatalf("TaskQueueCreate: Error[%v]", err)
+	}
+
+	if err := store.TaskQueueHeartbeat(ctx, leasedTask.GetID()); err != nil {
+		t.Fatalf("TaskQueueHeartbeat: Error[%v]", err)
+	}
+
+	// A second heartbeat within the same second is fine too
+	if err := store.TaskQueueHeartbeat(ctx, leasedTask.GetID()); err != nil {
+		t.Fatalf("TaskQueueHeartbeat: Error[%v]", err)
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, leasedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if !dbTask.GetLeaseExpiresAt().After(time.Now().Add(DefaultLeaseDuration - time.Minute)) {
+		t.Fatalf("Expected the lease to be extended, got %s", dbTask.GetLeaseExpiresAt())
+	}
+
+	otherTask := createExpiredLeaseTask(t, store, "TASK_OTHER", nil)
+	if err := store.TaskQueueHeartbeat(ctx, otherTask.GetID()); !errors.Is(err, ErrTaskQueueLeaseLost) {
+		t.Fatalf("Expected %v for a task leased by another worker, got %v", ErrTaskQueueLeaseLost, err)
+	}
+}
+
+func Test_Store_TaskQueueReapExpiredLeases(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueReapExpiredLeases: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	definition := NewTaskDefinition().
+		SetAlias("ImportJob").
+		SetTitle("Import Job")
+	if err := store.TaskDefinitionCreate(ctx, definition); err != nil {
+		t.Fatalf("TaskDefinitionCreate: Error[%v]", err)
+	}
+
+	requeuedTask := createExpiredLeaseTask(t, store, definition.GetID(), &RetryPolicy{MaxAttempts: 3})
+	failedTask := createExpiredLeaseTask(t, store, definition.GetID(), nil)
+
+	leasedTask := NewTaskQueue().
+		SetTaskID(definition.GetID()).
+		SetStatus(TaskQueueStatusRunning).
+		SetLeaseOwner("LIVE_WORKER").
+		SetLeaseExpiresAt(carbon.Now(carbon.UTC).StdTime().Add(time.Hour))
+	if err := store.TaskQueueCreate(ctx, leasedTask); err != nil {
+		t.Fatalf("TaskQueueCreate: Error[%v]", err)
+	}
+
+	reaped, err := store.TaskQueueReapExpiredLeases(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueReapExpiredLeases: Error[%v]", err)
+	}
+	if reaped != 2 {
+		t.Fatalf("Expected 2 reaped tasks, got %d", reaped)
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, requeuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusQueued {
+		t.Fatalf("Expected status %s, got %s", TaskQueueStatusQueued, dbTask.GetStatus())
+	}
+	if dbTask.GetLeaseOwner() != "" {
+		t.Fatalf("Expected the lease to be released, got %s", dbTask.GetLeaseOwner())
+	}
+	if !strings.Contains(dbTask.GetDetails(), "Task lease of worker CRASHED_WORKER expired") {
+		t.Fatalf("Expected details to record the expired lease, got %s", dbTask.GetDetails())
+	}
+
+	dbTask, err = store.TaskQueueFindByID(ctx, failedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusFailed {
+		t.Fatalf("Expected status %s, got %s", TaskQueueStatusFailed, dbTask.GetStatus())
+	}
+	if isNullTime(dbTask.GetCompletedAt()) {
+		t.Fatal("Expected completed at to be set")
+	}
+
+	dbTask, err = store.TaskQueueFindByID(ctx, leasedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusRunning {
+		t.Fatalf("Expected a task with a live lease to keep running, got %s", dbTask.GetStatus())
+	}
+
+	reaped, err = store.TaskQueueReapExpiredLeases(ctx, "")
+	if err != nil {
+		t.Fatalf("TaskQueueReapExpiredLeases: Error[%v]", err)
+	}
+	if reaped != 0 {
+		t.Fatalf("Expected nothing left to reap, got %d", reaped)
+	}
+}
+
+func Test_Store_TaskQueueReapExpiredLeases_WithoutLease(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueReapExpiredLeases: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	definition := NewTaskDefinition().
+		SetAlias("ImportJob").
+		SetTitle("Import Job")
+	if err := store.TaskDefinitionCreate(ctx, definition); err != nil {
+		t.Fatalf("TaskDefinitionCreate: Error[%v]", err)
+	}
+
+	// Running tasks claimed before leases were introduced
+	createLeaselessTask := func(startedAt time.Time) TaskQueueInterface {
+		t.Helper()
+		queuedTask := NewTaskQueue().
+			SetTaskID(definition.GetID()).
+			SetStatus(TaskQueueStatusRunning).
+			SetAttempts(1).
+			SetStartedAt(startedAt)
+		if err := store.TaskQueueCreate(ctx, queuedTask); err != nil {
+			t.Fatalf("TaskQueueCreate: Error[%v]", err)
+		}
+		return queuedTask
+	}
+
+	staleTask := createLeaselessTask(carbon.Now(carbon.UTC).StdTime().Add(-2 * DefaultLeaseDuration))
+	recentTask := createLeaselessTask(carbon.Now(carbon.UTC).StdTime().Add(-time.Minute))
+
+	reaped, err := store.TaskQueueReapExpiredLeases(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueReapExpiredLeases: Error[%v]", err)
+	}
+	if reaped != 1 {
+		t.Fatalf("Expected 1 reaped task, got %d", reaped)
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, staleTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusFailed {
+		t.Fatalf("Expected status %s, got %s", TaskQueueStatusFailed, dbTask.GetStatus())
+	}
+	if !strings.Contains(dbTask.GetDetails(), "Task running without a lease") {
+		t.Fatalf("Expected details to record the missing lease, got %s", dbTask.GetDetails())
+	}
+
+	dbTask, err = store.TaskQueueFindByID(ctx, recentTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusRunning {
+		t.Fatalf("Expected a recently started task to keep running, got %s", dbTask.GetStatus())
+	}
+}
+
+func Test_Store_QueuedTaskProcess_LeaseLostNotOverwritten(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("QueuedTaskProcess: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := &leaseStealingHandler{store: store}
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	queuedTask, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+	}
+
+	claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+
+	if _, err := store.QueuedTaskProcessWithContext(ctx, claimedTask); err != nil {
+		t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusRunning {
+		t.Fatalf("Expected the task to keep running under the other lease, got %s", dbTask.GetStatus())
+	}
+	if dbTask.GetLeaseOwner() != "OTHER_WORKER" {
+		t.Fatalf("Expected lease owner OTHER_WORKER, got %s", dbTask.GetLeaseOwner())
+	}
+}
+
+func Test_Store_QueuedTaskProcess_ReleasesLease(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("QueuedTaskProcess: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := newTestTaskHandler()
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	queuedTask, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{
+		"completeWithSuccess": "yes",
+	})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+	}
+
+	if _, err := store.QueuedTaskProcessWithContext(ctx, queuedTask); err != nil {
+		t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusSuccess {
+		t.Fatalf("Expected status %s, got %s", TaskQueueStatusSuccess, dbTask.GetStatus())
+	}
+	if dbTask.GetLeaseOwner() != "" {
+		t.Fatalf("Expected the lease to be released, got %s", dbTask.GetLeaseOwner())
+	}
+	if !isNullTime(dbTask.GetLeaseExpiresAt()) {
+		t.Fatalf("Expected the lease expiry to be cleared, got %s", dbTask.GetLeaseExpiresAt())
+	}
+}
@@ -0,0 +1,159 @@
+package taskstore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// panickingHandler panics while it handles a task
+type panickingHandler struct {
+	TaskDefinitionHandlerBase
+}
+
+func (h *panickingHandler) Alias() string {
+	return "PanickingHandler"
+}
+
+func (h *panickingHandler) Title() string {
+	return "Panicking Handler"
+}
+
+func (h *panickingHandler) Description() string {
+	return "Panics while handling a task"
+}
+
+func (h *panickingHandler) Handle() bool {
+	panic("handler exploded")
+}
+
+func Test_Store_QueuedTaskProcess_RecoversPanic(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("QueuedTaskProcess: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var handledErrors []error
+	store.SetErrorHandler(func(queueName, taskID string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		handledErrors = append(handledErrors, err)
+	})
+
+	handler := &panickingHandler{}
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	// A panic is not retried, even if the retry policy allows it
+	definition, err := store.TaskDefinitionFindByAlias(ctx, handler.Alias())
+	if err != nil {
+		t.Fatalf("TaskDefinitionFindByAlias: Error[%v]", err)
+	}
+	definition.SetRetryPolicy(&RetryPolicy{MaxAttempts: 3})
+	if err := store.TaskDefinitionUpdate(ctx, definition); err != nil {
+		t.Fatalf("TaskDefinitionUpdate: Error[%v]", err)
+	}
+
+	for _, timeoutSeconds := range []int{0, 10} {
+		queuedTask, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{})
+		if err != nil {
+			t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+		}
+		queuedTask.SetTimeoutSeconds(timeoutSeconds)
+
+		processed, err := store.QueuedTaskProcessWithContext(ctx, queuedTask)
+		if err != nil {
+			t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+		}
+		if !processed {
+			t.Fatal("Expected the task to be processed")
+		}
+
+		dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+		if err != nil {
+			t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+		}
+		if dbTask.GetStatus() != TaskQueueStatusFailed {
+			t.Fatalf("Expected status %s, got %s", TaskQueueStatusFailed, dbTask.GetStatus())
+		}
+		if !strings.Contains(dbTask.GetDetails(), "Task panicked: handler exploded") {
+			t.Fatalf("Expected the panic value in the details, got %s", dbTask.GetDetails())
+		}
+		if !strings.Contains(dbTask.GetDetails(), "panickingHandler") {
+			t.Fatalf("Expected the stack trace in the details, got %s", dbTask.GetDetails())
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handledErrors) != 2 {
+		t.Fatalf("Expected the error handler to be called twice, got %d", len(handledErrors))
+	}
+	for _, err := range handledErrors {
+		if !errors.Is(err, ErrTaskQueuePanicked) {
+			t.Fatalf("Expected ErrTaskQueuePanicked, got %v", err)
+		}
+	}
+}
+
+func TestTaskQueueRunner_SurvivesPanic(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("initStore: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+	store.SetErrorHandler(func(queueName, taskID string, err error) {})
+
+	if err := store.TaskHandlerAdd(ctx, &panickingHandler{}, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+	if err := store.TaskHandlerAdd(ctx, newTestTaskHandler(), true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	panicking, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, "PanickingHandler", map[string]any{})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+	}
+	succeeding, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, newTestTaskHandler().Alias(), map[string]any{
+		"completeWithSuccess": "yes",
+	})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+	}
+
+	runner := NewTaskQueueRunner(store, TaskQueueRunnerOptions{
+		QueueName:      DefaultQueueName,
+		MaxConcurrency: 2,
+	})
+	for range 2 {
+		if err := runner.RunOnce(ctx); err != nil {
+			t.Fatalf("RunOnce: Error[%v]", err)
+		}
+	}
+
+	for _, expected := range []struct {
+		id     string
+		status string
+	}{
+		{panicking.GetID(), TaskQueueStatusFailed},
+		{succeeding.GetID(), TaskQueueStatusSuccess},
+	} {
+		dbTask, err := store.TaskQueueFindByID(ctx, expected.id)
+		if err != nil {
+			t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+		}
+		if dbTask.GetStatus() != expected.status {
+			t.Fatalf("Expected status %s, got %s", expected.status, dbTask.GetStatus())
+		}
+	}
+}
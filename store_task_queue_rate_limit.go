@@ -0,0 +1,144 @@
+package taskstore
+
+import (
+	"context"
+	"time"
+
+	contractsorm "github.com/dracory/neat/contracts/database/orm"
+	neatuid "github.com/dracory/neat/support/uid"
+	"github.com/dromara/carbon/v2"
+)
+
+// taskQueueRateLimitRemaining checks the rate limits of the queue (read and
+// locked by the claim) and of the candidate task definitions (those with
+// tasks which may be claimed) within the claim transaction tx, counting the
+// claims recorded within their sliding windows (see taskQueueClaimsRecord).
+// On databases other than SQLite, the rows holding the limits are locked
+// until the claim commits, so concurrent claimers in other processes wait
+// instead of exceeding the limits.
+//
+// Returns how many more tasks may be claimed from the queue (-1 if it is
+// not rate limited), and how many more tasks may be claimed per rate
+// limited task definition, by task definition ID.
+func (store *Store) taskQueueRateLimitRemaining(tx contractsorm.Query, queueName string, queueRateLimit *RateLimit, candidateTaskIDs []any, now time.Time) (int, map[string]int, error) {
+	queueRemaining := -1
+	if queueRateLimit != nil && queueRateLimit.IsEnabled() {
+		var claimed int64
+		err := txTable(tx, store.claimTableName).
+			Where(COLUMN_QUEUE_NAME+" = ?", queueName).
+			Where(COLUMN_CLAIMED_AT+" >= ?", now.Add(-queueRateLimit.Period()).Format("2006-01-02 15:04:05")).
+			Count(&claimed)
+		if err != nil {
+			return 0, nil, err
+		}
+		queueRemaining = max(queueRateLimit.Limit-int(claimed), 0)
+	}
+
+	taskRemaining := map[string]int{}
+	if len(candidateTaskIDs) == 0 {
+		return queueRemaining, taskRemaining, nil
+	}
+
+	q := txTable(tx, store.taskDefinitionTableName).
+		WhereIn(COLUMN_ID, candidateTaskIDs).
+		Where(COLUMN_RATE_LIMIT+" <> ?", "")
+	if !store.isSQLite {
+		q = q.LockForUpdate()
+	}
+
+	var definitions []taskDefinition
+	if err := q.Find(&definitions); err != nil {
+		return 0, nil, err
+	}
+
+	for _, definition := range definitions {
+		rateLimit := definition.GetRateLimit()
+		if rateLimit == nil || !rateLimit.IsEnabled() {
+			continue
+		}
+
+		var claimed int64
+		err := txTable(tx, store.claimTableName).
+			Where(COLUMN_TASK_ID+" = ?", definition.GetID()).
+			Where(COLUMN_CLAIMED_AT+" >= ?", now.Add(-rateLimit.Period()).Format("2006-01-02 15:04:05")).
+			Count(&claimed)
+		if err != nil {
+			return 0, nil, err
+		}
+		taskRemaining[definition.GetID()] = max(rateLimit.Limit-int(claimed), 0)
+	}
+
+	return queueRemaining, taskRemaining, nil
+}
+
+// taskQueueClaimsRecord records the claims of the tasks within the claim
+// transaction tx. Unlike the claim time of a task, which the next attempt
+// overwrites, claims are only added, so every attempt counts towards the
+// rate limits.
+func (store *Store) taskQueueClaimsRecord(tx contractsorm.Query, tasks []taskQueue, now time.Time) error {
+	for _, task := range tasks {
+		err := txTable(tx, store.claimTableName).Create(map[string]any{
+			COLUMN_ID:            neatuid.GenerateShortID(),
+			COLUMN_QUEUE_NAME:    task.GetQueueName(),
+			COLUMN_TASK_ID:       task.GetTaskID(),
+			COLUMN_TASK_QUEUE_ID: task.GetID(),
+			COLUMN_CLAIMED_AT:    now.Format("2006-01-02 15:04:05"),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// taskQueueClaimsPrune removes the claims older than the longest rate limit
+// window of all queues and task definitions, as they no longer count
+// towards any limit. All claims are removed when nothing is rate limited.
+func (store *Store) taskQueueClaimsPrune(ctx context.Context) error {
+	var states []queueState
+	err := store.db.Query().
+		Table(store.queueTableName).
+		Where(COLUMN_RATE_LIMIT+" <> ?", "").
+		Find(&states)
+	if err != nil {
+		return err
+	}
+
+	var definitions []taskDefinition
+	err = store.db.Query().
+		Table(store.taskDefinitionTableName).
+		Where(COLUMN_RATE_LIMIT+" <> ?", "").
+		Find(&definitions)
+	if err != nil {
+		return err
+	}
+
+	rateLimits := []*RateLimit{}
+	for _, state := range states {
+		rateLimits = append(rateLimits, rateLimitFromJSON(state.RateLimitField))
+	}
+	for _, definition := range definitions {
+		rateLimits = append(rateLimits, definition.GetRateLimit())
+	}
+
+	var period time.Duration
+	for _, rateLimit := range rateLimits {
+		if rateLimit != nil && rateLimit.IsEnabled() {
+			period = max(period, rateLimit.Period())
+		}
+	}
+
+	_, err = store.db.Query().
+		Table(store.claimTableName).
+		Where(COLUMN_CLAIMED_AT+" < ?", carbon.Now(carbon.UTC).StdTime().Add(-period).Format("2006-01-02 15:04:05")).
+		Delete()
+	return err
+}
+
+// txTable starts a query on the table within the transaction tx. The query
+// builder of a transaction keeps its conditions between queries, so these
+// are cleared first.
+func txTable(tx contractsorm.Query, table string) contractsorm.Query {
+	return tx.Model(nil).Table(table)
+}
@@ -0,0 +1,258 @@
+package taskstore
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_Store_QueueSetRateLimit(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("QueueSetRateLimit: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	rateLimit, err := store.QueueGetRateLimit(ctx, "emails")
+	if err != nil {
+		t.Fatalf("QueueGetRateLimit: Error[%v]", err)
+	}
+	if rateLimit != nil {
+		t.Fatalf("Expected no rate limit by default, got %v", rateLimit)
+	}
+
+	if err := store.QueueSetRateLimit(ctx, "emails", &RateLimit{Limit: 60, PeriodSeconds: 60}); err != nil {
+		t.Fatalf("QueueSetRateLimit: Error[%v]", err)
+	}
+
+	rateLimit, err = store.QueueGetRateLimit(ctx, "emails")
+	if err != nil {
+		t.Fatalf("QueueGetRateLimit: Error[%v]", err)
+	}
+	if rateLimit == nil || rateLimit.Limit != 60 || rateLimit.PeriodSeconds != 60 {
+		t.Fatalf("Expected 60 per 60 seconds, got %v", rateLimit)
+	}
+
+	// Setting a rate limit does not pause the queue, and pausing keeps the limit
+	paused, err := store.QueueIsPaused(ctx, "emails")
+	if err != nil {
+		t.Fatalf("QueueIsPaused: Error[%v]", err)
+	}
+	if paused {
+		t.Fatal("Expected the queue to stay active")
+	}
+	if err := store.QueuePause(ctx, "emails"); err != nil {
+		t.Fatalf("QueuePause: Error[%v]", err)
+	}
+	rateLimit, err = store.QueueGetRateLimit(ctx, "emails")
+	if err != nil {
+		t.Fatalf("QueueGetRateLimit: Error[%v]", err)
+	}
+	if rateLimit == nil {
+		t.Fatal("Expected the rate limit to be kept when pausing")
+	}
+
+	if err := store.QueueSetRateLimit(ctx, "emails", nil); err != nil {
+		t.Fatalf("QueueSetRateLimit: Error[%v]", err)
+	}
+	rateLimit, err = store.QueueGetRateLimit(ctx, "emails")
+	if err != nil {
+		t.Fatalf("QueueGetRateLimit: Error[%v]", err)
+	}
+	if rateLimit != nil {
+		t.Fatalf("Expected the rate limit to be removed, got %v", rateLimit)
+	}
+}
+
+func Test_Store_TaskQueueClaimNext_QueueRateLimit(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	if err := store.QueueSetRateLimit(ctx, DefaultQueueName, &RateLimit{Limit: 2, PeriodSeconds: 60}); err != nil {
+		t.Fatalf("QueueSetRateLimit: Error[%v]", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := store.TaskQueueCreate(ctx, NewTaskQueue().SetTaskID("TASK_LIMITED")); err != nil {
+			t.Fatalf("TaskQueueCreate: Error[%v]", err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+		if err != nil {
+			t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+		}
+		if claimedTask == nil {
+			t.Fatalf("Expected task %d to be claimed within the rate limit", i+1)
+		}
+	}
+
+	claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimedTask != nil {
+		t.Fatal("Expected no task to be claimed over the rate limit")
+	}
+
+	queued, err := store.TaskQueueCount(ctx, TaskQueueQuery().SetStatus(TaskQueueStatusQueued))
+	if err != nil {
+		t.Fatalf("TaskQueueCount: Error[%v]", err)
+	}
+	if queued != 1 {
+		t.Fatalf("Expected the task over the rate limit to stay queued, got %d queued", queued)
+	}
+
+	// Other queues are not limited
+	if err := store.TaskQueueCreate(ctx, NewTaskQueue().SetTaskID("TASK_OTHER").SetQueueName("other")); err != nil {
+		t.Fatalf("TaskQueueCreate: Error[%v]", err)
+	}
+	claimedTask, err = store.TaskQueueClaimNext(ctx, "other")
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimedTask == nil {
+		t.Fatal("Expected a task of another queue to be claimed")
+	}
+}
+
+func Test_Store_TaskQueueClaimNext_DefinitionRateLimit(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	limited := NewTaskDefinition().
+		SetAlias("CallQuotaAPI").
+		SetTitle("Call Quota API").
+		SetRateLimit(&RateLimit{Limit: 1, PeriodSeconds: 60})
+	if err := store.TaskDefinitionCreate(ctx, limited); err != nil {
+		t.Fatalf("TaskDefinitionCreate: Error[%v]", err)
+	}
+
+	unlimited := NewTaskDefinition().
+		SetAlias("SendEmail").
+		SetTitle("Send Email")
+	if err := store.TaskDefinitionCreate(ctx, unlimited); err != nil {
+		t.Fatalf("TaskDefinitionCreate: Error[%v]", err)
+	}
+
+	// The limited tasks are oldest, so would be claimed first
+	for _, taskID := range []string{limited.GetID(), limited.GetID(), unlimited.GetID()} {
+		if err := store.TaskQueueCreate(ctx, NewTaskQueue().SetTaskID(taskID)); err != nil {
+			t.Fatalf("TaskQueueCreate: Error[%v]", err)
+		}
+	}
+
+	expected := []string{limited.GetID(), unlimited.GetID()}
+	for _, taskID := range expected {
+		claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+		if err != nil {
+			t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+		}
+		if claimedTask == nil {
+			t.Fatalf("Expected a task of %s to be claimed", taskID)
+		}
+		if claimedTask.GetTaskID() != taskID {
+			t.Fatalf("Expected a task of %s to be claimed, got %s", taskID, claimedTask.GetTaskID())
+		}
+	}
+
+	claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimedTask != nil {
+		t.Fatal("Expected the second limited task to stay queued")
+	}
+}
+
+func Test_Store_TaskQueueClaimNext_RateLimitCountsRetries(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	limited := NewTaskDefinition().
+		SetAlias("CallQuotaAPI").
+		SetTitle("Call Quota API").
+		SetRateLimit(&RateLimit{Limit: 2, PeriodSeconds: 60})
+	if err := store.TaskDefinitionCreate(ctx, limited); err != nil {
+		t.Fatalf("TaskDefinitionCreate: Error[%v]", err)
+	}
+
+	if err := store.TaskQueueCreate(ctx, NewTaskQueue().SetTaskID(limited.GetID())); err != nil {
+		t.Fatalf("TaskQueueCreate: Error[%v]", err)
+	}
+
+	// Each attempt of the same task counts towards the limit, although
+	// the claim time of the task is overwritten by the next attempt
+	for i := 0; i < 2; i++ {
+		claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+		if err != nil {
+			t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+		}
+		if claimedTask == nil {
+			t.Fatalf("Expected attempt %d to be claimed within the rate limit", i+1)
+		}
+
+		claimedTask.SetStatus(TaskQueueStatusQueued)
+		if err := store.TaskQueueUpdate(ctx, claimedTask); err != nil {
+			t.Fatalf("TaskQueueUpdate: Error[%v]", err)
+		}
+	}
+
+	claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimedTask != nil {
+		t.Fatal("Expected the third attempt to stay queued over the rate limit")
+	}
+
+	// Claims within the window are kept by the reaper, older ones are pruned
+	if _, err := store.TaskQueueReapExpiredLeases(ctx, ""); err != nil {
+		t.Fatalf("TaskQueueReapExpiredLeases: Error[%v]", err)
+	}
+	if claims := countClaims(t, store); claims != 2 {
+		t.Fatalf("Expected 2 claims within the window, got %d", claims)
+	}
+
+	limited.SetRateLimit(nil)
+	if err := store.TaskDefinitionUpdate(ctx, limited); err != nil {
+		t.Fatalf("TaskDefinitionUpdate: Error[%v]", err)
+	}
+	_, err = store.GetDB().Exec("UPDATE "+store.GetClaimTableName()+" SET "+COLUMN_CLAIMED_AT+" = ?", "2020-01-01 00:00:00")
+	if err != nil {
+		t.Fatalf("Exec: Error[%v]", err)
+	}
+	if _, err := store.TaskQueueReapExpiredLeases(ctx, ""); err != nil {
+		t.Fatalf("TaskQueueReapExpiredLeases: Error[%v]", err)
+	}
+	if claims := countClaims(t, store); claims != 0 {
+		t.Fatalf("Expected the old claims to be pruned, got %d", claims)
+	}
+}
+
+func countClaims(t *testing.T, store *Store) int {
+	t.Helper()
+	var claims int
+	err := store.GetDB().QueryRow("SELECT COUNT(*) FROM " + store.GetClaimTableName()).Scan(&claims)
+	if err != nil {
+		t.Fatalf("QueryRow: Error[%v]", err)
+	}
+	return claims
+}
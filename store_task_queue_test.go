@@ -3,6 +3,7 @@ package taskstore
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -517,3 +518,505 @@ func Test_Store_TaskQueueClaimNext(t *testing.T) {
 		t.Errorf("TaskQueueClaimNext: Database status expected %s, got %s", TaskQueueStatusRunning, dbTask.GetStatus())
 	}
 }
+
+func Test_Store_TaskQueueClaimNext_SkipsNotYetAvailable(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+	queueName := "test-queue"
+
+	task := NewTaskQueue().
+		SetTaskID("TASK_LATER").
+		SetQueueName(queueName).
+		SetAvailableAt(time.Now().UTC().Add(time.Hour))
+
+	if err := store.TaskQueueCreate(ctx, task); err != nil {
+		t.Fatalf("TaskQueueCreate: Error[%v]", err)
+	}
+
+	claimedTask, err := store.TaskQueueClaimNext(ctx, queueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error claiming task: [%v]", err)
+	}
+	if claimedTask != nil {
+		t.Fatalf("TaskQueueClaimNext: Expected no task to be claimed, got %s", claimedTask.GetID())
+	}
+
+	nextTask, err := store.TaskQueueFindNextQueuedTaskByQueue(ctx, queueName)
+	if err != nil {
+		t.Fatalf("TaskQueueFindNextQueuedTaskByQueue: Error[%v]", err)
+	}
+	if nextTask != nil {
+		t.Fatalf("TaskQueueFindNextQueuedTaskByQueue: Expected no task, got %s", nextTask.GetID())
+	}
+}
+
+func Test_Store_QueuedTaskProcess_Retry(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("QueuedTaskProcess: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := newTestTaskHandler()
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	queuedTask, err := store.TaskDefinitionEnqueueByAliasWithOptions(ctx, DefaultQueueName, handler.Alias(), map[string]any{
+		"completeWithFail": "yes",
+	}, EnqueueOptions{
+		RetryPolicy: &RetryPolicy{MaxAttempts: 2, DelaySeconds: 60},
+	})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAliasWithOptions: Error[%v]", err)
+	}
+
+	// First attempt fails and is scheduled for a retry
+	if _, err := store.QueuedTaskProcessWithContext(ctx, queuedTask); err != nil {
+		t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusQueued {
+		t.Fatalf("Expected status %s after first failure, got %s", TaskQueueStatusQueued, dbTask.GetStatus())
+	}
+	if !dbTask.GetAvailableAt().After(time.Now().UTC()) {
+		t.Fatalf("Expected available at in the future, got %v", dbTask.GetAvailableAt())
+	}
+	if !strings.Contains(dbTask.GetDetails(), "Retry 1 of 1") {
+		t.Fatalf("Expected details to mention the retry, got %s", dbTask.GetDetails())
+	}
+
+	// The retry is not claimed before it is available
+	claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimedTask != nil {
+		t.Fatal("TaskQueueClaimNext: Expected the retry not to be claimed yet")
+	}
+
+	// Second attempt fails and exhausts the retry policy
+	if _, err := store.QueuedTaskProcessWithContext(ctx, dbTask); err != nil {
+		t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+	}
+
+	dbTask, err = store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusFailed {
+		t.Fatalf("Expected status %s after last attempt, got %s", TaskQueueStatusFailed, dbTask.GetStatus())
+	}
+	if dbTask.GetAttempts() != 2 {
+		t.Fatalf("Expected 2 attempts, got %d", dbTask.GetAttempts())
+	}
+}
+
+func Test_Store_QueuedTaskProcess_RetryPolicyFromDefinition(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("QueuedTaskProcess: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := newTestTaskHandler()
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	definition, err := store.TaskDefinitionFindByAlias(ctx, handler.Alias())
+	if err != nil {
+		t.Fatalf("TaskDefinitionFindByAlias: Error[%v]", err)
+	}
+	definition.SetRetryPolicy(&RetryPolicy{MaxAttempts: 3})
+	if err := store.TaskDefinitionUpdate(ctx, definition); err != nil {
+		t.Fatalf("TaskDefinitionUpdate: Error[%v]", err)
+	}
+
+	queuedTask, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{
+		"completeWithFail": "yes",
+	})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+	}
+
+	if _, err := store.QueuedTaskProcessWithContext(ctx, queuedTask); err != nil {
+		t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+	}
+
+	// Without a delay the retry is available immediately
+	claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimedTask == nil || claimedTask.GetID() != queuedTask.GetID() {
+		t.Fatal("TaskQueueClaimNext: Expected the retry to be claimed")
+	}
+}
+
+func Test_Store_QueuedTaskProcess_FailPermanently(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("QueuedTaskProcess: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := newTestTaskHandler()
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	queuedTask, err := store.TaskDefinitionEnqueueByAliasWithOptions(ctx, DefaultQueueName, handler.Alias(), map[string]any{
+		"failPermanently": "yes",
+	}, EnqueueOptions{
+		RetryPolicy: &RetryPolicy{MaxAttempts: 5},
+	})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAliasWithOptions: Error[%v]", err)
+	}
+
+	if _, err := store.QueuedTaskProcessWithContext(ctx, queuedTask); err != nil {
+		t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusFailed {
+		t.Fatalf("Expected status %s, got %s", TaskQueueStatusFailed, dbTask.GetStatus())
+	}
+	if !strings.Contains(dbTask.GetDetails(), "Task forced to fail permanently.") {
+		t.Fatalf("Expected details to contain the failure message, got %s", dbTask.GetDetails())
+	}
+}
+
+func Test_Store_TaskQueueClaimNext_Priority(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+	queueName := "test-queue"
+	now := time.Now().UTC()
+
+	lowOld := NewTaskQueue(queueName).
+		SetTaskID("TASK_LOW_OLD").
+		SetPriority(0).
+		SetCreatedAt(now.Add(-3 * time.Minute))
+	highNew := NewTaskQueue(queueName).
+		SetTaskID("TASK_HIGH_NEW").
+		SetPriority(10).
+		SetCreatedAt(now.Add(-1 * time.Minute))
+	highOld := NewTaskQueue(queueName).
+		SetTaskID("TASK_HIGH_OLD").
+		SetPriority(10).
+		SetCreatedAt(now.Add(-2 * time.Minute))
+
+	for _, task := range []TaskQueueInterface{lowOld, highNew, highOld} {
+		if err := store.TaskQueueCreate(ctx, task); err != nil {
+			t.Fatalf("TaskQueueCreate: Error[%v]", err)
+		}
+	}
+
+	next, err := store.TaskQueueFindNextQueuedTaskByQueue(ctx, queueName)
+	if err != nil {
+		t.Fatalf("TaskQueueFindNextQueuedTaskByQueue: Error[%v]", err)
+	}
+	if next == nil || next.GetTaskID() != "TASK_HIGH_OLD" {
+		t.Fatalf("TaskQueueFindNextQueuedTaskByQueue: Expected TASK_HIGH_OLD, got %v", next)
+	}
+
+	for _, expectedTaskID := range []string{"TASK_HIGH_OLD", "TASK_HIGH_NEW", "TASK_LOW_OLD"} {
+		claimedTask, err := store.TaskQueueClaimNext(ctx, queueName)
+		if err != nil {
+			t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+		}
+		if claimedTask == nil {
+			t.Fatalf("TaskQueueClaimNext: Expected %s, got nil", expectedTaskID)
+		}
+		if claimedTask.GetTaskID() != expectedTaskID {
+			t.Fatalf("TaskQueueClaimNext: Expected %s, got %s", expectedTaskID, claimedTask.GetTaskID())
+		}
+	}
+}
+
+func Test_Store_TaskQueueClaimNext_SkipsBlockedDependents(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := newTestTaskHandler()
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	parent, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+	}
+	priority := 100
+	child, err := store.TaskDefinitionEnqueueByAliasWithOptions(ctx, DefaultQueueName, handler.Alias(), map[string]any{}, EnqueueOptions{
+		DependsOn: []string{parent.GetID()},
+		Priority:  &priority,
+	})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAliasWithOptions: Error[%v]", err)
+	}
+
+	claimedTask, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimedTask == nil || claimedTask.GetID() != parent.GetID() {
+		t.Fatalf("TaskQueueClaimNext: Expected the parent task, got %v", claimedTask)
+	}
+
+	claimedTask, err = store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimedTask != nil {
+		t.Fatalf("TaskQueueClaimNext: Expected no task while the dependency runs, got %s", claimedTask.GetID())
+	}
+
+	if err := store.TaskQueueSuccess(ctx, parent); err != nil {
+		t.Fatalf("TaskQueueSuccess: Error[%v]", err)
+	}
+
+	claimedTask, err = store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimedTask == nil || claimedTask.GetID() != child.GetID() {
+		t.Fatalf("TaskQueueClaimNext: Expected the dependent task, got %v", claimedTask)
+	}
+}
+
+func Test_Store_TaskQueueClaimBatch(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueClaimBatch: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := ContextWithWorkerID(context.Background(), "worker-1")
+	queueName := "test-queue"
+	now := time.Now().UTC()
+
+	for i, priority := range []int{0, 10, 5, 0, 0} {
+		task := NewTaskQueue(queueName).
+			SetTaskID(fmt.Sprintf("TASK_%d", i)).
+			SetPriority(priority).
+			SetCreatedAt(now.Add(time.Duration(i-10) * time.Minute))
+		if err := store.TaskQueueCreate(ctx, task); err != nil {
+			t.Fatalf("TaskQueueCreate: Error[%v]", err)
+		}
+	}
+
+	claimedTasks, err := store.TaskQueueClaimBatch(ctx, queueName, 3)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimBatch: Error[%v]", err)
+	}
+	claimedTaskIDs := []string{}
+	for _, claimedTask := range claimedTasks {
+		claimedTaskIDs = append(claimedTaskIDs, claimedTask.GetTaskID())
+		if claimedTask.GetStatus() != TaskQueueStatusRunning {
+			t.Errorf("TaskQueueClaimBatch: Expected status %s, got %s", TaskQueueStatusRunning, claimedTask.GetStatus())
+		}
+		if claimedTask.GetWorkerID() != "worker-1" {
+			t.Errorf("TaskQueueClaimBatch: Expected worker-1, got %s", claimedTask.GetWorkerID())
+		}
+	}
+	if strings.Join(claimedTaskIDs, ",") != "TASK_1,TASK_2,TASK_0" {
+		t.Fatalf("TaskQueueClaimBatch: Expected TASK_1,TASK_2,TASK_0, got %v", claimedTaskIDs)
+	}
+
+	running, err := store.TaskQueueCount(ctx, TaskQueueQuery().
+		SetQueueName(queueName).
+		SetStatus(TaskQueueStatusRunning).
+		SetWorkerID("worker-1"))
+	if err != nil {
+		t.Fatalf("TaskQueueCount: Error[%v]", err)
+	}
+	if running != 3 {
+		t.Fatalf("TaskQueueClaimBatch: Expected 3 running tasks in the database, got %d", running)
+	}
+
+	// Fewer tasks are left than asked for
+	claimedTasks, err = store.TaskQueueClaimBatch(ctx, queueName, 10)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimBatch: Error[%v]", err)
+	}
+	if len(claimedTasks) != 2 {
+		t.Fatalf("TaskQueueClaimBatch: Expected the 2 remaining tasks, got %d", len(claimedTasks))
+	}
+
+	claimedTasks, err = store.TaskQueueClaimBatch(ctx, queueName, 10)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimBatch: Error[%v]", err)
+	}
+	if len(claimedTasks) != 0 {
+		t.Fatalf("TaskQueueClaimBatch: Expected no tasks left, got %d", len(claimedTasks))
+	}
+}
+
+func Test_Store_TaskQueueClaimBatch_Limits(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskQueueClaimBatch: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	limited := NewTaskDefinition().
+		SetAlias("GenerateInvoice").
+		SetTitle("Generate Invoice").
+		SetMaxConcurrency(2)
+	if err := store.TaskDefinitionCreate(ctx, limited); err != nil {
+		t.Fatalf("TaskDefinitionCreate: Error[%v]", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		if err := store.TaskQueueCreate(ctx, NewTaskQueue().SetTaskID(limited.GetID())); err != nil {
+			t.Fatalf("TaskQueueCreate: Error[%v]", err)
+		}
+	}
+	if err := store.TaskQueueCreate(ctx, NewTaskQueue().SetTaskID("TASK_UNLIMITED")); err != nil {
+		t.Fatalf("TaskQueueCreate: Error[%v]", err)
+	}
+
+	// Only 2 tasks of the limited definition fit in the batch
+	claimedTasks, err := store.TaskQueueClaimBatch(ctx, DefaultQueueName, 4)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimBatch: Error[%v]", err)
+	}
+	if len(claimedTasks) != 2 {
+		t.Fatalf("TaskQueueClaimBatch: Expected 2 tasks within the concurrency limit, got %d", len(claimedTasks))
+	}
+
+	// The limited definition is skipped, other tasks are claimed
+	claimedTasks, err = store.TaskQueueClaimBatch(ctx, DefaultQueueName, 4)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimBatch: Error[%v]", err)
+	}
+	if len(claimedTasks) != 1 || claimedTasks[0].GetTaskID() != "TASK_UNLIMITED" {
+		t.Fatalf("TaskQueueClaimBatch: Expected only TASK_UNLIMITED, got %v", claimedTasks)
+	}
+
+	// The queue rate limit caps the batch
+	if err := store.QueueSetRateLimit(ctx, "emails", &RateLimit{Limit: 2, PeriodSeconds: 60}); err != nil {
+		t.Fatalf("QueueSetRateLimit: Error[%v]", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := store.TaskQueueCreate(ctx, NewTaskQueue("emails").SetTaskID("TASK_EMAIL")); err != nil {
+			t.Fatalf("TaskQueueCreate: Error[%v]", err)
+		}
+	}
+	claimedTasks, err = store.TaskQueueClaimBatch(ctx, "emails", 10)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimBatch: Error[%v]", err)
+	}
+	if len(claimedTasks) != 2 {
+		t.Fatalf("TaskQueueClaimBatch: Expected 2 tasks within the rate limit, got %d", len(claimedTasks))
+	}
+}
+
+func Test_Store_QueuedTaskProcess_MissingDefinition(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("QueuedTaskProcess: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := newTestTaskHandler()
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	batch := NewBatch()
+	queuedTasks, err := store.BatchEnqueue(ctx, batch, []BatchTask{{Alias: handler.Alias(), Parameters: map[string]any{}}})
+	if err != nil {
+		t.Fatalf("BatchEnqueue: Error[%v]", err)
+	}
+	parent := queuedTasks[0]
+
+	dependent, err := store.TaskDefinitionEnqueueByAliasWithOptions(ctx, DefaultQueueName, handler.Alias(), map[string]any{}, EnqueueOptions{
+		DependsOn: []string{parent.GetID()},
+	})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAliasWithOptions: Error[%v]", err)
+	}
+
+	// The task definition is deleted after its tasks were enqueued
+	definition, err := store.TaskDefinitionFindByAlias(ctx, handler.Alias())
+	if err != nil {
+		t.Fatalf("TaskDefinitionFindByAlias: Error[%v]", err)
+	}
+	if err := store.TaskDefinitionDelete(ctx, definition); err != nil {
+		t.Fatalf("TaskDefinitionDelete: Error[%v]", err)
+	}
+
+	claimed, err := store.TaskQueueClaimNext(ctx, DefaultQueueName)
+	if err != nil {
+		t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+	}
+	if claimed == nil || claimed.GetID() != parent.GetID() {
+		t.Fatalf("Expected to claim the parent task, got %v", claimed)
+	}
+	if _, err := store.QueuedTaskProcessWithContext(ctx, claimed); err != nil {
+		t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+	}
+
+	dbParent, err := store.TaskQueueFindByID(ctx, parent.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbParent.GetStatus() != TaskQueueStatusFailed {
+		t.Fatalf("Expected status %s, got %s", TaskQueueStatusFailed, dbParent.GetStatus())
+	}
+	if dbParent.GetLeaseOwner() != "" {
+		t.Fatalf("Expected the lease to be released, got %s", dbParent.GetLeaseOwner())
+	}
+
+	// Its dependents and its batch are resolved
+	dbDependent, err := store.TaskQueueFindByID(ctx, dependent.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbDependent.GetStatus() != TaskQueueStatusCanceled {
+		t.Fatalf("Expected the dependent to be canceled, got %s", dbDependent.GetStatus())
+	}
+
+	dbBatch, err := store.BatchFindByID(ctx, batch.GetID())
+	if err != nil {
+		t.Fatalf("BatchFindByID: Error[%v]", err)
+	}
+	if dbBatch.GetStatus() != BatchStatusFailed {
+		t.Fatalf("Expected the batch to fail, got %s", dbBatch.GetStatus())
+	}
+}
@@ -0,0 +1,258 @@
+package taskstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowHandler does not support a context. It blocks until released,
+// then returns true, to check a late return does not overwrite a timeout
+type slowHandler struct {
+	TaskDefinitionHandlerBase
+	release chan struct{}
+}
+
+func (h *slowHandler) Alias() string {
+	return "SlowHandler"
+}
+
+func (h *slowHandler) Title() string {
+	return "Slow Handler"
+}
+
+func (h *slowHandler) Description() string {
+	return "Blocks until released"
+}
+
+func (h *slowHandler) Handle() bool {
+	<-h.release
+	return true
+}
+
+func Test_Store_QueuedTaskProcess_TimeoutFromDefinition(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("QueuedTaskProcess: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := &blockingContextHandler{started: make(chan struct{})}
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	definition, err := store.TaskDefinitionFindByAlias(ctx, handler.Alias())
+	if err != nil {
+		t.Fatalf("TaskDefinitionFindByAlias: Error[%v]", err)
+	}
+	definition.SetTimeoutSeconds(1)
+	if err := store.TaskDefinitionUpdate(ctx, definition); err != nil {
+		t.Fatalf("TaskDefinitionUpdate: Error[%v]", err)
+	}
+
+	queuedTask, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+	}
+
+	if _, err := store.QueuedTaskProcessWithContext(ctx, queuedTask); err != nil {
+		t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+	}
+
+	// The handler returns shortly after its context timed out
+	deadline := time.Now().Add(time.Second)
+	for !handler.canceled.Load() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !handler.canceled.Load() {
+		t.Fatal("Expected the handler context to be canceled")
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusFailed {
+		t.Fatalf("Expected status %s, got %s", TaskQueueStatusFailed, dbTask.GetStatus())
+	}
+	if !strings.Contains(dbTask.GetDetails(), "Task timed out after 1s") {
+		t.Fatalf("Expected details to record the timeout, got %s", dbTask.GetDetails())
+	}
+	if strings.Contains(dbTask.GetDetails(), "Task completed") {
+		t.Fatalf("Expected the late success to be ignored, got %s", dbTask.GetDetails())
+	}
+}
+
+func Test_Store_QueuedTaskProcess_TimeoutLateReturnIgnored(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("QueuedTaskProcess: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := &slowHandler{release: make(chan struct{})}
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatalf("TaskHandlerAdd: Error[%v]", err)
+	}
+
+	queuedTask, err := store.TaskDefinitionEnqueueByAliasWithOptions(ctx, DefaultQueueName, handler.Alias(), map[string]any{}, EnqueueOptions{
+		Timeout: 500 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAliasWithOptions: Error[%v]", err)
+	}
+	if queuedTask.GetTimeoutSeconds() != 1 {
+		t.Fatalf("Expected timeout to be rounded up to 1 second, got %d", queuedTask.GetTimeoutSeconds())
+	}
+
+	if _, err := store.QueuedTaskProcessWithContext(ctx, queuedTask); err != nil {
+		t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+	}
+
+	// The handler returns success after the task timed out
+	close(handler.release)
+	time.Sleep(100 * time.Millisecond)
+
+	dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusFailed {
+		t.Fatalf("Expected status %s, got %s", TaskQueueStatusFailed, dbTask.GetStatus())
+	}
+	if !strings.Contains(dbTask.GetDetails(), "Task timed out after 1s") {
+		t.Fatalf("Expected details to record the timeout, got %s", dbTask.GetDetails())
+	}
+	if isNullTime(dbTask.GetCompletedAt()) {
+		t.Fatal("Expected completed at to be set")
+	}
+}
+
+func Test_Store_QueuedTaskProcess_TimeoutKeepsDetails(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("QueuedTaskProcess: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	// Each attempt blocks until released. The first one reports its
+	// progress once released, long after it timed out.
+	release := []chan struct{}{make(chan struct{}), make(chan struct{})}
+	progressed := make(chan struct{})
+	err = store.TaskHandlerFunc(ctx, "abandoned-handler", "Abandoned handler", func(ctx context.Context, task TaskContext) error {
+		attempt := task.GetQueuedTask().GetAttempts()
+		task.LogInfo(fmt.Sprintf("Attempt %d running", attempt))
+		<-release[attempt-1]
+		if attempt == 1 {
+			task.SetProgress(1, 1)
+			close(progressed)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("TaskHandlerFunc: Error[%v]", err)
+	}
+
+	queuedTask, err := store.TaskDefinitionEnqueueByAliasWithOptions(ctx, DefaultQueueName, "abandoned-handler", map[string]any{}, EnqueueOptions{
+		Timeout:     time.Second,
+		RetryPolicy: &RetryPolicy{MaxAttempts: 2},
+	})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAliasWithOptions: Error[%v]", err)
+	}
+
+	if _, err := store.QueuedTaskProcessWithContext(ctx, queuedTask); err != nil {
+		t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+	}
+
+	// The details logged before the timeout are kept
+	dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusQueued {
+		t.Fatalf("Expected status %s, got %s", TaskQueueStatusQueued, dbTask.GetStatus())
+	}
+	if !strings.Contains(dbTask.GetDetails(), "Attempt 1 running") || !strings.Contains(dbTask.GetDetails(), "Task timed out after 1s") {
+		t.Fatalf("Expected details to record the attempt and the timeout, got %s", dbTask.GetDetails())
+	}
+
+	// The same worker runs the second attempt
+	dbTask.SetAvailableAt(time.Time{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = store.QueuedTaskProcessWithContext(ctx, dbTask)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		secondTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+		if err != nil {
+			t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+		}
+		if secondTask.GetAttempts() == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the second attempt to start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The abandoned first attempt does not overwrite the details of the
+	// second one
+	close(release[0])
+	<-progressed
+
+	secondTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if strings.Contains(secondTask.GetDetails(), "Progress: 1/1") {
+		t.Fatalf("Expected the progress of the abandoned attempt to be ignored, got %s", secondTask.GetDetails())
+	}
+
+	close(release[1])
+	<-done
+}
+
+func Test_queuedTaskRunHandler(t *testing.T) {
+	handlerFunc := func(queuedTask TaskQueueInterface) (bool, error) {
+		return true, nil
+	}
+
+	result, returned, _ := queuedTaskRunHandler(context.Background(), 0, handlerFunc, NewTaskQueue())
+	if !result || !returned {
+		t.Fatalf("Expected the handler to return true, got %v %v", result, returned)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), time.Millisecond, ErrTaskQueueTimedOut)
+	defer cancel()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	blockingFunc := func(queuedTask TaskQueueInterface) (bool, error) {
+		<-release
+		return true, nil
+	}
+
+	result, returned, _ = queuedTaskRunHandler(ctx, time.Millisecond, blockingFunc, NewTaskQueue())
+	if result || returned {
+		t.Fatalf("Expected the handler to be abandoned, got %v %v", result, returned)
+	}
+	if !errors.Is(context.Cause(ctx), ErrTaskQueueTimedOut) {
+		t.Fatalf("Expected cause %v, got %v", ErrTaskQueueTimedOut, context.Cause(ctx))
+	}
+}
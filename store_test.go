@@ -240,3 +240,49 @@ func initStore(filename ...string) (*Store, error) {
 		DebugEnabled:       false,
 	})
 }
+
+func Test_Store_MigrateUp_AddsMissingColumns(t *testing.T) {
+	db, err := initDB()
+	if err != nil {
+		t.Fatalf("initDB: Error[%v]", err)
+	}
+	defer db.Close()
+
+	// Tables created before the newer columns were introduced
+	_, err = db.Exec(`CREATE TABLE task_definition (id TEXT PRIMARY KEY, status TEXT, alias TEXT, title TEXT, memo TEXT, description TEXT, is_recurring INTEGER, recurrence_rule TEXT, created_at DATETIME, updated_at DATETIME, soft_deleted_at DATETIME)`)
+	if err != nil {
+		t.Fatalf("create task_definition: Error[%v]", err)
+	}
+	_, err = db.Exec(`CREATE TABLE task_queue (id TEXT PRIMARY KEY, queue_name TEXT, task_id TEXT, parameters TEXT, status TEXT, output TEXT, details TEXT, attempts INTEGER, started_at DATETIME, completed_at DATETIME, created_at DATETIME, updated_at DATETIME, soft_deleted_at DATETIME)`)
+	if err != nil {
+		t.Fatalf("create task_queue: Error[%v]", err)
+	}
+
+	store, err := NewStore(NewStoreOptions{
+		TaskDefinitionTableName: "task_definition",
+		TaskQueueTableName:      "task_queue",
+		ScheduleTableName:       "schedules",
+		DB:                      db,
+		AutomigrateEnabled:      true,
+	})
+	if err != nil {
+		t.Fatalf("NewStore: Error[%v]", err)
+	}
+
+	for _, migration := range taskDefinitionColumnMigrations() {
+		if !store.db.Schema().HasColumn("task_definition", migration.column) {
+			t.Errorf("Expected task_definition to have column %s", migration.column)
+		}
+	}
+
+	for _, migration := range taskQueueColumnMigrations() {
+		if !store.db.Schema().HasColumn("task_queue", migration.column) {
+			t.Errorf("Expected task_queue to have column %s", migration.column)
+		}
+	}
+
+	// Running the migration again is a no-op
+	if err := store.MigrateUp(context.Background()); err != nil {
+		t.Fatalf("MigrateUp: Error[%v]", err)
+	}
+}
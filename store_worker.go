@@ -0,0 +1,205 @@
+package taskstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dromara/carbon/v2"
+)
+
+// WorkerRegister registers a worker in the workers table, as started now.
+// A worker registered before with the same ID is replaced.
+func (store *Store) WorkerRegister(ctx context.Context, worker WorkerInterface) error {
+	if worker == nil {
+		return errors.New("worker is nil")
+	}
+
+	now := carbon.Now(carbon.UTC).StdTime()
+	worker.SetStartedAt(now)
+	worker.SetHeartbeatAt(now)
+
+	return store.workerSave(ctx, worker)
+}
+
+// WorkerHeartbeat records that the worker is alive, along with its current
+// state (i.e. the number of tasks in flight). A worker which is not
+// registered (i.e. was deregistered meanwhile) is registered again.
+func (store *Store) WorkerHeartbeat(ctx context.Context, worker WorkerInterface) error {
+	if worker == nil {
+		return errors.New("worker is nil")
+	}
+
+	worker.SetHeartbeatAt(carbon.Now(carbon.UTC).StdTime())
+
+	return store.workerSave(ctx, worker)
+}
+
+// WorkerDeregister removes the worker from the workers table, i.e. when
+// it stops
+func (store *Store) WorkerDeregister(ctx context.Context, workerID string) error {
+	if workerID == "" {
+		return errors.New("worker id is empty")
+	}
+
+	_, err := store.db.Query().
+		Table(store.workerTableName).
+		Where(COLUMN_ID+" = ?", workerID).
+		Delete()
+	return err
+}
+
+// WorkerFindByID finds a registered worker by ID
+func (store *Store) WorkerFindByID(ctx context.Context, workerID string) (WorkerInterface, error) {
+	if workerID == "" {
+		return nil, errors.New("worker id is empty")
+	}
+
+	var found worker
+	err := store.db.Query().
+		Table(store.workerTableName).
+		Where(COLUMN_ID+" = ?", workerID).
+		First(&found)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) || err.Error() == "no rows found" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &found, nil
+}
+
+// WorkerIsAlive returns true if the worker heartbeated within the worker
+// timeout of the store (see NewStoreOptions.WorkerTimeout)
+func (store *Store) WorkerIsAlive(worker WorkerInterface) bool {
+	return worker.GetHeartbeatAt().After(time.Now().Add(-store.workerTimeout))
+}
+
+// WorkerListLive returns the registered workers which heartbeated within
+// the worker timeout of the store, ordered by queue name and ID
+func (store *Store) WorkerListLive(ctx context.Context) ([]WorkerInterface, error) {
+	return store.workerList(ctx, COLUMN_HEARTBEAT_AT+" >= ?")
+}
+
+// WorkerListDead returns the registered workers which did not heartbeat
+// within the worker timeout of the store, as they stopped without
+// deregistering (i.e. crashed or were killed). Ordered by queue name and ID.
+func (store *Store) WorkerListDead(ctx context.Context) ([]WorkerInterface, error) {
+	return store.workerList(ctx, COLUMN_HEARTBEAT_AT+" < ?")
+}
+
+// WorkerPruneDead removes the dead workers (see WorkerListDead) which did
+// not heartbeat for longer than the worker retention of the store (see
+// NewStoreOptions.WorkerRetention), past the worker timeout. The reaper
+// prunes them (see TaskQueueReapExpiredLeases).
+// Returns the number of pruned workers.
+func (store *Store) WorkerPruneDead(ctx context.Context) (int64, error) {
+	pruneBefore := carbon.Now(carbon.UTC).StdTime().Add(-store.workerTimeout - store.workerRetention)
+
+	result, err := store.db.Query().
+		Table(store.workerTableName).
+		Where(COLUMN_HEARTBEAT_AT+" < ?", pruneBefore.Format("2006-01-02 15:04:05")).
+		Delete()
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected, nil
+}
+
+// WorkerDeadTaskList returns the running tasks claimed by dead workers
+// (see WorkerListDead). These tasks are reaped once their lease expires
+// (see TaskQueueReapExpiredLeases), or can be recovered by hand.
+func (store *Store) WorkerDeadTaskList(ctx context.Context) ([]TaskQueueInterface, error) {
+	deadWorkers, err := store.WorkerListDead(ctx)
+	if err != nil {
+		return []TaskQueueInterface{}, err
+	}
+
+	tasks := []TaskQueueInterface{}
+	for _, deadWorker := range deadWorkers {
+		list, err := store.TaskQueueList(ctx, TaskQueueQuery().
+			SetWorkerID(deadWorker.GetID()).
+			SetStatus(TaskQueueStatusRunning))
+		if err != nil {
+			return []TaskQueueInterface{}, err
+		}
+		tasks = append(tasks, list...)
+	}
+
+	return tasks, nil
+}
+
+// workerList returns the workers whose heartbeat time matches the given
+// condition, compared to the start of the worker timeout
+func (store *Store) workerList(ctx context.Context, heartbeatCondition string) ([]WorkerInterface, error) {
+	timeoutStart := carbon.Now(carbon.UTC).StdTime().Add(-store.workerTimeout)
+
+	var workers []worker
+	err := store.db.Query().
+		Table(store.workerTableName).
+		Where(heartbeatCondition, timeoutStart.Format("2006-01-02 15:04:05")).
+		OrderBy(COLUMN_QUEUE_NAME, ASC).
+		OrderBy(COLUMN_ID, ASC).
+		Get(&workers)
+	if err != nil {
+		return []WorkerInterface{}, err
+	}
+
+	list := make([]WorkerInterface, len(workers))
+	for i := range workers {
+		list[i] = &workers[i]
+	}
+	return list, nil
+}
+
+// workerSave updates the row of the worker, creating it if it does not
+// exist yet
+func (store *Store) workerSave(ctx context.Context, worker WorkerInterface) error {
+	worker.SetUpdatedAt(carbon.Now(carbon.UTC).StdTime())
+
+	result, err := store.db.Query().
+		Table(store.workerTableName).
+		Where(COLUMN_ID+" = ?", worker.GetID()).
+		Update(workerToRow(worker))
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	// Nothing changed, either because the worker is not registered, or
+	// because the row already had these values (within the same second)
+	var count int64
+	err = store.db.Query().
+		Table(store.workerTableName).
+		Where(COLUMN_ID+" = ?", worker.GetID()).
+		Count(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+
+	if worker.GetCreatedAt().IsZero() {
+		worker.SetCreatedAt(carbon.Now(carbon.UTC).StdTime())
+	}
+
+	row := workerToRow(worker)
+	row[COLUMN_ID] = worker.GetID()
+	row[COLUMN_CREATED_AT] = worker.GetCreatedAt().Format("2006-01-02 15:04:05")
+
+	return store.db.Query().Table(store.workerTableName).Create(row)
+}
+
+func workerToRow(worker WorkerInterface) map[string]any {
+	return map[string]any{
+		COLUMN_QUEUE_NAME:      worker.GetQueueName(),
+		COLUMN_MAX_CONCURRENCY: worker.GetMaxConcurrency(),
+		COLUMN_IN_FLIGHT:       worker.GetInFlight(),
+		COLUMN_VERSION:         worker.GetVersion(),
+		COLUMN_STARTED_AT:      worker.GetStartedAt().Format("2006-01-02 15:04:05"),
+		COLUMN_HEARTBEAT_AT:    worker.GetHeartbeatAt().Format("2006-01-02 15:04:05"),
+		COLUMN_UPDATED_AT:      worker.GetUpdatedAt().Format("2006-01-02 15:04:05"),
+	}
+}
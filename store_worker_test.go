@@ -0,0 +1,204 @@
+package taskstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dromara/carbon/v2"
+)
+
+// markWorkerDead moves the last heartbeat of a worker past the worker timeout
+func markWorkerDead(t *testing.T, store *Store, workerID string) {
+	t.Helper()
+
+	heartbeatAt := carbon.Now(carbon.UTC).StdTime().Add(-2 * store.workerTimeout)
+	_, err := store.GetDB().Exec("UPDATE "+store.GetWorkerTableName()+" SET "+COLUMN_HEARTBEAT_AT+" = ? WHERE "+COLUMN_ID+" = ?", heartbeatAt.Format("2006-01-02 15:04:05"), workerID)
+	if err != nil {
+		t.Fatalf("markWorkerDead: Error[%v]", err)
+	}
+}
+
+func Test_Store_WorkerRegisterHeartbeatDeregister(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("WorkerRegister: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	worker := NewWorker("worker-1").
+		SetQueueName("emails").
+		SetMaxConcurrency(4).
+		SetVersion("v1.0.0")
+	if err := store.WorkerRegister(ctx, worker); err != nil {
+		t.Fatalf("WorkerRegister: Error[%v]", err)
+	}
+
+	// Registering again replaces the worker
+	if err := store.WorkerRegister(ctx, worker); err != nil {
+		t.Fatalf("WorkerRegister: Error[%v]", err)
+	}
+
+	worker.SetInFlight(2)
+	if err := store.WorkerHeartbeat(ctx, worker); err != nil {
+		t.Fatalf("WorkerHeartbeat: Error[%v]", err)
+	}
+
+	dbWorker, err := store.WorkerFindByID(ctx, "worker-1")
+	if err != nil {
+		t.Fatalf("WorkerFindByID: Error[%v]", err)
+	}
+	if dbWorker == nil {
+		t.Fatal("Expected the worker to be registered")
+	}
+	if dbWorker.GetQueueName() != "emails" || dbWorker.GetMaxConcurrency() != 4 || dbWorker.GetVersion() != "v1.0.0" {
+		t.Fatalf("Expected emails/4/v1.0.0, got %s/%d/%s", dbWorker.GetQueueName(), dbWorker.GetMaxConcurrency(), dbWorker.GetVersion())
+	}
+	if dbWorker.GetInFlight() != 2 {
+		t.Fatalf("Expected 2 tasks in flight, got %d", dbWorker.GetInFlight())
+	}
+	if !store.WorkerIsAlive(dbWorker) {
+		t.Fatal("Expected the worker to be alive")
+	}
+
+	if err := store.WorkerDeregister(ctx, "worker-1"); err != nil {
+		t.Fatalf("WorkerDeregister: Error[%v]", err)
+	}
+
+	dbWorker, err = store.WorkerFindByID(ctx, "worker-1")
+	if err != nil {
+		t.Fatalf("WorkerFindByID: Error[%v]", err)
+	}
+	if dbWorker != nil {
+		t.Fatal("Expected the worker to be deregistered")
+	}
+
+	// A heartbeat registers a deregistered worker again
+	if err := store.WorkerHeartbeat(ctx, worker); err != nil {
+		t.Fatalf("WorkerHeartbeat: Error[%v]", err)
+	}
+	dbWorker, err = store.WorkerFindByID(ctx, "worker-1")
+	if err != nil {
+		t.Fatalf("WorkerFindByID: Error[%v]", err)
+	}
+	if dbWorker == nil {
+		t.Fatal("Expected the worker to be registered again")
+	}
+}
+
+func Test_Store_WorkerListLiveAndDead(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("WorkerListLive: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	for _, workerID := range []string{"worker-live", "worker-dead"} {
+		if err := store.WorkerRegister(ctx, NewWorker(workerID)); err != nil {
+			t.Fatalf("WorkerRegister: Error[%v]", err)
+		}
+	}
+	markWorkerDead(t, store, "worker-dead")
+
+	live, err := store.WorkerListLive(ctx)
+	if err != nil {
+		t.Fatalf("WorkerListLive: Error[%v]", err)
+	}
+	if len(live) != 1 || live[0].GetID() != "worker-live" {
+		t.Fatalf("Expected only worker-live to be alive, got %d workers", len(live))
+	}
+
+	dead, err := store.WorkerListDead(ctx)
+	if err != nil {
+		t.Fatalf("WorkerListDead: Error[%v]", err)
+	}
+	if len(dead) != 1 || dead[0].GetID() != "worker-dead" {
+		t.Fatalf("Expected only worker-dead to be dead, got %d workers", len(dead))
+	}
+	if store.WorkerIsAlive(dead[0]) {
+		t.Fatal("Expected WorkerIsAlive to be false for a dead worker")
+	}
+}
+
+func Test_Store_WorkerPruneDead(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("WorkerPruneDead: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	for _, workerID := range []string{"worker-live", "worker-dead", "worker-expired"} {
+		if err := store.WorkerRegister(ctx, NewWorker(workerID)); err != nil {
+			t.Fatalf("WorkerRegister: Error[%v]", err)
+		}
+	}
+	markWorkerDead(t, store, "worker-dead")
+
+	heartbeatAt := carbon.Now(carbon.UTC).StdTime().Add(-store.workerTimeout - 2*store.workerRetention)
+	_, err = store.GetDB().Exec("UPDATE "+store.GetWorkerTableName()+" SET "+COLUMN_HEARTBEAT_AT+" = ? WHERE "+COLUMN_ID+" = ?", heartbeatAt.Format("2006-01-02 15:04:05"), "worker-expired")
+	if err != nil {
+		t.Fatalf("Exec: Error[%v]", err)
+	}
+
+	// The reaper prunes the workers dead for longer than the retention
+	if _, err := store.TaskQueueReapExpiredLeases(ctx, ""); err != nil {
+		t.Fatalf("TaskQueueReapExpiredLeases: Error[%v]", err)
+	}
+
+	for workerID, expected := range map[string]bool{"worker-live": true, "worker-dead": true, "worker-expired": false} {
+		found, err := store.WorkerFindByID(ctx, workerID)
+		if err != nil {
+			t.Fatalf("WorkerFindByID: Error[%v]", err)
+		}
+		if (found != nil) != expected {
+			t.Fatalf("Expected %s registered: %v, got %v", workerID, expected, found != nil)
+		}
+	}
+
+	pruned, err := store.WorkerPruneDead(ctx)
+	if err != nil {
+		t.Fatalf("WorkerPruneDead: Error[%v]", err)
+	}
+	if pruned != 0 {
+		t.Fatalf("Expected nothing left to prune, got %d", pruned)
+	}
+}
+
+func Test_Store_WorkerDeadTaskList(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("WorkerDeadTaskList: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	for _, workerID := range []string{"worker-live", "worker-dead"} {
+		if err := store.WorkerRegister(ctx, NewWorker(workerID)); err != nil {
+			t.Fatalf("WorkerRegister: Error[%v]", err)
+		}
+		if err := store.TaskQueueCreate(ctx, NewTaskQueue().SetTaskID("TASK_"+workerID)); err != nil {
+			t.Fatalf("TaskQueueCreate: Error[%v]", err)
+		}
+		if _, err := store.TaskQueueClaimNext(ContextWithWorkerID(ctx, workerID), DefaultQueueName); err != nil {
+			t.Fatalf("TaskQueueClaimNext: Error[%v]", err)
+		}
+	}
+	markWorkerDead(t, store, "worker-dead")
+
+	tasks, err := store.WorkerDeadTaskList(ctx)
+	if err != nil {
+		t.Fatalf("WorkerDeadTaskList: Error[%v]", err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("Expected 1 task of a dead worker, got %d", len(tasks))
+	}
+	if tasks[0].GetWorkerID() != "worker-dead" || tasks[0].GetTaskID() != "TASK_worker-dead" {
+		t.Fatalf("Expected the task claimed by worker-dead, got %s claimed by %s", tasks[0].GetTaskID(), tasks[0].GetWorkerID())
+	}
+}
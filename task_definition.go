@@ -24,6 +24,9 @@ type TaskDefinitionInterface interface {
 	GetCreatedAtCarbon() *carbon.Carbon
 	SetCreatedAt(createdAt time.Time) TaskDefinitionInterface
 
+	GetDeadLetterQueueName() string
+	SetDeadLetterQueueName(deadLetterQueueName string) TaskDefinitionInterface
+
 	GetDescription() string
 	SetDescription(description string) TaskDefinitionInterface
 
@@ -33,12 +36,27 @@ type TaskDefinitionInterface interface {
 	GetMemo() string
 	SetMemo(memo string) TaskDefinitionInterface
 
+	GetMaxConcurrency() int
+	SetMaxConcurrency(maxConcurrency int) TaskDefinitionInterface
+
+	GetParameterSchema() []TaskParameter
+	SetParameterSchema(schema []TaskParameter) TaskDefinitionInterface
+
+	GetPriority() int
+	SetPriority(priority int) TaskDefinitionInterface
+
 	GetIsRecurring() int
 	SetIsRecurring(isRecurring int) TaskDefinitionInterface
 
+	GetRateLimit() *RateLimit
+	SetRateLimit(rateLimit *RateLimit) TaskDefinitionInterface
+
 	GetRecurrenceRule() string
 	SetRecurrenceRule(recurrenceRule string) TaskDefinitionInterface
 
+	GetRetryPolicy() *RetryPolicy
+	SetRetryPolicy(policy *RetryPolicy) TaskDefinitionInterface
+
 	GetSoftDeletedAt() time.Time
 	GetSoftDeletedAtCarbon() *carbon.Carbon
 	SetSoftDeletedAt(deletedAt time.Time) TaskDefinitionInterface
@@ -46,6 +64,9 @@ type TaskDefinitionInterface interface {
 	GetStatus() string
 	SetStatus(status string) TaskDefinitionInterface
 
+	GetTimeoutSeconds() int
+	SetTimeoutSeconds(timeoutSeconds int) TaskDefinitionInterface
+
 	GetTitle() string
 	SetTitle(title string) TaskDefinitionInterface
 
@@ -118,13 +139,20 @@ type TaskDefinitionQueryInterface interface {
 type taskDefinition struct {
 	orm.ShortID
 
-	StatusField         string `db:"status"`
-	AliasField          string `db:"alias"`
-	TitleField          string `db:"title"`
-	DescriptionField    string `db:"description"`
-	MemoField           string `db:"memo"`
-	IsRecurringField    int    `db:"is_recurring"`
-	RecurrenceRuleField string `db:"recurrence_rule"`
+	StatusField              string `db:"status"`
+	AliasField               string `db:"alias"`
+	TitleField               string `db:"title"`
+	DescriptionField         string `db:"description"`
+	MemoField                string `db:"memo"`
+	PriorityField            int    `db:"priority"`
+	IsRecurringField         int    `db:"is_recurring"`
+	RecurrenceRuleField      string `db:"recurrence_rule"`
+	RetryPolicyField         string `db:"retry_policy"`
+	DeadLetterQueueNameField string `db:"dead_letter_queue_name"`
+	TimeoutSecondsField      int    `db:"timeout_seconds"`
+	RateLimitField           string `db:"rate_limit"`
+	MaxConcurrencyField      int    `db:"max_concurrency"`
+	ParameterSchemaField     string `db:"parameter_schema"`
 
 	CreatedAtField orm.CreatedAt
 	UpdatedAtField orm.UpdatedAt
@@ -145,6 +173,7 @@ func NewTaskDefinition() TaskDefinitionInterface {
 		SetIsRecurring(0).
 		SetRecurrenceRule("").
 		SetMemo("").
+		SetPriority(DefaultPriority).
 		SetCreatedAt(carbon.Now(carbon.UTC).StdTime()).
 		SetUpdatedAt(carbon.Now(carbon.UTC).StdTime()).
 		SetSoftDeletedAt(carbon.Parse(MAX_DATETIME, carbon.UTC).StdTime())
@@ -160,8 +189,15 @@ func NewTaskDefinitionFromExistingData(data map[string]string) TaskDefinitionInt
 	o.SetTitle(data[COLUMN_TITLE])
 	o.SetDescription(data[COLUMN_DESCRIPTION])
 	o.SetMemo(data[COLUMN_MEMO])
+	o.SetPriority(cast.ToInt(data[COLUMN_PRIORITY]))
 	o.SetIsRecurring(cast.ToInt(data[COLUMN_IS_RECURRING]))
 	o.SetRecurrenceRule(data[COLUMN_RECURRENCE_RULE])
+	o.SetRetryPolicy(retryPolicyFromJSON(data[COLUMN_RETRY_POLICY]))
+	o.SetDeadLetterQueueName(data[COLUMN_DEAD_LETTER_QUEUE_NAME])
+	o.SetTimeoutSeconds(cast.ToInt(data[COLUMN_TIMEOUT_SECONDS]))
+	o.SetRateLimit(rateLimitFromJSON(data[COLUMN_RATE_LIMIT]))
+	o.SetMaxConcurrency(cast.ToInt(data[COLUMN_MAX_CONCURRENCY]))
+	o.SetParameterSchema(parameterSchemaFromJSON(data[COLUMN_PARAMETER_SCHEMA]))
 	if v, ok := data[COLUMN_CREATED_AT]; ok {
 		o.SetCreatedAt(parseTime(v))
 	}
@@ -212,6 +248,18 @@ func (o *taskDefinition) SetCreatedAt(createdAt time.Time) TaskDefinitionInterfa
 	return o
 }
 
+// GetDeadLetterQueueName returns the name of the dead letter queue the
+// queued tasks of this task definition are moved to, once they failed
+// for good. Returns an empty string if failed tasks are left as they are.
+func (o *taskDefinition) GetDeadLetterQueueName() string {
+	return o.DeadLetterQueueNameField
+}
+
+func (o *taskDefinition) SetDeadLetterQueueName(deadLetterQueueName string) TaskDefinitionInterface {
+	o.DeadLetterQueueNameField = deadLetterQueueName
+	return o
+}
+
 func (o *taskDefinition) GetDescription() string {
 	return o.DescriptionField
 }
@@ -239,6 +287,29 @@ func (o *taskDefinition) SetMemo(memo string) TaskDefinitionInterface {
 	return o
 }
 
+// GetMaxConcurrency returns how many tasks of this task definition may run
+// at the same time, across all queues and workers. A value of 1 makes the
+// task a global singleton, 0 or less means unlimited.
+func (o *taskDefinition) GetMaxConcurrency() int {
+	return o.MaxConcurrencyField
+}
+
+func (o *taskDefinition) SetMaxConcurrency(maxConcurrency int) TaskDefinitionInterface {
+	o.MaxConcurrencyField = maxConcurrency
+	return o
+}
+
+// GetPriority returns the default priority of the tasks enqueued
+// for this task definition
+func (o *taskDefinition) GetPriority() int {
+	return o.PriorityField
+}
+
+func (o *taskDefinition) SetPriority(priority int) TaskDefinitionInterface {
+	o.PriorityField = priority
+	return o
+}
+
 func (o *taskDefinition) GetIsRecurring() int {
 	return o.IsRecurringField
 }
@@ -248,6 +319,29 @@ func (o *taskDefinition) SetIsRecurring(isRecurring int) TaskDefinitionInterface
 	return o
 }
 
+// GetParameterSchema returns the parameters the tasks of this task
+// definition expect. Returns nil if the parameters are not declared.
+func (o *taskDefinition) GetParameterSchema() []TaskParameter {
+	return parameterSchemaFromJSON(o.ParameterSchemaField)
+}
+
+func (o *taskDefinition) SetParameterSchema(schema []TaskParameter) TaskDefinitionInterface {
+	o.ParameterSchemaField = parameterSchemaToJSON(schema)
+	return o
+}
+
+// GetRateLimit returns the rate limit applied when claiming the queued
+// tasks of this task definition, across all queues. Returns nil if the
+// tasks are not rate limited.
+func (o *taskDefinition) GetRateLimit() *RateLimit {
+	return rateLimitFromJSON(o.RateLimitField)
+}
+
+func (o *taskDefinition) SetRateLimit(rateLimit *RateLimit) TaskDefinitionInterface {
+	o.RateLimitField = rateLimitToJSON(rateLimit)
+	return o
+}
+
 func (o *taskDefinition) GetRecurrenceRule() string {
 	return o.RecurrenceRuleField
 }
@@ -257,6 +351,17 @@ func (o *taskDefinition) SetRecurrenceRule(recurrenceRule string) TaskDefinition
 	return o
 }
 
+// GetRetryPolicy returns the retry policy applied to the queued tasks of
+// this task definition. Returns nil if failed tasks are not retried.
+func (o *taskDefinition) GetRetryPolicy() *RetryPolicy {
+	return retryPolicyFromJSON(o.RetryPolicyField)
+}
+
+func (o *taskDefinition) SetRetryPolicy(policy *RetryPolicy) TaskDefinitionInterface {
+	o.RetryPolicyField = retryPolicyToJSON(policy)
+	return o
+}
+
 func (o *taskDefinition) GetSoftDeletedAt() time.Time {
 	return o.SoftDeletesMaxDate.SoftDeletedAt
 }
@@ -279,6 +384,18 @@ func (o *taskDefinition) SetStatus(status string) TaskDefinitionInterface {
 	return o
 }
 
+// GetTimeoutSeconds returns the execution timeout of the tasks enqueued
+// for this definition. A task running longer is marked as timed out.
+// 0 means no timeout.
+func (o *taskDefinition) GetTimeoutSeconds() int {
+	return o.TimeoutSecondsField
+}
+
+func (o *taskDefinition) SetTimeoutSeconds(timeoutSeconds int) TaskDefinitionInterface {
+	o.TimeoutSecondsField = timeoutSeconds
+	return o
+}
+
 func (o *taskDefinition) GetTitle() string {
 	return o.TitleField
 }
@@ -2,6 +2,7 @@ package taskstore
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"sync"
@@ -134,6 +135,50 @@ type TaskHandlerWithContext interface {
 	HandleWithContext(ctx context.Context) bool
 }
 
+// TaskHandlerWithError is an optional interface that task handlers can
+// implement to return why a task failed. It takes precedence over
+// HandleWithContext and Handle. A nil error completes the task, an error
+// fails it: the error is recorded in the error message of the queued task,
+// and passed to the error handler of the store (see Store.SetErrorHandler).
+//
+// Errors are retried as the retry policy allows, unless they are wrapped
+// with PermanentError.
+//
+// Example usage:
+//
+//	func (h *MyHandler) HandleE(ctx context.Context) error {
+//	    user, err := findUser(ctx, h.GetParam("user_id"))
+//	    if err != nil {
+//	        return err // retried
+//	    }
+//	    if user == nil {
+//	        return taskstore.PermanentError(errors.New("user not found"))
+//	    }
+//	    return nil
+//	}
+type TaskHandlerWithError interface {
+	TaskDefinitionHandlerInterface
+	HandleE(ctx context.Context) error
+}
+
+// TaskHandlerWithParameters is an optional interface that task handlers can
+// implement to declare the parameters they expect. The parameters are stored
+// as the parameter schema of the task definition when the handler is added,
+// and enqueuing checks the parameters against them, filling the defaults.
+//
+// Example usage:
+//
+//	func (h *MyHandler) Parameters() []taskstore.TaskParameter {
+//	    return []taskstore.TaskParameter{
+//	        {Name: "user_id", Type: taskstore.ParameterTypeInteger, Required: true},
+//	        {Name: "template", Type: taskstore.ParameterTypeString, Default: "welcome"},
+//	    }
+//	}
+type TaskHandlerWithParameters interface {
+	TaskDefinitionHandlerInterface
+	Parameters() []TaskParameter
+}
+
 // == BASE IMPLEMENTATION ======================================================
 
 // TaskHandlerBase alias is kept for backwards compatibility.
@@ -285,6 +330,22 @@ func (handler *TaskDefinitionHandlerBase) LogSuccess(message string) {
 	}
 }
 
+// FailPermanently records an error message like LogError, and marks the
+// queued task (when present) as permanently failed, so it is not retried
+// even if its retry policy allows more attempts. The handler must still
+// return false for the task to fail.
+func (handler *TaskDefinitionHandlerBase) FailPermanently(message string) {
+	handler.LogError(message)
+
+	handler.mu.RLock()
+	qt := handler.queuedTask
+	handler.mu.RUnlock()
+
+	if marker, ok := qt.(permanentFailureMarker); ok {
+		marker.setPermanentFailure(true)
+	}
+}
+
 // GetParam returns the value of a named parameter for the current execution.
 // When a queued task is present it reads from the task's parameter map;
 // otherwise it falls back to the handler options. If the parameter is
@@ -316,7 +377,8 @@ func (handler *TaskDefinitionHandlerBase) GetParam(paramName string) string {
 	}
 }
 
-// GetParamArray returns the named parameter split on semicolons into a slice.
+// GetParamArray returns the named parameter split on semicolons into a slice,
+// or its values when it was enqueued as a JSON array.
 // If the parameter is missing or empty, it returns an empty slice.
 func (handler *TaskDefinitionHandlerBase) GetParamArray(paramName string) []string {
 	param := handler.GetParam(paramName)
@@ -325,6 +387,22 @@ func (handler *TaskDefinitionHandlerBase) GetParamArray(paramName string) []stri
 		return []string{}
 	}
 
+	// Array parameters enqueued as JSON arrays
+	if strings.HasPrefix(param, "[") {
+		var values []json.RawMessage
+		if json.Unmarshal([]byte(param), &values) == nil {
+			result := make([]string, 0, len(values))
+			for _, rawValue := range values {
+				var value string
+				if json.Unmarshal(rawValue, &value) != nil {
+					value = string(rawValue)
+				}
+				result = append(result, value)
+			}
+			return result
+		}
+	}
+
 	result := strings.Split(param, ";")
 	if result == nil {
 		return []string{}
@@ -132,5 +132,10 @@ func (handler *testTaskHandler) Handle() bool {
 		return false
 	}
 
+	if handler.GetParam("failPermanently") == "yes" {
+		handler.FailPermanently("Task forced to fail permanently.")
+		return false
+	}
+
 	return false
 }
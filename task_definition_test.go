@@ -223,6 +223,47 @@ func TestTaskDefinition_SettersAndGetters(t *testing.T) {
 		t.Errorf("Status: Expected %s, got %s", TaskDefinitionStatusCanceled, task.GetStatus())
 	}
 
+	// Test Priority
+	task.SetPriority(3)
+	if task.GetPriority() != 3 {
+		t.Errorf("Priority: Expected 3, got %d", task.GetPriority())
+	}
+
+	// Test RetryPolicy
+	testRetryPolicy := &RetryPolicy{MaxAttempts: 5, DelaySeconds: 30}
+	task.SetRetryPolicy(testRetryPolicy)
+	if task.GetRetryPolicy() == nil || *task.GetRetryPolicy() != *testRetryPolicy {
+		t.Errorf("RetryPolicy: Expected %v, got %v", testRetryPolicy, task.GetRetryPolicy())
+	}
+
+	// Test DeadLetterQueueName
+	task.SetDeadLetterQueueName("poison")
+	if task.GetDeadLetterQueueName() != "poison" {
+		t.Errorf("DeadLetterQueueName: Expected poison, got %s", task.GetDeadLetterQueueName())
+	}
+
+	// Test TimeoutSeconds
+	task.SetTimeoutSeconds(30)
+	if task.GetTimeoutSeconds() != 30 {
+		t.Errorf("TimeoutSeconds: Expected 30, got %d", task.GetTimeoutSeconds())
+	}
+
+	// Test RateLimit
+	task.SetRateLimit(&RateLimit{Limit: 60, PeriodSeconds: 60})
+	if rateLimit := task.GetRateLimit(); rateLimit == nil || rateLimit.Limit != 60 || rateLimit.PeriodSeconds != 60 {
+		t.Errorf("RateLimit: Expected 60 per 60 seconds, got %v", rateLimit)
+	}
+	task.SetRateLimit(nil)
+	if task.GetRateLimit() != nil {
+		t.Errorf("RateLimit: Expected nil, got %v", task.GetRateLimit())
+	}
+
+	// Test MaxConcurrency
+	task.SetMaxConcurrency(1)
+	if task.GetMaxConcurrency() != 1 {
+		t.Errorf("MaxConcurrency: Expected 1, got %d", task.GetMaxConcurrency())
+	}
+
 	// Test CreatedAt
 	testCreatedAt := "2023-01-01 10:00:00"
 	task.SetCreatedAt(carbon.Parse(testCreatedAt, carbon.UTC).StdTime())
@@ -0,0 +1,53 @@
+package taskstore
+
+import "errors"
+
+// PermanentError wraps err to mark the failure of a task as permanent: the
+// task is not retried, even if its retry policy allows more attempts (as
+// with TaskDefinitionHandlerBase.FailPermanently). Returns nil if err is nil.
+func PermanentError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &taskFailureError{err: err, permanent: true}
+}
+
+// RetryableError wraps err to mark the failure of a task as retryable: the
+// task is retried as its retry policy allows. Errors which are not wrapped
+// are retryable too, so it is only needed to override a PermanentError
+// wrapped by err. Returns nil if err is nil.
+func RetryableError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &taskFailureError{err: err, permanent: false}
+}
+
+// IsPermanentError reports whether err is marked as permanent by
+// PermanentError, and not wrapped again with RetryableError since
+func IsPermanentError(err error) bool {
+	var failure *taskFailureError
+	return errors.As(err, &failure) && failure.permanent
+}
+
+// IsRetryableError reports whether err is an error a task is retried for,
+// which is any error not marked as permanent
+func IsRetryableError(err error) bool {
+	return err != nil && !IsPermanentError(err)
+}
+
+// taskFailureError classifies the failure of a task as permanent or not
+type taskFailureError struct {
+	err       error
+	permanent bool
+}
+
+func (e *taskFailureError) Error() string {
+	return e.err.Error()
+}
+
+func (e *taskFailureError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,47 @@
+package taskstore
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestTaskErrorClassification(t *testing.T) {
+	cause := errors.New("boom")
+
+	tests := []struct {
+		name      string
+		err       error
+		permanent bool
+		retryable bool
+	}{
+		{name: "nil", err: nil, permanent: false, retryable: false},
+		{name: "plain", err: cause, permanent: false, retryable: true},
+		{name: "permanent", err: PermanentError(cause), permanent: true, retryable: false},
+		{name: "retryable", err: RetryableError(cause), permanent: false, retryable: true},
+		{name: "wrapped permanent", err: fmt.Errorf("sending: %w", PermanentError(cause)), permanent: true, retryable: false},
+		{name: "retryable over permanent", err: RetryableError(PermanentError(cause)), permanent: false, retryable: true},
+		{name: "permanent over retryable", err: PermanentError(RetryableError(cause)), permanent: true, retryable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPermanentError(tt.err); got != tt.permanent {
+				t.Fatalf("IsPermanentError() = %v, want %v", got, tt.permanent)
+			}
+			if got := IsRetryableError(tt.err); got != tt.retryable {
+				t.Fatalf("IsRetryableError() = %v, want %v", got, tt.retryable)
+			}
+			if tt.err != nil && !errors.Is(tt.err, cause) {
+				t.Fatal("Expected the error to wrap its cause")
+			}
+		})
+	}
+
+	if PermanentError(nil) != nil || RetryableError(nil) != nil {
+		t.Fatal("Expected nil errors to stay nil")
+	}
+	if PermanentError(cause).Error() != cause.Error() {
+		t.Fatalf("Expected message %s, got %s", cause.Error(), PermanentError(cause).Error())
+	}
+}
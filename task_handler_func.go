@@ -0,0 +1,123 @@
+package taskstore
+
+import (
+	"context"
+	"fmt"
+	"maps"
+)
+
+// TaskFunc is the function of a handler registered with
+// Store.TaskHandlerFunc. A nil error completes the task, an error fails it,
+// as returned by TaskHandlerWithError.HandleE.
+type TaskFunc func(ctx context.Context, task TaskContext) error
+
+// TaskContext is passed to a TaskFunc. It exposes the parameters of the
+// queued task being processed (or of the CLI, when executed with
+// TaskDefinitionExecuteCli), and records its logs, output and progress.
+type TaskContext interface {
+	// GetQueuedTask returns the queued task being processed.
+	// Nil when executed with TaskDefinitionExecuteCli.
+	GetQueuedTask() TaskQueueInterface
+
+	// GetParam returns the value of a named parameter, or an empty string
+	// if it is missing.
+	GetParam(paramName string) string
+
+	// GetParamArray returns the values of a named array parameter.
+	GetParamArray(paramName string) []string
+
+	// GetParams returns all the parameters.
+	GetParams() map[string]string
+
+	// LogError, LogInfo and LogSuccess record a message in the details of
+	// the queued task, or print it when executed from the CLI.
+	LogError(message string)
+	LogInfo(message string)
+	LogSuccess(message string)
+
+	// GetOutput and SetOutput access the output of the queued task.
+	GetOutput() string
+	SetOutput(output string)
+
+	// SetProgress records how many of the total steps of the task are
+	// done. It is saved to the details of the queued task right away, so
+	// the progress can be followed while the task runs.
+	SetProgress(done int, total int)
+}
+
+// funcTaskHandler adapts a TaskFunc to a task definition handler. A fresh
+// funcTaskHandler is created for each execution (see TaskHandlerFunc), so
+// it keeps the context of the execution it belongs to.
+type funcTaskHandler struct {
+	TaskDefinitionHandlerBase
+	store *Store
+	alias string
+	title string
+	fn    TaskFunc
+	ctx   context.Context
+}
+
+var _ TaskHandlerWithError = (*funcTaskHandler)(nil)
+var _ TaskContext = (*funcTaskHandler)(nil)
+
+func (h *funcTaskHandler) Alias() string {
+	return h.alias
+}
+
+func (h *funcTaskHandler) Title() string {
+	return h.title
+}
+
+func (h *funcTaskHandler) Description() string {
+	return h.title
+}
+
+// Handle runs the function from the CLI, where no error can be returned
+func (h *funcTaskHandler) Handle() bool {
+	err := h.HandleE(context.Background())
+	if err != nil {
+		h.LogError(err.Error())
+	}
+	return err == nil
+}
+
+func (h *funcTaskHandler) HandleE(ctx context.Context) error {
+	h.ctx = ctx
+	return h.fn(ctx, h)
+}
+
+func (h *funcTaskHandler) GetParams() map[string]string {
+	queuedTask := h.GetQueuedTask()
+	if queuedTask == nil {
+		return maps.Clone(h.GetOptions())
+	}
+
+	parameters, err := queuedTask.ParametersMap()
+	if err != nil {
+		queuedTask.AppendDetails("Parameters JSON incorrect. " + err.Error())
+		return map[string]string{}
+	}
+
+	return parameters
+}
+
+func (h *funcTaskHandler) SetProgress(done int, total int) {
+	message := fmt.Sprintf("Progress: %d/%d", done, total)
+
+	queuedTask := h.GetQueuedTask()
+	if queuedTask == nil {
+		fmt.Println("INFO:", message)
+		return
+	}
+
+	queuedTask.AppendDetails(message)
+
+	ctx := h.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := h.store.taskQueueUpdateDetails(ctx, queuedTask); err != nil && h.store.debugEnabled {
+		h.store.logger.Error("SetProgress: failed to save details", "id", queuedTask.GetID(), "error", err)
+	}
+}
@@ -0,0 +1,116 @@
+package taskstore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_Store_TaskHandlerFunc(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("TaskHandlerFunc: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	if err := store.TaskHandlerFunc(ctx, "send-email", "Send email", nil); err == nil {
+		t.Fatal("TaskHandlerFunc: Expected an error for a nil function")
+	}
+
+	var savedDetails string
+	err = store.TaskHandlerFunc(ctx, "send-email", "Send email", func(ctx context.Context, task TaskContext) error {
+		if task.GetParams()["to"] != task.GetParam("to") {
+			t.Errorf("Expected GetParams and GetParam to agree, got %v", task.GetParams())
+		}
+		if task.GetParam("to") == "" {
+			return PermanentError(errors.New("to is required"))
+		}
+
+		task.LogInfo("Sending to " + task.GetParam("to"))
+		task.SetProgress(1, 2)
+
+		// The progress is saved while the task runs
+		if queuedTask := task.GetQueuedTask(); queuedTask != nil {
+			dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+			if err != nil {
+				return err
+			}
+			savedDetails = dbTask.GetDetails()
+		}
+
+		task.SetProgress(2, 2)
+		task.SetOutput("sent to " + task.GetParam("to"))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("TaskHandlerFunc: Error[%v]", err)
+	}
+
+	definition, err := store.TaskDefinitionFindByAlias(ctx, "send-email")
+	if err != nil {
+		t.Fatalf("TaskDefinitionFindByAlias: Error[%v]", err)
+	}
+	if definition == nil {
+		t.Fatal("TaskHandlerFunc: Expected the task definition to be created")
+	}
+	if definition.GetTitle() != "Send email" {
+		t.Fatalf("Expected title %s, got %s", "Send email", definition.GetTitle())
+	}
+
+	queuedTask, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, "send-email", map[string]any{
+		"to": "jane@example.com",
+	})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+	}
+	if _, err := store.QueuedTaskProcessWithContext(ctx, queuedTask); err != nil {
+		t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusSuccess {
+		t.Fatalf("Expected status %s, got %s", TaskQueueStatusSuccess, dbTask.GetStatus())
+	}
+	if dbTask.GetOutput() != "sent to jane@example.com" {
+		t.Fatalf("Expected output %s, got %s", "sent to jane@example.com", dbTask.GetOutput())
+	}
+	for _, expected := range []string{"Sending to jane@example.com", "Progress: 1/2", "Progress: 2/2"} {
+		if !strings.Contains(dbTask.GetDetails(), expected) {
+			t.Fatalf("Expected the details to contain %q, got %s", expected, dbTask.GetDetails())
+		}
+	}
+	if !strings.Contains(savedDetails, "Progress: 1/2") {
+		t.Fatalf("Expected the progress to be saved while running, got %s", savedDetails)
+	}
+
+	// Errors fail the task, as for TaskHandlerWithError
+	queuedTask, err = store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, "send-email", map[string]any{})
+	if err != nil {
+		t.Fatalf("TaskDefinitionEnqueueByAlias: Error[%v]", err)
+	}
+	if _, err := store.QueuedTaskProcessWithContext(ctx, queuedTask); err != nil {
+		t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+	}
+
+	dbTask, err = store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusFailed {
+		t.Fatalf("Expected status %s, got %s", TaskQueueStatusFailed, dbTask.GetStatus())
+	}
+	if dbTask.GetErrorMessage() != "to is required" {
+		t.Fatalf("Expected error message %q, got %q", "to is required", dbTask.GetErrorMessage())
+	}
+
+	// The CLI passes its arguments as parameters
+	if !store.TaskDefinitionExecuteCli("send-email", []string{"--to=john@example.com"}) {
+		t.Fatal("TaskDefinitionExecuteCli: Expected the task to be found")
+	}
+}
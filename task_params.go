@@ -0,0 +1,100 @@
+package taskstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// Enqueue enqueues the task definition with the alias, with the typed
+// parameters params. The parameters are encoded to JSON, so they must
+// encode to a JSON object (i.e. a struct or a map), and are decoded by the
+// handler with DecodeParams. Nested objects, numbers and arrays are kept.
+func Enqueue[T any](ctx context.Context, store StoreInterface, queueName string, alias string, params T, options ...EnqueueOptions) (TaskQueueInterface, error) {
+	parameters, err := paramsToMap(params)
+	if err != nil {
+		return nil, err
+	}
+
+	enqueueOptions := EnqueueOptions{}
+	if len(options) > 0 {
+		enqueueOptions = options[0]
+	}
+
+	return store.TaskDefinitionEnqueueByAliasWithOptions(ctx, queueName, alias, parameters, enqueueOptions)
+}
+
+// DecodeParams decodes the parameters of the queued task into a T, i.e. a
+// struct enqueued with Enqueue. Parameters without a matching field (like
+// the task_alias added when enqueuing) are ignored.
+func DecodeParams[T any](queuedTask TaskQueueInterface) (T, error) {
+	var params T
+	if queuedTask == nil {
+		return params, errors.New("queued task is nil")
+	}
+	if queuedTask.GetParameters() == "" {
+		return params, nil
+	}
+
+	if err := json.Unmarshal([]byte(queuedTask.GetParameters()), &params); err != nil {
+		return params, err
+	}
+
+	return params, nil
+}
+
+// SetResult encodes the result to JSON, as the output of the queued task
+func SetResult[R any](queuedTask TaskQueueInterface, result R) error {
+	if queuedTask == nil {
+		return errors.New("queued task is nil")
+	}
+
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	queuedTask.SetOutput(string(resultBytes))
+	return nil
+}
+
+// GetResult decodes the output of the queued task, set with SetResult,
+// into an R. Returns the zero value of R if the task has no output.
+func GetResult[R any](queuedTask TaskQueueInterface) (R, error) {
+	var result R
+	if queuedTask == nil {
+		return result, errors.New("queued task is nil")
+	}
+	if queuedTask.GetOutput() == "" {
+		return result, nil
+	}
+
+	if err := json.Unmarshal([]byte(queuedTask.GetOutput()), &result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// paramsToMap converts typed parameters to the parameters map taken by
+// TaskDefinitionEnqueueByAlias, through their JSON encoding
+func paramsToMap[T any](params T) (map[string]any, error) {
+	paramsBytes, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(paramsBytes))
+	decoder.UseNumber()
+
+	var parameters map[string]any
+	if err := decoder.Decode(&parameters); err != nil {
+		return nil, errors.New("parameters must encode to a JSON object")
+	}
+	if parameters == nil {
+		parameters = map[string]any{}
+	}
+
+	return parameters, nil
+}
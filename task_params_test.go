@@ -0,0 +1,144 @@
+package taskstore
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type testEmailParams struct {
+	To       string            `json:"to"`
+	Retries  int               `json:"retries"`
+	Urgent   bool              `json:"urgent"`
+	Tags     []string          `json:"tags"`
+	Headers  map[string]string `json:"headers"`
+	Template struct {
+		Name    string `json:"name"`
+		Version int64  `json:"version"`
+	} `json:"template"`
+}
+
+type testEmailResult struct {
+	MessageID string `json:"message_id"`
+	Sent      int    `json:"sent"`
+}
+
+func Test_Enqueue_DecodeParams(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatalf("Enqueue: Error[%v]", err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	var decoded testEmailParams
+	err = store.TaskHandlerFunc(ctx, "send-email", "Send email", func(ctx context.Context, task TaskContext) error {
+		params, err := DecodeParams[testEmailParams](task.GetQueuedTask())
+		if err != nil {
+			return err
+		}
+		decoded = params
+
+		return SetResult(task.GetQueuedTask(), testEmailResult{MessageID: "msg-1", Sent: len(params.Tags)})
+	})
+	if err != nil {
+		t.Fatalf("TaskHandlerFunc: Error[%v]", err)
+	}
+
+	params := testEmailParams{
+		To:      "jane@example.com",
+		Retries: 3,
+		Urgent:  true,
+		Tags:    []string{"welcome", "onboarding"},
+		Headers: map[string]string{"X-Campaign": "spring"},
+	}
+	params.Template.Name = "welcome"
+	params.Template.Version = 9007199254740993
+
+	queuedTask, err := Enqueue(ctx, store, DefaultQueueName, "send-email", params)
+	if err != nil {
+		t.Fatalf("Enqueue: Error[%v]", err)
+	}
+
+	if _, err := store.QueuedTaskProcessWithContext(ctx, queuedTask); err != nil {
+		t.Fatalf("QueuedTaskProcessWithContext: Error[%v]", err)
+	}
+
+	if !reflect.DeepEqual(decoded, params) {
+		t.Fatalf("Expected params %+v, got %+v", params, decoded)
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatalf("TaskQueueFindByID: Error[%v]", err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusSuccess {
+		t.Fatalf("Expected status %s, got %s", TaskQueueStatusSuccess, dbTask.GetStatus())
+	}
+
+	result, err := GetResult[testEmailResult](dbTask)
+	if err != nil {
+		t.Fatalf("GetResult: Error[%v]", err)
+	}
+	if result != (testEmailResult{MessageID: "msg-1", Sent: 2}) {
+		t.Fatalf("Expected result %+v, got %+v", testEmailResult{MessageID: "msg-1", Sent: 2}, result)
+	}
+
+	// The parameters map keeps the values which are not strings
+	parameters, err := dbTask.ParametersMap()
+	if err != nil {
+		t.Fatalf("ParametersMap: Error[%v]", err)
+	}
+	expected := map[string]string{
+		"task_alias": "send-email",
+		"to":         "jane@example.com",
+		"retries":    "3",
+		"urgent":     "true",
+		"tags":       `["welcome","onboarding"]`,
+		"headers":    `{"X-Campaign":"spring"}`,
+		"template":   `{"name":"welcome","version":9007199254740993}`,
+	}
+	if !reflect.DeepEqual(parameters, expected) {
+		t.Fatalf("Expected parameters %v, got %v", expected, parameters)
+	}
+
+	handler := &TaskDefinitionHandlerBase{}
+	handler.SetQueuedTask(dbTask)
+	if tags := handler.GetParamArray("tags"); !reflect.DeepEqual(tags, []string{"welcome", "onboarding"}) {
+		t.Fatalf("Expected tags %v, got %v", []string{"welcome", "onboarding"}, tags)
+	}
+
+	if _, err := Enqueue(ctx, store, DefaultQueueName, "send-email", []string{"not", "an", "object"}); err == nil {
+		t.Fatal("Enqueue: Expected an error for parameters which are not an object")
+	}
+}
+
+func Test_DecodeParams_GetResult_Empty(t *testing.T) {
+	if _, err := DecodeParams[testEmailParams](nil); err == nil {
+		t.Fatal("DecodeParams: Expected an error for a nil queued task")
+	}
+
+	queuedTask := NewTaskQueue()
+
+	params, err := DecodeParams[testEmailParams](queuedTask)
+	if err != nil {
+		t.Fatalf("DecodeParams: Error[%v]", err)
+	}
+	if !reflect.DeepEqual(params, testEmailParams{}) {
+		t.Fatalf("Expected empty params, got %+v", params)
+	}
+
+	result, err := GetResult[testEmailResult](queuedTask)
+	if err != nil {
+		t.Fatalf("GetResult: Error[%v]", err)
+	}
+	if result != (testEmailResult{}) {
+		t.Fatalf("Expected an empty result, got %+v", result)
+	}
+
+	queuedTask.SetOutput("plain text")
+	if _, err := GetResult[testEmailResult](queuedTask); err == nil {
+		t.Fatal("GetResult: Expected an error for an output which is not JSON")
+	}
+}
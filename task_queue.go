@@ -2,6 +2,7 @@ package taskstore
 
 import (
 	"encoding/json"
+	"sync"
 	"time"
 
 	"github.com/dracory/neat/database/orm"
@@ -15,6 +16,7 @@ import (
 
 type TaskQueueInterface interface {
 	IsCanceled() bool
+	IsCancelRequested() bool
 	IsDeleted() bool
 	IsFailed() bool
 	IsQueued() bool
@@ -26,6 +28,19 @@ type TaskQueueInterface interface {
 	GetAttempts() int
 	SetAttempts(attempts int) TaskQueueInterface
 
+	GetAvailableAt() time.Time
+	GetAvailableAtCarbon() *carbon.Carbon
+	SetAvailableAt(availableAt time.Time) TaskQueueInterface
+
+	GetBatchID() string
+	SetBatchID(batchID string) TaskQueueInterface
+
+	GetCancelRequestedAt() time.Time
+
+	GetClaimedAt() time.Time
+	GetClaimedAtCarbon() *carbon.Carbon
+	SetClaimedAt(claimedAt time.Time) TaskQueueInterface
+
 	GetCompletedAt() time.Time
 	GetCompletedAtCarbon() *carbon.Carbon
 	SetCompletedAt(completedAt time.Time) TaskQueueInterface
@@ -34,13 +49,36 @@ type TaskQueueInterface interface {
 	GetCreatedAtCarbon() *carbon.Carbon
 	SetCreatedAt(createdAt time.Time) TaskQueueInterface
 
+	GetDeadLetterQueueName() string
+	SetDeadLetterQueueName(deadLetterQueueName string) TaskQueueInterface
+
+	GetDeadLetteredAt() time.Time
+	GetDeadLetteredAtCarbon() *carbon.Carbon
+	SetDeadLetteredAt(deadLetteredAt time.Time) TaskQueueInterface
+
+	GetDependencyFailurePolicy() string
+	SetDependencyFailurePolicy(policy string) TaskQueueInterface
+
+	GetDependsOn() []string
+	SetDependsOn(taskQueueIDs []string) TaskQueueInterface
+
 	GetDetails() string
 	AppendDetails(details string) TaskQueueInterface
 	SetDetails(details string) TaskQueueInterface
 
+	GetErrorMessage() string
+	SetErrorMessage(errorMessage string) TaskQueueInterface
+
 	GetID() string
 	SetID(id string) TaskQueueInterface
 
+	GetLeaseExpiresAt() time.Time
+	GetLeaseExpiresAtCarbon() *carbon.Carbon
+	SetLeaseExpiresAt(leaseExpiresAt time.Time) TaskQueueInterface
+
+	GetLeaseOwner() string
+	SetLeaseOwner(leaseOwner string) TaskQueueInterface
+
 	GetOutput() string
 	SetOutput(output string) TaskQueueInterface
 
@@ -49,6 +87,12 @@ type TaskQueueInterface interface {
 	ParametersMap() (map[string]string, error)
 	SetParametersMap(parameters map[string]string) (TaskQueueInterface, error)
 
+	GetPriority() int
+	SetPriority(priority int) TaskQueueInterface
+
+	GetRetryPolicy() *RetryPolicy
+	SetRetryPolicy(policy *RetryPolicy) TaskQueueInterface
+
 	GetSoftDeletedAt() time.Time
 	GetSoftDeletedAtCarbon() *carbon.Carbon
 	SetSoftDeletedAt(deletedAt time.Time) TaskQueueInterface
@@ -63,12 +107,24 @@ type TaskQueueInterface interface {
 	GetTaskID() string
 	SetTaskID(taskID string) TaskQueueInterface
 
+	GetTimeoutSeconds() int
+	SetTimeoutSeconds(timeoutSeconds int) TaskQueueInterface
+
+	GetUniqueKey() string
+	SetUniqueKey(uniqueKey string) TaskQueueInterface
+
+	GetUniqueUntil() time.Time
+	SetUniqueUntil(uniqueUntil time.Time) TaskQueueInterface
+
 	GetUpdatedAt() time.Time
 	GetUpdatedAtCarbon() *carbon.Carbon
 	SetUpdatedAt(updatedAt time.Time) TaskQueueInterface
 
 	GetQueueName() string
 	SetQueueName(queueName string) TaskQueueInterface
+
+	GetWorkerID() string
+	SetWorkerID(workerID string) TaskQueueInterface
 }
 
 // == TYPE =====================================================================
@@ -76,23 +132,50 @@ type TaskQueueInterface interface {
 type taskQueue struct {
 	orm.ShortID
 
-	QueueNameField   string    `db:"queue_name"`
-	TaskIDField      string    `db:"task_id"`
-	ParametersField  string    `db:"parameters"`
-	StatusField      string    `db:"status"`
-	OutputField      string    `db:"output"`
-	DetailsField     string    `db:"details"`
-	AttemptsField    int       `db:"attempts"`
-	StartedAtField   time.Time `db:"started_at"`
-	CompletedAtField time.Time `db:"completed_at"`
+	QueueNameField               string    `db:"queue_name"`
+	TaskIDField                  string    `db:"task_id"`
+	ParametersField              string    `db:"parameters"`
+	StatusField                  string    `db:"status"`
+	OutputField                  string    `db:"output"`
+	DetailsField                 string    `db:"details"`
+	ErrorMessageField            string    `db:"error_message"`
+	AttemptsField                int       `db:"attempts"`
+	PriorityField                int       `db:"priority"`
+	StartedAtField               time.Time `db:"started_at"`
+	CompletedAtField             time.Time `db:"completed_at"`
+	AvailableAtField             time.Time `db:"available_at"`
+	RetryPolicyField             string    `db:"retry_policy"`
+	UniqueKeyField               string    `db:"unique_key"`
+	UniqueUntilField             time.Time `db:"unique_until"`
+	DependsOnField               string    `db:"depends_on"`
+	DependencyFailurePolicyField string    `db:"dependency_failure_policy"`
+	BatchIDField                 string    `db:"batch_id"`
+	DeadLetterQueueNameField     string    `db:"dead_letter_queue_name"`
+	DeadLetteredAtField          time.Time `db:"dead_lettered_at"`
+	TimeoutSecondsField          int       `db:"timeout_seconds"`
+	LeaseOwnerField              string    `db:"lease_owner"`
+	LeaseExpiresAtField          time.Time `db:"lease_expires_at"`
+	WorkerIDField                string    `db:"worker_id"`
+	ClaimedAtField               time.Time `db:"claimed_at"`
+	CancelRequestedAtField       time.Time `db:"cancel_requested_at"`
 
 	CreatedAtField orm.CreatedAt
 	UpdatedAtField orm.UpdatedAt
 	soft_delete.SoftDeletesMaxDate
+
+	// permanentFailure is set during processing when the current attempt
+	// failed in a way that must not be retried. It is not persisted.
+	permanentFailure bool
 }
 
 var _ TaskQueueInterface = (*taskQueue)(nil)
 
+// taskQueueDetailsMu guards the details of the queued tasks, which the
+// handler of a queued task may append to while its worker reads them (i.e.
+// once the handler timed out). The queued tasks are copied by value, so
+// they cannot hold a mutex themselves.
+var taskQueueDetailsMu sync.Mutex
+
 // == CONSTRUCTORS =============================================================
 
 // NewTaskQueue creates a new task queue
@@ -109,11 +192,15 @@ func NewTaskQueue(queueName ...string) TaskQueueInterface {
 		SetStatus(TaskQueueStatusQueued).
 		SetQueueName(name).
 		SetAttempts(0).
+		SetPriority(DefaultPriority).
 		SetOutput("").
 		SetDetails("").
 		SetParameters("{}").
 		SetStartedAt(time.Time{}).
 		SetCompletedAt(time.Time{}).
+		SetAvailableAt(time.Time{}).
+		SetLeaseExpiresAt(time.Time{}).
+		SetClaimedAt(time.Time{}).
 		SetCreatedAt(carbon.Now(carbon.UTC).StdTime()).
 		SetUpdatedAt(carbon.Now(carbon.UTC).StdTime()).
 		SetSoftDeletedAt(carbon.Parse(MAX_DATETIME, carbon.UTC).StdTime())
@@ -130,13 +217,42 @@ func NewTaskQueueFromExistingData(data map[string]string) TaskQueueInterface {
 	o.SetStatus(data[COLUMN_STATUS])
 	o.SetOutput(data[COLUMN_OUTPUT])
 	o.SetDetails(data[COLUMN_DETAILS])
+	o.SetErrorMessage(data[COLUMN_ERROR_MESSAGE])
 	o.SetAttempts(cast.ToInt(data[COLUMN_ATTEMPTS]))
+	o.SetPriority(cast.ToInt(data[COLUMN_PRIORITY]))
 	if v, ok := data[COLUMN_STARTED_AT]; ok {
 		o.SetStartedAt(parseTime(v))
 	}
 	if v, ok := data[COLUMN_COMPLETED_AT]; ok {
 		o.SetCompletedAt(parseTime(v))
 	}
+	if v, ok := data[COLUMN_AVAILABLE_AT]; ok {
+		o.SetAvailableAt(parseTime(v))
+	}
+	o.SetRetryPolicy(retryPolicyFromJSON(data[COLUMN_RETRY_POLICY]))
+	o.SetUniqueKey(data[COLUMN_UNIQUE_KEY])
+	o.DependsOnField = data[COLUMN_DEPENDS_ON]
+	o.SetDependencyFailurePolicy(data[COLUMN_DEPENDENCY_FAILURE_POLICY])
+	o.SetBatchID(data[COLUMN_BATCH_ID])
+	o.SetDeadLetterQueueName(data[COLUMN_DEAD_LETTER_QUEUE_NAME])
+	o.SetTimeoutSeconds(cast.ToInt(data[COLUMN_TIMEOUT_SECONDS]))
+	o.SetLeaseOwner(data[COLUMN_LEASE_OWNER])
+	o.SetWorkerID(data[COLUMN_WORKER_ID])
+	if v, ok := data[COLUMN_CLAIMED_AT]; ok {
+		o.SetClaimedAt(parseTime(v))
+	}
+	if v, ok := data[COLUMN_CANCEL_REQUESTED_AT]; ok {
+		o.CancelRequestedAtField = parseTime(v)
+	}
+	if v, ok := data[COLUMN_LEASE_EXPIRES_AT]; ok {
+		o.SetLeaseExpiresAt(parseTime(v))
+	}
+	if v, ok := data[COLUMN_DEAD_LETTERED_AT]; ok {
+		o.SetDeadLetteredAt(parseTime(v))
+	}
+	if v, ok := data[COLUMN_UNIQUE_UNTIL]; ok {
+		o.SetUniqueUntil(parseTime(v))
+	}
 	if v, ok := data[COLUMN_CREATED_AT]; ok {
 		o.SetCreatedAt(parseTime(v))
 	}
@@ -155,6 +271,13 @@ func (o *taskQueue) IsCanceled() bool {
 	return o.GetStatus() == TaskQueueStatusCanceled
 }
 
+// IsCancelRequested returns true when the queued task is running, and was
+// asked to stop (see TaskQueueCancel). It is canceled once its worker
+// stopped it.
+func (o *taskQueue) IsCancelRequested() bool {
+	return o.IsRunning() && !isNullTime(o.GetCancelRequestedAt())
+}
+
 func (o *taskQueue) IsDeleted() bool {
 	return o.GetStatus() == TaskQueueStatusDeleted
 }
@@ -194,6 +317,55 @@ func (o *taskQueue) SetAttempts(attempts int) TaskQueueInterface {
 	return o
 }
 
+func (o *taskQueue) GetAvailableAt() time.Time {
+	return o.AvailableAtField
+}
+
+func (o *taskQueue) GetAvailableAtCarbon() *carbon.Carbon {
+	return carbon.CreateFromStdTime(o.AvailableAtField)
+}
+
+// SetAvailableAt sets the time before which the queued task is not claimed.
+// A zero time means the task is available immediately.
+func (o *taskQueue) SetAvailableAt(availableAt time.Time) TaskQueueInterface {
+	o.AvailableAtField = availableAt
+	return o
+}
+
+// GetBatchID returns the ID of the batch the queued task belongs to,
+// or an empty string if it is not part of a batch
+func (o *taskQueue) GetBatchID() string {
+	return o.BatchIDField
+}
+
+func (o *taskQueue) SetBatchID(batchID string) TaskQueueInterface {
+	o.BatchIDField = batchID
+	return o
+}
+
+// GetCancelRequestedAt returns the time TaskQueueCancel asked the worker
+// processing the queued task to stop it. Zero if it was not asked to.
+// It is only set by TaskQueueCancel, so it is not saved with the other
+// fields of the queued task.
+func (o *taskQueue) GetCancelRequestedAt() time.Time {
+	return o.CancelRequestedAtField
+}
+
+// GetClaimedAt returns the time the queued task was last claimed by a
+// worker (see GetWorkerID). Zero if it was never claimed.
+func (o *taskQueue) GetClaimedAt() time.Time {
+	return o.ClaimedAtField
+}
+
+func (o *taskQueue) GetClaimedAtCarbon() *carbon.Carbon {
+	return carbon.CreateFromStdTime(o.ClaimedAtField)
+}
+
+func (o *taskQueue) SetClaimedAt(claimedAt time.Time) TaskQueueInterface {
+	o.ClaimedAtField = claimedAt
+	return o
+}
+
 func (o *taskQueue) GetCompletedAt() time.Time {
 	return o.CompletedAtField
 }
@@ -220,27 +392,116 @@ func (o *taskQueue) SetCreatedAt(createdAt time.Time) TaskQueueInterface {
 	return o
 }
 
+// GetDeadLetterQueueName returns the name of the dead letter queue the
+// queued task was moved to, after it failed for good. Returns an empty
+// string if the queued task is not a dead letter.
+func (o *taskQueue) GetDeadLetterQueueName() string {
+	return o.DeadLetterQueueNameField
+}
+
+func (o *taskQueue) SetDeadLetterQueueName(deadLetterQueueName string) TaskQueueInterface {
+	o.DeadLetterQueueNameField = deadLetterQueueName
+	return o
+}
+
+// GetDeadLetteredAt returns when the queued task was moved to
+// its dead letter queue
+func (o *taskQueue) GetDeadLetteredAt() time.Time {
+	return o.DeadLetteredAtField
+}
+
+func (o *taskQueue) GetDeadLetteredAtCarbon() *carbon.Carbon {
+	return carbon.CreateFromStdTime(o.DeadLetteredAtField)
+}
+
+func (o *taskQueue) SetDeadLetteredAt(deadLetteredAt time.Time) TaskQueueInterface {
+	o.DeadLetteredAtField = deadLetteredAt
+	return o
+}
+
+// GetDependencyFailurePolicy returns what happens to the queued task when
+// one of the tasks it depends on fails. One of DependencyFailureCancel
+// (default) or DependencyFailureBlock.
+func (o *taskQueue) GetDependencyFailurePolicy() string {
+	if o.DependencyFailurePolicyField == "" {
+		return DependencyFailureCancel
+	}
+	return o.DependencyFailurePolicyField
+}
+
+func (o *taskQueue) SetDependencyFailurePolicy(policy string) TaskQueueInterface {
+	o.DependencyFailurePolicyField = policy
+	return o
+}
+
+// GetDependsOn returns the IDs of the queued tasks which must succeed
+// before this queued task can be claimed
+func (o *taskQueue) GetDependsOn() []string {
+	if o.DependsOnField == "" {
+		return []string{}
+	}
+
+	var taskQueueIDs []string
+	if err := json.Unmarshal([]byte(o.DependsOnField), &taskQueueIDs); err != nil {
+		return []string{}
+	}
+	return taskQueueIDs
+}
+
+func (o *taskQueue) SetDependsOn(taskQueueIDs []string) TaskQueueInterface {
+	if len(taskQueueIDs) == 0 {
+		o.DependsOnField = ""
+		return o
+	}
+
+	taskQueueIDsBytes, err := json.Marshal(taskQueueIDs)
+	if err != nil {
+		return o
+	}
+	o.DependsOnField = string(taskQueueIDsBytes)
+	return o
+}
+
 // AppendDetails appends details to the queued task
 // !!! warning does not auto-save it for performance reasons
 func (o *taskQueue) AppendDetails(details string) TaskQueueInterface {
 	ts := carbon.Now().Format("Y-m-d H:i:s")
-	text := o.GetDetails()
-	if text != "" {
-		text += "\n"
+
+	taskQueueDetailsMu.Lock()
+	defer taskQueueDetailsMu.Unlock()
+
+	if o.DetailsField != "" {
+		o.DetailsField += "\n"
 	}
-	text += ts + " : " + details
-	return o.SetDetails(text)
+	o.DetailsField += ts + " : " + details
+	return o
 }
 
 func (o *taskQueue) GetDetails() string {
+	taskQueueDetailsMu.Lock()
+	defer taskQueueDetailsMu.Unlock()
 	return o.DetailsField
 }
 
 func (o *taskQueue) SetDetails(details string) TaskQueueInterface {
+	taskQueueDetailsMu.Lock()
+	defer taskQueueDetailsMu.Unlock()
 	o.DetailsField = details
 	return o
 }
 
+// GetErrorMessage returns the error of the last failed attempt of the
+// queued task, i.e. the error returned by TaskHandlerWithError.HandleE.
+// Empty when the task has not failed, or succeeded since.
+func (o *taskQueue) GetErrorMessage() string {
+	return o.ErrorMessageField
+}
+
+func (o *taskQueue) SetErrorMessage(errorMessage string) TaskQueueInterface {
+	o.ErrorMessageField = errorMessage
+	return o
+}
+
 func (o *taskQueue) GetQueueName() string {
 	return o.QueueNameField
 }
@@ -259,6 +520,33 @@ func (o *taskQueue) SetID(id string) TaskQueueInterface {
 	return o
 }
 
+// GetLeaseExpiresAt returns the time the lease of the worker processing
+// the queued task expires, unless it is extended with a heartbeat
+// (see TaskQueueHeartbeat). Zero when the task is not leased.
+func (o *taskQueue) GetLeaseExpiresAt() time.Time {
+	return o.LeaseExpiresAtField
+}
+
+func (o *taskQueue) GetLeaseExpiresAtCarbon() *carbon.Carbon {
+	return carbon.CreateFromStdTime(o.LeaseExpiresAtField)
+}
+
+func (o *taskQueue) SetLeaseExpiresAt(leaseExpiresAt time.Time) TaskQueueInterface {
+	o.LeaseExpiresAtField = leaseExpiresAt
+	return o
+}
+
+// GetLeaseOwner returns the worker holding the lease of the queued task,
+// while it is processed. Empty when the task is not leased.
+func (o *taskQueue) GetLeaseOwner() string {
+	return o.LeaseOwnerField
+}
+
+func (o *taskQueue) SetLeaseOwner(leaseOwner string) TaskQueueInterface {
+	o.LeaseOwnerField = leaseOwner
+	return o
+}
+
 func (o *taskQueue) GetOutput() string {
 	return o.OutputField
 }
@@ -277,17 +565,31 @@ func (o *taskQueue) SetParameters(parameters string) TaskQueueInterface {
 	return o
 }
 
+// ParametersMap returns the parameters of the queued task by name. String
+// values are returned as they are, null values as empty strings, and other
+// values (numbers, booleans, objects and arrays) as their JSON encoding.
+// Use DecodeParams to decode the parameters into a typed struct instead.
 func (o *taskQueue) ParametersMap() (map[string]string, error) {
 	// Handle empty string parameters
 	if o.GetParameters() == "" {
 		return map[string]string{}, nil
 	}
 
-	var parameters map[string]string
-	jsonErr := json.Unmarshal([]byte(o.GetParameters()), &parameters)
+	var rawParameters map[string]json.RawMessage
+	jsonErr := json.Unmarshal([]byte(o.GetParameters()), &rawParameters)
 	if jsonErr != nil {
 		return map[string]string{}, jsonErr
 	}
+
+	parameters := make(map[string]string, len(rawParameters))
+	for name, rawValue := range rawParameters {
+		var value string
+		if json.Unmarshal(rawValue, &value) == nil {
+			parameters[name] = value
+		} else {
+			parameters[name] = string(rawValue)
+		}
+	}
 	return parameters, nil
 }
 
@@ -299,6 +601,28 @@ func (o *taskQueue) SetParametersMap(parameters map[string]string) (TaskQueueInt
 	return o.SetParameters(string(parametersBytes)), nil
 }
 
+// GetPriority returns the priority of the queued task.
+// Tasks with a higher priority are claimed first.
+func (o *taskQueue) GetPriority() int {
+	return o.PriorityField
+}
+
+func (o *taskQueue) SetPriority(priority int) TaskQueueInterface {
+	o.PriorityField = priority
+	return o
+}
+
+// GetRetryPolicy returns the retry policy set for this queued task, which
+// overrides the policy of the task definition. Returns nil if not set.
+func (o *taskQueue) GetRetryPolicy() *RetryPolicy {
+	return retryPolicyFromJSON(o.RetryPolicyField)
+}
+
+func (o *taskQueue) SetRetryPolicy(policy *RetryPolicy) TaskQueueInterface {
+	o.RetryPolicyField = retryPolicyToJSON(policy)
+	return o
+}
+
 func (o *taskQueue) GetSoftDeletedAt() time.Time {
 	return o.SoftDeletesMaxDate.SoftDeletedAt
 }
@@ -343,6 +667,39 @@ func (o *taskQueue) SetTaskID(taskID string) TaskQueueInterface {
 	return o
 }
 
+// GetTimeoutSeconds returns the execution timeout set for this queued task,
+// which overrides the timeout of the task definition. 0 if not set.
+func (o *taskQueue) GetTimeoutSeconds() int {
+	return o.TimeoutSecondsField
+}
+
+func (o *taskQueue) SetTimeoutSeconds(timeoutSeconds int) TaskQueueInterface {
+	o.TimeoutSecondsField = timeoutSeconds
+	return o
+}
+
+// GetUniqueKey returns the unique key of the queued task, which prevents
+// duplicate tasks with the same key from being enqueued. Empty if not set.
+func (o *taskQueue) GetUniqueKey() string {
+	return o.UniqueKeyField
+}
+
+func (o *taskQueue) SetUniqueKey(uniqueKey string) TaskQueueInterface {
+	o.UniqueKeyField = uniqueKey
+	return o
+}
+
+// GetUniqueUntil returns the time until which the unique key is held.
+// A zero time means the key is held while the task is queued or running.
+func (o *taskQueue) GetUniqueUntil() time.Time {
+	return o.UniqueUntilField
+}
+
+func (o *taskQueue) SetUniqueUntil(uniqueUntil time.Time) TaskQueueInterface {
+	o.UniqueUntilField = uniqueUntil
+	return o
+}
+
 func (o *taskQueue) GetUpdatedAt() time.Time {
 	return o.UpdatedAtField.UpdatedAt
 }
@@ -355,3 +712,23 @@ func (o *taskQueue) SetUpdatedAt(updatedAt time.Time) TaskQueueInterface {
 	o.UpdatedAtField.UpdatedAt = updatedAt
 	return o
 }
+
+// GetWorkerID returns the ID of the worker which last claimed the queued
+// task. Unlike the lease owner, it is kept once the task is finished.
+// Empty if it was never claimed.
+func (o *taskQueue) GetWorkerID() string {
+	return o.WorkerIDField
+}
+
+func (o *taskQueue) SetWorkerID(workerID string) TaskQueueInterface {
+	o.WorkerIDField = workerID
+	return o
+}
+
+func (o *taskQueue) setPermanentFailure(permanentFailure bool) {
+	o.permanentFailure = permanentFailure
+}
+
+func (o *taskQueue) isPermanentFailure() bool {
+	return o.permanentFailure
+}
@@ -15,6 +15,22 @@ type taskQueueQuery struct {
 var _ TaskQueueQueryInterface = (*taskQueueQuery)(nil)
 
 func (q *taskQueueQuery) Validate() error {
+	if q.HasAvailableAtLte() && q.AvailableAtLte() == "" {
+		return errors.New("queue query. available_at_lte cannot be empty")
+	}
+
+	if q.HasBatchID() && q.BatchID() == "" {
+		return errors.New("queue query. batch_id cannot be empty")
+	}
+
+	if q.HasClaimedAtGte() && q.ClaimedAtGte() == "" {
+		return errors.New("queue query. claimed_at_gte cannot be empty")
+	}
+
+	if q.HasClaimedAtLte() && q.ClaimedAtLte() == "" {
+		return errors.New("queue query. claimed_at_lte cannot be empty")
+	}
+
 	if q.HasCreatedAtGte() && q.CreatedAtGte() == "" {
 		return errors.New("queue query. created_at_gte cannot be empty")
 	}
@@ -23,6 +39,10 @@ func (q *taskQueueQuery) Validate() error {
 		return errors.New("queue query. created_at_lte cannot be empty")
 	}
 
+	if q.HasDeadLetterQueueName() && q.DeadLetterQueueName() == "" {
+		return errors.New("queue query. dead_letter_queue_name cannot be empty")
+	}
+
 	if q.HasID() && q.ID() == "" {
 		return errors.New("queue query. id cannot be empty")
 	}
@@ -51,6 +71,10 @@ func (q *taskQueueQuery) Validate() error {
 		return errors.New("queue query. task_id cannot be empty")
 	}
 
+	if q.HasWorkerID() && q.WorkerID() == "" {
+		return errors.New("queue query. worker_id cannot be empty")
+	}
+
 	return nil
 }
 
@@ -67,6 +91,58 @@ func (q *taskQueueQuery) SetColumns(columns []string) TaskQueueQueryInterface {
 	return q
 }
 
+func (q *taskQueueQuery) HasAvailableAtLte() bool {
+	return q.hasProperty("available_at_lte")
+}
+
+func (q *taskQueueQuery) AvailableAtLte() string {
+	return q.properties["available_at_lte"].(string)
+}
+
+func (q *taskQueueQuery) SetAvailableAtLte(availableAtLte string) TaskQueueQueryInterface {
+	q.properties["available_at_lte"] = availableAtLte
+	return q
+}
+
+func (q *taskQueueQuery) HasBatchID() bool {
+	return q.hasProperty("batch_id")
+}
+
+func (q *taskQueueQuery) BatchID() string {
+	return q.properties["batch_id"].(string)
+}
+
+func (q *taskQueueQuery) SetBatchID(batchID string) TaskQueueQueryInterface {
+	q.properties["batch_id"] = batchID
+	return q
+}
+
+func (q *taskQueueQuery) HasClaimedAtGte() bool {
+	return q.hasProperty("claimed_at_gte")
+}
+
+func (q *taskQueueQuery) ClaimedAtGte() string {
+	return q.properties["claimed_at_gte"].(string)
+}
+
+func (q *taskQueueQuery) SetClaimedAtGte(claimedAtGte string) TaskQueueQueryInterface {
+	q.properties["claimed_at_gte"] = claimedAtGte
+	return q
+}
+
+func (q *taskQueueQuery) HasClaimedAtLte() bool {
+	return q.hasProperty("claimed_at_lte")
+}
+
+func (q *taskQueueQuery) ClaimedAtLte() string {
+	return q.properties["claimed_at_lte"].(string)
+}
+
+func (q *taskQueueQuery) SetClaimedAtLte(claimedAtLte string) TaskQueueQueryInterface {
+	q.properties["claimed_at_lte"] = claimedAtLte
+	return q
+}
+
 func (q *taskQueueQuery) HasCountOnly() bool {
 	return q.hasProperty("count_only")
 }
@@ -110,6 +186,19 @@ func (q *taskQueueQuery) SetCreatedAtLte(createdAtLte string) TaskQueueQueryInte
 	return q
 }
 
+func (q *taskQueueQuery) HasDeadLetterQueueName() bool {
+	return q.hasProperty("dead_letter_queue_name")
+}
+
+func (q *taskQueueQuery) DeadLetterQueueName() string {
+	return q.properties["dead_letter_queue_name"].(string)
+}
+
+func (q *taskQueueQuery) SetDeadLetterQueueName(deadLetterQueueName string) TaskQueueQueryInterface {
+	q.properties["dead_letter_queue_name"] = deadLetterQueueName
+	return q
+}
+
 func (q *taskQueueQuery) HasID() bool {
 	return q.hasProperty("id")
 }
@@ -256,6 +345,19 @@ func (q *taskQueueQuery) SetStatusIn(statusIn []string) TaskQueueQueryInterface
 	return q
 }
 
+func (q *taskQueueQuery) HasWorkerID() bool {
+	return q.hasProperty("worker_id")
+}
+
+func (q *taskQueueQuery) WorkerID() string {
+	return q.properties["worker_id"].(string)
+}
+
+func (q *taskQueueQuery) SetWorkerID(workerID string) TaskQueueQueryInterface {
+	q.properties["worker_id"] = workerID
+	return q
+}
+
 func (q *taskQueueQuery) hasProperty(key string) bool {
 	return q.properties[key] != nil
 }
@@ -6,6 +6,22 @@ type TaskQueueQueryInterface interface {
 	Columns() []string
 	SetColumns(columns []string) TaskQueueQueryInterface
 
+	HasAvailableAtLte() bool
+	AvailableAtLte() string
+	SetAvailableAtLte(availableAtLte string) TaskQueueQueryInterface
+
+	HasBatchID() bool
+	BatchID() string
+	SetBatchID(batchID string) TaskQueueQueryInterface
+
+	HasClaimedAtGte() bool
+	ClaimedAtGte() string
+	SetClaimedAtGte(claimedAtGte string) TaskQueueQueryInterface
+
+	HasClaimedAtLte() bool
+	ClaimedAtLte() string
+	SetClaimedAtLte(claimedAtLte string) TaskQueueQueryInterface
+
 	HasCountOnly() bool
 	IsCountOnly() bool
 	SetCountOnly(countOnly bool) TaskQueueQueryInterface
@@ -18,6 +34,10 @@ type TaskQueueQueryInterface interface {
 	CreatedAtLte() string
 	SetCreatedAtLte(createdAtLte string) TaskQueueQueryInterface
 
+	HasDeadLetterQueueName() bool
+	DeadLetterQueueName() string
+	SetDeadLetterQueueName(deadLetterQueueName string) TaskQueueQueryInterface
+
 	HasID() bool
 	ID() string
 	SetID(id string) TaskQueueQueryInterface
@@ -61,4 +81,8 @@ type TaskQueueQueryInterface interface {
 	HasQueueName() bool
 	QueueName() string
 	SetQueueName(queueName string) TaskQueueQueryInterface
+
+	HasWorkerID() bool
+	WorkerID() string
+	SetWorkerID(workerID string) TaskQueueQueryInterface
 }
@@ -219,6 +219,54 @@ func TestTaskQueueQuery_CountOnly(t *testing.T) {
 	}
 }
 
+func TestTaskQueueQuery_AvailableAtLte(t *testing.T) {
+	query := TaskQueueQuery()
+
+	// Test default state
+	if query.HasAvailableAtLte() {
+		t.Error("HasAvailableAtLte: Expected false for new query")
+	}
+
+	// Test setting available_at_lte
+	testDate := "2023-06-01 12:00:00"
+	result := query.SetAvailableAtLte(testDate)
+	if result != query {
+		t.Error("SetAvailableAtLte: Expected method to return the same query instance")
+	}
+	if !query.HasAvailableAtLte() {
+		t.Error("HasAvailableAtLte: Expected true after setting available_at_lte")
+	}
+	if query.AvailableAtLte() != testDate {
+		t.Errorf("AvailableAtLte: Expected '%s', got '%s'", testDate, query.AvailableAtLte())
+	}
+}
+
+func TestTaskQueueQuery_BatchID(t *testing.T) {
+	query := TaskQueueQuery()
+
+	// Test default state
+	if query.HasBatchID() {
+		t.Error("HasBatchID: Expected false for new query")
+	}
+
+	// Test setting batch_id
+	result := query.SetBatchID("BATCH_1")
+	if result != query {
+		t.Error("SetBatchID: Expected method to return the same query instance")
+	}
+	if !query.HasBatchID() {
+		t.Error("HasBatchID: Expected true after setting batch_id")
+	}
+	if query.BatchID() != "BATCH_1" {
+		t.Errorf("BatchID: Expected 'BATCH_1', got '%s'", query.BatchID())
+	}
+
+	// Test validation of an empty batch_id
+	if err := TaskQueueQuery().SetBatchID("").Validate(); err == nil {
+		t.Error("Validate: Expected an error for an empty batch_id")
+	}
+}
+
 func TestTaskQueueQuery_CreatedAtGte(t *testing.T) {
 	query := TaskQueueQuery()
 
@@ -580,3 +628,81 @@ func TestTaskQueueQuery_ChainedSetters(t *testing.T) {
 		t.Error("ChainedSetters: QueueName not set correctly")
 	}
 }
+
+func TestTaskQueueQuery_DeadLetterQueueName(t *testing.T) {
+	query := TaskQueueQuery()
+
+	// Test default state
+	if query.HasDeadLetterQueueName() {
+		t.Error("HasDeadLetterQueueName: Expected false for new query")
+	}
+
+	// Test setting dead_letter_queue_name
+	result := query.SetDeadLetterQueueName("poison")
+	if result != query {
+		t.Error("SetDeadLetterQueueName: Expected method to return the same query instance")
+	}
+	if !query.HasDeadLetterQueueName() {
+		t.Error("HasDeadLetterQueueName: Expected true after setting dead_letter_queue_name")
+	}
+	if query.DeadLetterQueueName() != "poison" {
+		t.Errorf("DeadLetterQueueName: Expected 'poison', got '%s'", query.DeadLetterQueueName())
+	}
+
+	// Test validation of an empty dead_letter_queue_name
+	if err := TaskQueueQuery().SetDeadLetterQueueName("").Validate(); err == nil {
+		t.Error("Validate: Expected an error for an empty dead_letter_queue_name")
+	}
+}
+
+func TestTaskQueueQuery_ClaimedAt(t *testing.T) {
+	query := TaskQueueQuery()
+
+	// Test default state
+	if query.HasClaimedAtGte() || query.HasClaimedAtLte() {
+		t.Error("HasClaimedAt: Expected false for new query")
+	}
+
+	// Test setting claimed_at bounds
+	query.SetClaimedAtGte("2023-01-01 00:00:00").SetClaimedAtLte("2023-01-31 23:59:59")
+	if query.ClaimedAtGte() != "2023-01-01 00:00:00" {
+		t.Errorf("ClaimedAtGte: Expected '2023-01-01 00:00:00', got '%s'", query.ClaimedAtGte())
+	}
+	if query.ClaimedAtLte() != "2023-01-31 23:59:59" {
+		t.Errorf("ClaimedAtLte: Expected '2023-01-31 23:59:59', got '%s'", query.ClaimedAtLte())
+	}
+
+	// Test validation of empty bounds
+	if err := TaskQueueQuery().SetClaimedAtGte("").Validate(); err == nil {
+		t.Error("Validate: Expected an error for an empty claimed_at_gte")
+	}
+	if err := TaskQueueQuery().SetClaimedAtLte("").Validate(); err == nil {
+		t.Error("Validate: Expected an error for an empty claimed_at_lte")
+	}
+}
+
+func TestTaskQueueQuery_WorkerID(t *testing.T) {
+	query := TaskQueueQuery()
+
+	// Test default state
+	if query.HasWorkerID() {
+		t.Error("HasWorkerID: Expected false for new query")
+	}
+
+	// Test setting worker_id
+	result := query.SetWorkerID("worker-1")
+	if result != query {
+		t.Error("SetWorkerID: Expected method to return the same query instance")
+	}
+	if !query.HasWorkerID() {
+		t.Error("HasWorkerID: Expected true after setting worker_id")
+	}
+	if query.WorkerID() != "worker-1" {
+		t.Errorf("WorkerID: Expected 'worker-1', got '%s'", query.WorkerID())
+	}
+
+	// Test validation of an empty worker_id
+	if err := TaskQueueQuery().SetWorkerID("").Validate(); err == nil {
+		t.Error("Validate: Expected an error for an empty worker_id")
+	}
+}
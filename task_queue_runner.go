@@ -9,11 +9,31 @@ import (
 )
 
 type TaskQueueRunnerOptions struct {
+	// IntervalSeconds is how often a started runner polls the queue
+	// (default: 10). Tasks enqueued through the store of the runner wake it
+	// up right away (see NotifierInterface), so polling is the fallback.
+	// With MaxIntervalSeconds set, it is the minimum interval.
 	IntervalSeconds int
-	UnstuckMinutes  int
-	QueueName       string
-	Logger          *log.Logger
-	MaxConcurrency  int // 0 or 1 = serial, >1 = concurrent (default: 1)
+	// MaxIntervalSeconds enables an adaptive interval: while polls find no
+	// tasks, the interval doubles from IntervalSeconds up to
+	// MaxIntervalSeconds, and it is reset as soon as a task is claimed
+	// (default: 0, polling at a fixed interval)
+	MaxIntervalSeconds int
+	// Deprecated: Running tasks are leased, and tasks whose lease expired
+	// are reaped on each run instead. UnstuckMinutes is no longer used.
+	UnstuckMinutes int
+	QueueName      string
+	Logger         *log.Logger
+	MaxConcurrency int // 0 or 1 = serial, >1 = concurrent (default: 1)
+	// WorkerID identifies the runner on the tasks it claims, and owns their
+	// leases (default: host name, process ID and a random suffix)
+	WorkerID string
+	// Version of the application running the runner, shown in the workers
+	// table (optional)
+	Version string
+	// HeartbeatSeconds is how often a started runner reports it is alive in
+	// the workers table (default: 30)
+	HeartbeatSeconds int
 }
 
 type TaskQueueRunnerInterface interface {
@@ -21,15 +41,25 @@ type TaskQueueRunnerInterface interface {
 	Stop()
 	IsRunning() bool
 	RunOnce(ctx context.Context) error
+	// CurrentInterval returns the interval the runner waits between polls,
+	// which grows while the queue is idle (see MaxIntervalSeconds)
+	CurrentInterval() time.Duration
 }
 
 type taskQueueRunner struct {
-	store     StoreInterface
-	opts      TaskQueueRunnerOptions
-	running   atomic.Bool
-	stopCh    chan struct{}
-	taskWg    sync.WaitGroup // Tracks spawned task goroutines
-	semaphore chan struct{}  // Concurrency limiter
+	store      StoreInterface
+	opts       TaskQueueRunnerOptions
+	running    atomic.Bool
+	stopping   atomic.Bool // Set by Stop, so no more tasks are claimed
+	stopCh     chan struct{}
+	loopWg     sync.WaitGroup // Tracks the run loop goroutine
+	taskWg     sync.WaitGroup // Tracks spawned task goroutines
+	semaphore  chan struct{}  // Concurrency limiter
+	backoff    *pollBackoff   // Interval between polls
+	inFlight   atomic.Int64   // Number of tasks being processed
+	workerMu   sync.Mutex
+	workerStop chan struct{}  // Stops the worker heartbeat goroutine
+	workerWg   sync.WaitGroup // Tracks the worker heartbeat goroutine
 }
 
 func NewTaskQueueRunner(store StoreInterface, opts TaskQueueRunnerOptions) TaskQueueRunnerInterface {
@@ -50,11 +80,20 @@ func NewTaskQueueRunner(store StoreInterface, opts TaskQueueRunnerOptions) TaskQ
 		opts.MaxConcurrency = 1
 	}
 
+	if opts.WorkerID == "" {
+		opts.WorkerID = newWorkerID()
+	}
+
+	if opts.HeartbeatSeconds <= 0 {
+		opts.HeartbeatSeconds = 30
+	}
+
 	return &taskQueueRunner{
 		store:     store,
 		opts:      opts,
 		stopCh:    make(chan struct{}, 1),
 		semaphore: make(chan struct{}, opts.MaxConcurrency),
+		backoff:   newPollBackoff(time.Duration(opts.IntervalSeconds)*time.Second, time.Duration(opts.MaxIntervalSeconds)*time.Second),
 	}
 }
 
@@ -63,9 +102,17 @@ func (r *taskQueueRunner) Start(ctx context.Context) {
 		return
 	}
 
+	r.stopping.Store(false)
+	r.startWorker(ctx)
+
+	// Tasks enqueued meanwhile wake the runner up, rather than waiting for
+	// the next poll
+	wakeups, unsubscribe := r.store.GetNotifier().Subscribe(normalizeQueueName(r.opts.QueueName))
+
+	r.loopWg.Add(1)
 	go func() {
-		ticker := time.NewTicker(time.Duration(r.opts.IntervalSeconds) * time.Second)
-		defer ticker.Stop()
+		defer r.loopWg.Done()
+		defer unsubscribe()
 		defer r.running.Store(false)
 
 		for {
@@ -73,41 +120,75 @@ func (r *taskQueueRunner) Start(ctx context.Context) {
 				return
 			}
 
-			if err := r.RunOnce(ctx); err != nil {
+			claimed, err := r.runOnce(ctx)
+			if err != nil {
 				r.logf("TaskQueueRunner: RunOnce error: %v", err)
 			}
 
+			timer := time.NewTimer(r.backoff.next(claimed > 0))
+
 			select {
-			case <-ticker.C:
+			case <-timer.C:
+				continue
+			case <-wakeups:
+				timer.Stop()
 				continue
 			case <-ctx.Done():
+				timer.Stop()
 				return
 			case <-r.stopCh:
+				timer.Stop()
 				return
 			}
 		}
 	}()
 }
 
+// Stop stops the runner from claiming more tasks, and waits for the tasks
+// in flight to finish
 func (r *taskQueueRunner) Stop() {
 	if !r.running.Load() {
 		return
 	}
 
+	r.stopping.Store(true)
+
 	select {
 	case r.stopCh <- struct{}{}:
 	default:
 	}
 
-	// Wait for all spawned task goroutines to complete
+	// Wait for the run loop to stop claiming, then for all spawned task
+	// goroutines to complete
+	r.loopWg.Wait()
 	r.taskWg.Wait()
+
+	r.stopWorker()
 }
 
 func (r *taskQueueRunner) IsRunning() bool {
 	return r.running.Load()
 }
 
+func (r *taskQueueRunner) CurrentInterval() time.Duration {
+	return r.backoff.interval()
+}
+
+// RunOnce reaps the tasks of the queue whose lease expired, then claims
+// and processes the queued tasks until none is left.
 func (r *taskQueueRunner) RunOnce(ctx context.Context) error {
+	_, err := r.runOnce(ctx)
+	return err
+}
+
+// runOnce is RunOnce, returning the number of tasks claimed
+func (r *taskQueueRunner) runOnce(ctx context.Context) (int, error) {
+	ctx = ContextWithWorkerID(ctx, r.opts.WorkerID)
+
+	if _, err := r.store.TaskQueueReapExpiredLeases(ctx, normalizeQueueName(r.opts.QueueName)); err != nil {
+		r.logf("TaskQueueRunner: error reaping expired leases: %v", err)
+	}
+
 	if r.opts.MaxConcurrency == 1 {
 		return r.runOnceSerial(ctx)
 	}
@@ -115,76 +196,197 @@ func (r *taskQueueRunner) RunOnce(ctx context.Context) error {
 }
 
 // runOnceSerial processes tasks one at a time (original behavior)
-func (r *taskQueueRunner) runOnceSerial(ctx context.Context) error {
+func (r *taskQueueRunner) runOnceSerial(ctx context.Context) (int, error) {
 	queueName := normalizeQueueName(r.opts.QueueName)
+	claimed := 0
 
 	for {
 		if ctx != nil && ctx.Err() != nil {
-			return ctx.Err()
+			return claimed, ctx.Err()
+		}
+
+		if r.stopping.Load() {
+			return claimed, nil
 		}
 
 		queuedTask, err := r.store.TaskQueueClaimNext(ctx, queueName)
 		if err != nil {
-			return err
+			return claimed, err
 		}
 
 		if queuedTask == nil {
-			return nil
+			return claimed, nil
 		}
+		claimed++
 
-		_, err = r.store.TaskQueueProcessTask(ctx, queuedTask)
+		_, err = r.processTask(ctx, queuedTask)
 		if err != nil {
 			r.logf("TaskQueueRunner: error processing task %s: %v", queuedTask.GetID(), err)
 		}
 	}
 }
 
-// runOnceConcurrent processes multiple tasks concurrently up to MaxConcurrency limit
-func (r *taskQueueRunner) runOnceConcurrent(ctx context.Context) error {
+// runOnceConcurrent processes multiple tasks concurrently up to MaxConcurrency limit.
+// The free semaphore slots are filled with a single batch claim.
+func (r *taskQueueRunner) runOnceConcurrent(ctx context.Context) (int, error) {
 	queueName := normalizeQueueName(r.opts.QueueName)
+	claimed := 0
 
 	// Defer waiting for all spawned goroutines to complete
 	defer r.taskWg.Wait()
 
 	for {
 		if ctx != nil && ctx.Err() != nil {
-			return ctx.Err()
+			return claimed, ctx.Err()
 		}
 
-		// Claim next task (don't hold semaphore during claim)
-		queuedTask, err := r.store.TaskQueueClaimNext(ctx, queueName)
+		// Reserve the free semaphore slots (blocks if at max concurrency)
+		slots, err := r.acquireSlots(ctx)
 		if err != nil {
-			return err
+			return claimed, err
 		}
 
-		if queuedTask == nil {
-			return nil // Will wait for spawned goroutines due to defer
+		// Stop may have been requested while waiting for a free slot
+		if r.stopping.Load() {
+			r.releaseSlots(slots)
+			return claimed, nil
+		}
+
+		queuedTasks, err := r.store.TaskQueueClaimBatch(ctx, queueName, slots)
+		if err != nil {
+			r.releaseSlots(slots)
+			return claimed, err
+		}
+
+		// Release the slots left over by a short batch
+		r.releaseSlots(slots - len(queuedTasks))
+
+		if len(queuedTasks) == 0 {
+			return claimed, nil // Will wait for spawned goroutines due to defer
 		}
+		claimed += len(queuedTasks)
+
+		for _, queuedTask := range queuedTasks {
+			// Track the goroutine
+			r.taskWg.Add(1)
+
+			// Spawn goroutine to process the task
+			go func(task TaskQueueInterface) {
+				defer func() {
+					<-r.semaphore   // Release semaphore slot
+					r.taskWg.Done() // Mark goroutine as complete
+				}()
+
+				_, processErr := r.processTask(ctx, task)
+				if processErr != nil {
+					r.logf("TaskQueueRunner: error processing task %s: %v", task.GetID(), processErr)
+				}
+			}(queuedTask)
+		}
+	}
+}
 
-		// Acquire semaphore slot (blocks if at max concurrency)
+// acquireSlots waits for a free semaphore slot, then takes the other free
+// slots without waiting. Returns the number of slots taken.
+func (r *taskQueueRunner) acquireSlots(ctx context.Context) (int, error) {
+	select {
+	case r.semaphore <- struct{}{}:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+
+	slots := 1
+	for slots < cap(r.semaphore) {
 		select {
 		case r.semaphore <- struct{}{}:
-			// Got a slot, proceed
-		case <-ctx.Done():
-			return ctx.Err()
+			slots++
+		default:
+			return slots, nil
 		}
+	}
+	return slots, nil
+}
+
+// releaseSlots releases semaphore slots taken by acquireSlots
+func (r *taskQueueRunner) releaseSlots(slots int) {
+	for range slots {
+		<-r.semaphore
+	}
+}
+
+// processTask processes a claimed task, counting it as in flight meanwhile
+func (r *taskQueueRunner) processTask(ctx context.Context, queuedTask TaskQueueInterface) (bool, error) {
+	r.inFlight.Add(1)
+	defer r.inFlight.Add(-1)
+
+	return r.store.TaskQueueProcessTask(ctx, queuedTask)
+}
+
+// startWorker registers the runner in the workers table, and heartbeats
+// until the runner is stopped or ctx is done. The runner is deregistered
+// then.
+func (r *taskQueueRunner) startWorker(ctx context.Context) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	r.workerMu.Lock()
+	defer r.workerMu.Unlock()
+
+	if err := r.store.WorkerRegister(ctx, r.worker()); err != nil {
+		r.logf("TaskQueueRunner: error registering worker %s: %v", r.opts.WorkerID, err)
+	}
+
+	stop := make(chan struct{})
+	r.workerStop = stop
+	r.workerWg.Add(1)
 
-		// Track the goroutine
-		r.taskWg.Add(1)
+	go func() {
+		defer r.workerWg.Done()
+
+		ticker := time.NewTicker(time.Duration(r.opts.HeartbeatSeconds) * time.Second)
+		defer ticker.Stop()
 
-		// Spawn goroutine to process the task
-		go func(task TaskQueueInterface) {
-			defer func() {
-				<-r.semaphore   // Release semaphore slot
-				r.taskWg.Done() // Mark goroutine as complete
-			}()
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.store.WorkerHeartbeat(ctx, r.worker()); err != nil {
+					r.logf("TaskQueueRunner: error heartbeating worker %s: %v", r.opts.WorkerID, err)
+				}
+				continue
+			case <-ctx.Done():
+			case <-stop:
+			}
 
-			_, processErr := r.store.TaskQueueProcessTask(ctx, task)
-			if processErr != nil {
-				r.logf("TaskQueueRunner: error processing task %s: %v", task.GetID(), processErr)
+			// ctx may be done already, the worker is deregistered regardless
+			if err := r.store.WorkerDeregister(context.Background(), r.opts.WorkerID); err != nil {
+				r.logf("TaskQueueRunner: error deregistering worker %s: %v", r.opts.WorkerID, err)
 			}
-		}(queuedTask)
+			return
+		}
+	}()
+}
+
+// stopWorker stops the worker heartbeat goroutine, and waits until the
+// runner is deregistered
+func (r *taskQueueRunner) stopWorker() {
+	r.workerMu.Lock()
+	if r.workerStop != nil {
+		close(r.workerStop)
+		r.workerStop = nil
 	}
+	r.workerMu.Unlock()
+
+	r.workerWg.Wait()
+}
+
+// worker returns the current state of the runner as a worker
+func (r *taskQueueRunner) worker() WorkerInterface {
+	return NewWorker(r.opts.WorkerID).
+		SetQueueName(normalizeQueueName(r.opts.QueueName)).
+		SetMaxConcurrency(r.opts.MaxConcurrency).
+		SetInFlight(int(r.inFlight.Load())).
+		SetVersion(r.opts.Version)
 }
 
 func (r *taskQueueRunner) shouldContinue(ctx context.Context) bool {
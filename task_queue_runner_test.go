@@ -500,3 +500,348 @@ func (h *delayedHandler) Handle() bool {
 
 	return true
 }
+
+func TestTaskQueueRunner_RunOnceSkipsPausedQueue(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := new(testHandler)
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.QueuePause(ctx, DefaultQueueName); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, maxConcurrency := range []int{1, 2} {
+		runner := NewTaskQueueRunner(store, TaskQueueRunnerOptions{QueueName: DefaultQueueName, MaxConcurrency: maxConcurrency})
+		if err := runner.RunOnce(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	queued, err := store.TaskQueueCount(ctx, TaskQueueQuery().SetStatus(TaskQueueStatusQueued))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queued != 1 {
+		t.Fatalf("expected the task to stay queued while the queue is paused, got %d queued", queued)
+	}
+
+	if err := store.QueueResume(ctx, DefaultQueueName); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewTaskQueueRunner(store, TaskQueueRunnerOptions{QueueName: DefaultQueueName})
+	if err := runner.RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	success, err := store.TaskQueueCount(ctx, TaskQueueQuery().SetStatus(TaskQueueStatusSuccess))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if success != 1 {
+		t.Fatalf("expected the task to run once the queue is resumed, got %d success", success)
+	}
+}
+
+func TestTaskQueueRunner_RunOnceReapsExpiredLeases(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := new(testHandler)
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatal(err)
+	}
+
+	definition, err := store.TaskDefinitionFindByAlias(ctx, handler.Alias())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The worker which claimed the task crashed
+	queuedTask := createExpiredLeaseTask(t, store, definition.GetID(), &RetryPolicy{MaxAttempts: 2})
+
+	runner := NewTaskQueueRunner(store, TaskQueueRunnerOptions{QueueName: DefaultQueueName})
+	if err := runner.RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusSuccess {
+		t.Fatalf("expected the reaped task to be processed again, got status %s", dbTask.GetStatus())
+	}
+	if dbTask.GetAttempts() != 2 {
+		t.Fatalf("expected 2 attempts, got %d", dbTask.GetAttempts())
+	}
+}
+
+func TestTaskQueueRunner_RunOnceRecordsWorkerID(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := new(testHandler)
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatal(err)
+	}
+
+	queuedTask, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewTaskQueueRunner(store, TaskQueueRunnerOptions{
+		QueueName: DefaultQueueName,
+		WorkerID:  "runner-1",
+	})
+	if err := runner.RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	dbTask, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dbTask.GetStatus() != TaskQueueStatusSuccess {
+		t.Fatalf("expected status %s, got %s", TaskQueueStatusSuccess, dbTask.GetStatus())
+	}
+	if dbTask.GetWorkerID() != "runner-1" {
+		t.Fatalf("expected worker ID runner-1 to be kept after completion, got %s", dbTask.GetWorkerID())
+	}
+	if isNullTime(dbTask.GetClaimedAt()) {
+		t.Fatal("expected claimed at to be kept after completion")
+	}
+}
+
+func TestTaskQueueRunner_StartRegistersWorker(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.GetDB().Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runner := NewTaskQueueRunner(store, TaskQueueRunnerOptions{
+		IntervalSeconds: 1,
+		QueueName:       "emails",
+		MaxConcurrency:  3,
+		WorkerID:        "runner-1",
+		Version:         "v2.0.0",
+	})
+
+	runner.Start(ctx)
+
+	worker, err := store.WorkerFindByID(ctx, "runner-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if worker == nil {
+		t.Fatal("expected the runner to be registered as a worker on start")
+	}
+	if worker.GetQueueName() != "emails" || worker.GetMaxConcurrency() != 3 || worker.GetVersion() != "v2.0.0" {
+		t.Fatalf("expected emails/3/v2.0.0, got %s/%d/%s", worker.GetQueueName(), worker.GetMaxConcurrency(), worker.GetVersion())
+	}
+
+	runner.Stop()
+
+	worker, err = store.WorkerFindByID(ctx, "runner-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if worker != nil {
+		t.Fatal("expected the runner to be deregistered on stop")
+	}
+}
+
+// claimCountingStore records how the runner claims its tasks
+type claimCountingStore struct {
+	*Store
+	mu             sync.Mutex
+	batchSizes     []int
+	claimNextCalls int
+}
+
+func (s *claimCountingStore) TaskQueueClaimBatch(ctx context.Context, queueName string, n int) ([]TaskQueueInterface, error) {
+	s.mu.Lock()
+	s.batchSizes = append(s.batchSizes, n)
+	s.mu.Unlock()
+	return s.Store.TaskQueueClaimBatch(ctx, queueName, n)
+}
+
+func (s *claimCountingStore) TaskQueueClaimNext(ctx context.Context, queueName string) (TaskQueueInterface, error) {
+	s.mu.Lock()
+	s.claimNextCalls++
+	s.mu.Unlock()
+	return s.Store.TaskQueueClaimNext(ctx, queueName)
+}
+
+func TestTaskQueueRunner_ConcurrentClaimsInBatches(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.GetDB().Close()
+
+	ctx := context.Background()
+
+	handler := &delayedHandler{delay: 50 * time.Millisecond}
+	if err := store.TaskHandlerAdd(ctx, handler, true); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err := store.TaskDefinitionEnqueueByAlias(ctx, DefaultQueueName, handler.Alias(), map[string]any{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	countingStore := &claimCountingStore{Store: store}
+	runner := NewTaskQueueRunner(countingStore, TaskQueueRunnerOptions{
+		QueueName:      DefaultQueueName,
+		MaxConcurrency: 3,
+	})
+
+	if err := runner.RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	success, err := store.TaskQueueCount(ctx, TaskQueueQuery().SetStatus(TaskQueueStatusSuccess))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if success != 5 {
+		t.Fatalf("expected 5 successful tasks, got %d", success)
+	}
+
+	countingStore.mu.Lock()
+	defer countingStore.mu.Unlock()
+	if countingStore.claimNextCalls != 0 {
+		t.Fatalf("expected no single claims, got %d", countingStore.claimNextCalls)
+	}
+	if len(countingStore.batchSizes) == 0 || countingStore.batchSizes[0] != 3 {
+		t.Fatalf("expected the first batch to fill all 3 slots, got %v", countingStore.batchSizes)
+	}
+	if len(countingStore.batchSizes) >= 5 {
+		t.Fatalf("expected fewer claims than tasks, got %v", countingStore.batchSizes)
+	}
+}
+
+func TestTaskQueueRunner_WakesUpOnEnqueue(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.GetDB().Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := store.TaskHandlerAdd(ctx, newTestTaskHandler(), true); err != nil {
+		t.Fatal(err)
+	}
+
+	// Polling alone would not pick the task up within the test
+	runner := NewTaskQueueRunner(store, TaskQueueRunnerOptions{
+		IntervalSeconds: 60,
+		QueueName:       "emails",
+	})
+	runner.Start(ctx)
+	defer runner.Stop()
+
+	// Let the first run find the queue empty
+	time.Sleep(100 * time.Millisecond)
+
+	queuedTask, err := store.TaskDefinitionEnqueueByAlias(ctx, "emails", newTestTaskHandler().Alias(), map[string]any{
+		"completeWithSuccess": "yes",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		task, err := store.TaskQueueFindByID(ctx, queuedTask.GetID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task.GetStatus() == TaskQueueStatusSuccess {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the enqueued task to wake up the runner, status is %s", task.GetStatus())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestTaskQueueRunner_AdaptiveInterval(t *testing.T) {
+	store, err := initStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.GetDB().Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := store.TaskHandlerAdd(ctx, newTestTaskHandler(), true); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewTaskQueueRunner(store, TaskQueueRunnerOptions{
+		IntervalSeconds:    1,
+		MaxIntervalSeconds: 8,
+		QueueName:          "emails",
+	})
+
+	if runner.CurrentInterval() != time.Second {
+		t.Fatalf("expected the minimum interval before starting, got %v", runner.CurrentInterval())
+	}
+
+	runner.Start(ctx)
+	defer runner.Stop()
+
+	// The second poll finds the queue idle again, so the interval doubles
+	time.Sleep(1300 * time.Millisecond)
+	if runner.CurrentInterval() != 2*time.Second {
+		t.Fatalf("expected the interval to back off to 2s, got %v", runner.CurrentInterval())
+	}
+
+	// Work resets the interval
+	_, err = store.TaskDefinitionEnqueueByAlias(ctx, "emails", newTestTaskHandler().Alias(), map[string]any{
+		"completeWithSuccess": "yes",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if runner.CurrentInterval() != time.Second {
+		t.Fatalf("expected the interval to be reset to 1s, got %v", runner.CurrentInterval())
+	}
+}
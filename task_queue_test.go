@@ -2,6 +2,7 @@ package taskstore
 
 import (
 	"encoding/json"
+	"slices"
 	"strings"
 	"testing"
 
@@ -466,6 +467,91 @@ func TestTaskQueue_SettersAndGetters(t *testing.T) {
 		t.Errorf("CompletedAt: Expected %s, got %s", testCompletedAt, queue.GetCompletedAt().Format("2006-01-02 15:04:05"))
 	}
 
+	testAvailableAt := "2023-01-01 12:30:00"
+	queue.SetAvailableAt(carbon.Parse(testAvailableAt, carbon.UTC).StdTime())
+	if queue.GetAvailableAt().Format("2006-01-02 15:04:05") != testAvailableAt {
+		t.Errorf("AvailableAt: Expected %s, got %s", testAvailableAt, queue.GetAvailableAt().Format("2006-01-02 15:04:05"))
+	}
+
+	// Test Priority
+	queue.SetPriority(10)
+	if queue.GetPriority() != 10 {
+		t.Errorf("Priority: Expected 10, got %d", queue.GetPriority())
+	}
+
+	// Test RetryPolicy
+	if queue.GetRetryPolicy() != nil {
+		t.Errorf("RetryPolicy: Expected nil, got %v", queue.GetRetryPolicy())
+	}
+	testRetryPolicy := &RetryPolicy{MaxAttempts: 3, Backoff: RetryBackoffExponential, DelaySeconds: 10}
+	queue.SetRetryPolicy(testRetryPolicy)
+	if queue.GetRetryPolicy() == nil || *queue.GetRetryPolicy() != *testRetryPolicy {
+		t.Errorf("RetryPolicy: Expected %v, got %v", testRetryPolicy, queue.GetRetryPolicy())
+	}
+
+	// Test DependsOn
+	if len(queue.GetDependsOn()) != 0 {
+		t.Errorf("DependsOn: Expected no dependencies, got %v", queue.GetDependsOn())
+	}
+	queue.SetDependsOn([]string{"parent-1", "parent-2"})
+	if !slices.Equal(queue.GetDependsOn(), []string{"parent-1", "parent-2"}) {
+		t.Errorf("DependsOn: Expected [parent-1 parent-2], got %v", queue.GetDependsOn())
+	}
+
+	// Test DependencyFailurePolicy
+	if queue.GetDependencyFailurePolicy() != DependencyFailureCancel {
+		t.Errorf("DependencyFailurePolicy: Expected %s, got %s", DependencyFailureCancel, queue.GetDependencyFailurePolicy())
+	}
+	queue.SetDependencyFailurePolicy(DependencyFailureBlock)
+	if queue.GetDependencyFailurePolicy() != DependencyFailureBlock {
+		t.Errorf("DependencyFailurePolicy: Expected %s, got %s", DependencyFailureBlock, queue.GetDependencyFailurePolicy())
+	}
+
+	// Test DeadLetterQueueName
+	queue.SetDeadLetterQueueName("poison")
+	if queue.GetDeadLetterQueueName() != "poison" {
+		t.Errorf("DeadLetterQueueName: Expected poison, got %s", queue.GetDeadLetterQueueName())
+	}
+
+	// Test TimeoutSeconds
+	queue.SetTimeoutSeconds(30)
+	if queue.GetTimeoutSeconds() != 30 {
+		t.Errorf("TimeoutSeconds: Expected 30, got %d", queue.GetTimeoutSeconds())
+	}
+
+	// Test LeaseOwner
+	queue.SetLeaseOwner("worker-1")
+	if queue.GetLeaseOwner() != "worker-1" {
+		t.Errorf("LeaseOwner: Expected worker-1, got %s", queue.GetLeaseOwner())
+	}
+
+	// Test LeaseExpiresAt
+	testLeaseExpiresAt := "2023-01-05 14:00:00"
+	queue.SetLeaseExpiresAt(carbon.Parse(testLeaseExpiresAt, carbon.UTC).StdTime())
+	if queue.GetLeaseExpiresAt().Format("2006-01-02 15:04:05") != testLeaseExpiresAt {
+		t.Errorf("LeaseExpiresAt: Expected %s, got %s", testLeaseExpiresAt, queue.GetLeaseExpiresAt().Format("2006-01-02 15:04:05"))
+	}
+
+	// Test WorkerID
+	queue.SetWorkerID("worker-2")
+	if queue.GetWorkerID() != "worker-2" {
+		t.Errorf("WorkerID: Expected worker-2, got %s", queue.GetWorkerID())
+	}
+
+	// Test ClaimedAt
+	testClaimedAt := "2023-01-05 13:30:00"
+	queue.SetClaimedAt(carbon.Parse(testClaimedAt, carbon.UTC).StdTime())
+	if queue.GetClaimedAt().Format("2006-01-02 15:04:05") != testClaimedAt {
+		t.Errorf("ClaimedAt: Expected %s, got %s", testClaimedAt, queue.GetClaimedAt().Format("2006-01-02 15:04:05"))
+	}
+
+	// Test DeadLetteredAt
+	testDeadLetteredAt := "2023-01-04 13:00:00"
+	queue.SetDeadLetteredAt(carbon.Parse(testDeadLetteredAt, carbon.UTC).StdTime())
+	if queue.GetDeadLetteredAt().Format("2006-01-02 15:04:05") != testDeadLetteredAt {
+		t.Errorf("DeadLetteredAt: Expected %s, got %s", testDeadLetteredAt, queue.GetDeadLetteredAt().Format("2006-01-02 15:04:05"))
+	}
+
 	testDeletedAt := "2023-01-03 12:00:00"
 	queue.SetSoftDeletedAt(carbon.Parse(testDeletedAt, carbon.UTC).StdTime())
 	if queue.GetSoftDeletedAt().Format("2006-01-02 15:04:05") != testDeletedAt {
@@ -0,0 +1,202 @@
+package taskstore
+
+import (
+	"time"
+
+	"github.com/dracory/neat/database/orm"
+	"github.com/dromara/carbon/v2"
+)
+
+// == INTERFACE =================================================================
+
+// WorkerInterface is a process claiming and running queued tasks, i.e. a
+// TaskQueueRunner. Workers register in the workers table when started,
+// heartbeat while running and deregister when stopped.
+type WorkerInterface interface {
+	GetCreatedAt() time.Time
+	GetCreatedAtCarbon() *carbon.Carbon
+	SetCreatedAt(createdAt time.Time) WorkerInterface
+
+	GetHeartbeatAt() time.Time
+	GetHeartbeatAtCarbon() *carbon.Carbon
+	SetHeartbeatAt(heartbeatAt time.Time) WorkerInterface
+
+	GetID() string
+	SetID(id string) WorkerInterface
+
+	GetInFlight() int
+	SetInFlight(inFlight int) WorkerInterface
+
+	GetMaxConcurrency() int
+	SetMaxConcurrency(maxConcurrency int) WorkerInterface
+
+	GetQueueName() string
+	SetQueueName(queueName string) WorkerInterface
+
+	GetStartedAt() time.Time
+	GetStartedAtCarbon() *carbon.Carbon
+	SetStartedAt(startedAt time.Time) WorkerInterface
+
+	GetUpdatedAt() time.Time
+	GetUpdatedAtCarbon() *carbon.Carbon
+	SetUpdatedAt(updatedAt time.Time) WorkerInterface
+
+	GetVersion() string
+	SetVersion(version string) WorkerInterface
+}
+
+// == TYPE =====================================================================
+
+type worker struct {
+	IDField             string    `db:"id"`
+	QueueNameField      string    `db:"queue_name"`
+	MaxConcurrencyField int       `db:"max_concurrency"`
+	InFlightField       int       `db:"in_flight"`
+	VersionField        string    `db:"version"`
+	StartedAtField      time.Time `db:"started_at"`
+	HeartbeatAtField    time.Time `db:"heartbeat_at"`
+
+	CreatedAtField orm.CreatedAt
+	UpdatedAtField orm.UpdatedAt
+}
+
+var _ WorkerInterface = (*worker)(nil)
+
+// == CONSTRUCTORS =============================================================
+
+// NewWorker creates a new worker with the given ID.
+// If no ID is provided, one is generated from the host name, the process
+// ID and a random suffix.
+func NewWorker(id ...string) WorkerInterface {
+	workerID := ""
+	if len(id) > 0 {
+		workerID = id[0]
+	}
+	if workerID == "" {
+		workerID = newWorkerID()
+	}
+
+	o := &worker{}
+
+	o.SetID(workerID).
+		SetQueueName(DefaultQueueName).
+		SetMaxConcurrency(1).
+		SetInFlight(0).
+		SetVersion("").
+		SetStartedAt(carbon.Now(carbon.UTC).StdTime()).
+		SetHeartbeatAt(carbon.Now(carbon.UTC).StdTime()).
+		SetCreatedAt(carbon.Now(carbon.UTC).StdTime()).
+		SetUpdatedAt(carbon.Now(carbon.UTC).StdTime())
+
+	return o
+}
+
+// == SETTERS AND GETTERS ======================================================
+
+func (o *worker) GetCreatedAt() time.Time {
+	return o.CreatedAtField.CreatedAt
+}
+
+func (o *worker) GetCreatedAtCarbon() *carbon.Carbon {
+	return carbon.CreateFromStdTime(o.CreatedAtField.CreatedAt)
+}
+
+func (o *worker) SetCreatedAt(createdAt time.Time) WorkerInterface {
+	o.CreatedAtField.CreatedAt = createdAt
+	return o
+}
+
+// GetHeartbeatAt returns when the worker last reported it is alive
+func (o *worker) GetHeartbeatAt() time.Time {
+	return o.HeartbeatAtField
+}
+
+func (o *worker) GetHeartbeatAtCarbon() *carbon.Carbon {
+	return carbon.CreateFromStdTime(o.HeartbeatAtField)
+}
+
+func (o *worker) SetHeartbeatAt(heartbeatAt time.Time) WorkerInterface {
+	o.HeartbeatAtField = heartbeatAt
+	return o
+}
+
+// GetID returns the ID of the worker, as recorded on the tasks it claims
+// (see TaskQueueInterface.GetWorkerID)
+func (o *worker) GetID() string {
+	return o.IDField
+}
+
+func (o *worker) SetID(id string) WorkerInterface {
+	o.IDField = id
+	return o
+}
+
+// GetInFlight returns the number of tasks the worker was running at its
+// last heartbeat
+func (o *worker) GetInFlight() int {
+	return o.InFlightField
+}
+
+func (o *worker) SetInFlight(inFlight int) WorkerInterface {
+	o.InFlightField = inFlight
+	return o
+}
+
+// GetMaxConcurrency returns the number of tasks the worker runs at most
+// at the same time
+func (o *worker) GetMaxConcurrency() int {
+	return o.MaxConcurrencyField
+}
+
+func (o *worker) SetMaxConcurrency(maxConcurrency int) WorkerInterface {
+	o.MaxConcurrencyField = maxConcurrency
+	return o
+}
+
+// GetQueueName returns the name of the queue the worker claims tasks from
+func (o *worker) GetQueueName() string {
+	return o.QueueNameField
+}
+
+func (o *worker) SetQueueName(queueName string) WorkerInterface {
+	o.QueueNameField = queueName
+	return o
+}
+
+// GetStartedAt returns when the worker registered
+func (o *worker) GetStartedAt() time.Time {
+	return o.StartedAtField
+}
+
+func (o *worker) GetStartedAtCarbon() *carbon.Carbon {
+	return carbon.CreateFromStdTime(o.StartedAtField)
+}
+
+func (o *worker) SetStartedAt(startedAt time.Time) WorkerInterface {
+	o.StartedAtField = startedAt
+	return o
+}
+
+func (o *worker) GetUpdatedAt() time.Time {
+	return o.UpdatedAtField.UpdatedAt
+}
+
+func (o *worker) GetUpdatedAtCarbon() *carbon.Carbon {
+	return carbon.CreateFromStdTime(o.UpdatedAtField.UpdatedAt)
+}
+
+func (o *worker) SetUpdatedAt(updatedAt time.Time) WorkerInterface {
+	o.UpdatedAtField.UpdatedAt = updatedAt
+	return o
+}
+
+// GetVersion returns the version of the application the worker runs,
+// as set with TaskQueueRunnerOptions.Version
+func (o *worker) GetVersion() string {
+	return o.VersionField
+}
+
+func (o *worker) SetVersion(version string) WorkerInterface {
+	o.VersionField = version
+	return o
+}
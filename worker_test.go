@@ -0,0 +1,67 @@
+package taskstore
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewWorker(t *testing.T) {
+	worker := NewWorker()
+
+	if worker.GetID() == "" {
+		t.Error("Expected ID to be generated")
+	}
+	if worker.GetQueueName() != DefaultQueueName {
+		t.Errorf("Expected queue name %s, got %s", DefaultQueueName, worker.GetQueueName())
+	}
+	if worker.GetMaxConcurrency() != 1 {
+		t.Errorf("Expected max concurrency 1, got %d", worker.GetMaxConcurrency())
+	}
+	if worker.GetStartedAt().IsZero() || worker.GetHeartbeatAt().IsZero() {
+		t.Error("Expected started at and heartbeat at to be set")
+	}
+
+	if NewWorker("worker-1").GetID() != "worker-1" {
+		t.Error("Expected the worker ID to be used")
+	}
+	if NewWorker().GetID() == worker.GetID() {
+		t.Error("Expected generated worker IDs to be unique")
+	}
+}
+
+func TestWorker_SettersAndGetters(t *testing.T) {
+	startedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	heartbeatAt := time.Date(2024, 1, 2, 3, 5, 5, 0, time.UTC)
+
+	worker := NewWorker().
+		SetID("worker-1").
+		SetQueueName("emails").
+		SetMaxConcurrency(5).
+		SetInFlight(3).
+		SetVersion("v1.2.3").
+		SetStartedAt(startedAt).
+		SetHeartbeatAt(heartbeatAt)
+
+	if worker.GetID() != "worker-1" {
+		t.Errorf("ID: Expected worker-1, got %s", worker.GetID())
+	}
+	if worker.GetQueueName() != "emails" {
+		t.Errorf("QueueName: Expected emails, got %s", worker.GetQueueName())
+	}
+	if worker.GetMaxConcurrency() != 5 || worker.GetInFlight() != 3 {
+		t.Errorf("Concurrency: Expected 5/3, got %d/%d", worker.GetMaxConcurrency(), worker.GetInFlight())
+	}
+	if worker.GetVersion() != "v1.2.3" {
+		t.Errorf("Version: Expected v1.2.3, got %s", worker.GetVersion())
+	}
+	if !worker.GetStartedAt().Equal(startedAt) {
+		t.Errorf("StartedAt: Expected %v, got %v", startedAt, worker.GetStartedAt())
+	}
+	if !worker.GetHeartbeatAt().Equal(heartbeatAt) {
+		t.Errorf("HeartbeatAt: Expected %v, got %v", heartbeatAt, worker.GetHeartbeatAt())
+	}
+	if !strings.HasPrefix(worker.GetHeartbeatAtCarbon().ToDateTimeString(), "2024-01-02 03:05:05") {
+		t.Errorf("HeartbeatAtCarbon: Expected 2024-01-02 03:05:05, got %s", worker.GetHeartbeatAtCarbon().ToDateTimeString())
+	}
+}